@@ -110,6 +110,10 @@ type CinderSpecBase struct {
 	// TopologyRef to apply the Topology defined by the associated CR referenced
 	// by name
 	TopologyRef *topologyv1.TopoRef `json:"topologyRef,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Notifications - configures the notifications emitted by all Cinder services
+	Notifications Notifications `json:"notifications,omitempty"`
 }
 
 // CinderSpecCore the same as CinderSpec without ContainerImage references
@@ -167,6 +171,13 @@ type CinderStatus struct {
 	// TransportURLSecret - Secret containing RabbitMQ transportURL
 	TransportURLSecret string `json:"transportURLSecret,omitempty"`
 
+	// NotificationURLSecret - Secret containing the RabbitMQ transportURL used for notifications
+	NotificationURLSecret string `json:"notificationURLSecret,omitempty"`
+
+	// TLS - Whether the connections of the Cinder services to the database and
+	// the messaging bus use TLS
+	TLS CinderTLSStatus `json:"tls,omitempty"`
+
 	// API endpoints
 	APIEndpoints map[string]map[string]string `json:"apiEndpoints,omitempty"`
 
@@ -191,11 +202,29 @@ type CinderStatus struct {
 	// ReadyCounts of Cinder Volume instances
 	CinderVolumesReadyCounts map[string]int32 `json:"cinderVolumesReadyCounts,omitempty"`
 
+	// CinderVolumesBackends - state of the backends of each of the Cinder
+	// Volume instances, as reported in their status
+	CinderVolumesBackends map[string][]CinderVolumeBackendStatus `json:"cinderVolumesBackends,omitempty"`
+
 	// ObservedGeneration - the most recent generation observed for this service.
 	// If the observed generation is different than the spec generation, then the
 	// controller has not started processing the latest changes, and the status
 	// and its conditions are likely stale.
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Image - image of the CinderAPI the db sync job and the db purge cronjob
+	// run with
+	Image *CinderImageStatus `json:"image,omitempty"`
+}
+
+// CinderTLSStatus defines the observed TLS state of the connections of the
+// Cinder services to the services they depend on
+type CinderTLSStatus struct {
+	// Database - Whether the connections to the database use TLS
+	Database bool `json:"database,omitempty"`
+
+	// Messaging - Whether the connections to the RabbitMQ messaging bus use TLS
+	Messaging bool `json:"messaging,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -225,6 +254,30 @@ type DBPurge struct {
 	Schedule string `json:"schedule"`
 }
 
+// Notifications defines the notifications emitted by the Cinder services
+type Notifications struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=false
+	// Enabled - emit notifications, when false the noop driver is used
+	Enabled bool `json:"enabled"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=messagingv2
+	// +kubebuilder:validation:Enum=messagingv2;messaging;log;routing;test
+	// Driver - oslo.messaging driver used to emit the notifications
+	Driver string `json:"driver"`
+
+	// +kubebuilder:validation:Optional
+	// +listType=set
+	// Topics - topics the notifications are sent to, defaults to notifications
+	Topics []string `json:"topics,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// RabbitMqClusterName - RabbitMQ instance the notifications are sent to.
+	// When empty the notifications use the same transportURL as the RPCs.
+	RabbitMqClusterName string `json:"rabbitMqClusterName,omitempty"`
+}
+
 //+kubebuilder:object:root=true
 
 // CinderList contains a list of Cinder
@@ -167,7 +167,10 @@ func (r *Cinder) ValidateCreate() (admission.Warnings, error) {
 			r.Name, allErrs)
 	}
 
-	return nil, nil
+	warnings := r.Spec.CinderScheduler.GetWarnings(basePath.Child("cinderScheduler"))
+	warnings = append(warnings, r.Spec.GetAvailabilityZoneWarnings(basePath)...)
+	warnings = append(warnings, r.Spec.CinderAPI.GetAuthStrategyWarnings(basePath.Child("cinderAPI"))...)
+	return warnings, nil
 }
 
 // ValidateCreate - Exported function wrapping non-exported validate functions,
@@ -180,6 +183,29 @@ func (spec *CinderSpec) ValidateCreate(basePath *field.Path, namespace string) f
 		basePath.Child("cinderAPI").Child("override").Child("service"),
 		spec.CinderAPI.Override.Service)...)
 
+	allErrs = append(allErrs, spec.CinderAPI.ValidateAPIServer(basePath.Child("cinderAPI"))...)
+	allErrs = append(allErrs, spec.CinderAPI.ValidateProbes(basePath.Child("cinderAPI"))...)
+	allErrs = append(allErrs, spec.CinderAPI.ValidateAudit(spec.Notifications, basePath.Child("cinderAPI"))...)
+	allErrs = append(allErrs, spec.CinderAPI.ValidateAuthStrategy(basePath.Child("cinderAPI"))...)
+	allErrs = append(allErrs, spec.CinderAPI.ValidateInternalClientAuth(basePath.Child("cinderAPI"))...)
+	allErrs = append(allErrs, spec.CinderAPI.ValidateEndpointNetworks(basePath.Child("cinderAPI"))...)
+	allErrs = append(allErrs, spec.CinderAPI.Override.ValidateIPFamilies(basePath.Child("cinderAPI", "override"))...)
+	allErrs = append(allErrs, spec.CinderAPI.Policy.Validate(basePath.Child("cinderAPI"))...)
+
+	for name, volume := range spec.CinderVolumes {
+		path := basePath.Child("cinderVolumes").Key(name)
+		allErrs = append(allErrs, volume.ValidateReplication(path)...)
+		allErrs = append(allErrs, volume.ValidateLVM(path)...)
+		allErrs = append(allErrs, volume.AvailabilityZone.Validate(volume.NodeSelector, path)...)
+		allErrs = append(allErrs, volume.ValidateStorageNetwork(volume.StorageNetwork, path)...)
+	}
+	allErrs = append(allErrs, spec.CinderBackup.AvailabilityZone.Validate(
+		spec.CinderBackup.NodeSelector, basePath.Child("cinderBackup"))...)
+	allErrs = append(allErrs, spec.CinderBackup.ValidateStorageNetwork(
+		spec.CinderBackup.StorageNetwork, basePath.Child("cinderBackup"))...)
+	allErrs = append(allErrs, spec.CinderScheduler.SchedulerPolicy.Validate(
+		basePath.Child("cinderScheduler"), maps.Keys(spec.CinderVolumes))...)
+
 	allErrs = append(allErrs, spec.ValidateCinderTopology(basePath, namespace)...)
 	return allErrs
 }
@@ -192,6 +218,29 @@ func (spec *CinderSpecCore) ValidateCreate(basePath *field.Path, namespace strin
 		basePath.Child("cinderAPI").Child("override").Child("service"),
 		spec.CinderAPI.Override.Service)...)
 
+	allErrs = append(allErrs, spec.CinderAPI.ValidateAPIServer(basePath.Child("cinderAPI"))...)
+	allErrs = append(allErrs, spec.CinderAPI.ValidateProbes(basePath.Child("cinderAPI"))...)
+	allErrs = append(allErrs, spec.CinderAPI.ValidateAudit(spec.Notifications, basePath.Child("cinderAPI"))...)
+	allErrs = append(allErrs, spec.CinderAPI.ValidateAuthStrategy(basePath.Child("cinderAPI"))...)
+	allErrs = append(allErrs, spec.CinderAPI.ValidateInternalClientAuth(basePath.Child("cinderAPI"))...)
+	allErrs = append(allErrs, spec.CinderAPI.ValidateEndpointNetworks(basePath.Child("cinderAPI"))...)
+	allErrs = append(allErrs, spec.CinderAPI.Override.ValidateIPFamilies(basePath.Child("cinderAPI", "override"))...)
+	allErrs = append(allErrs, spec.CinderAPI.Policy.Validate(basePath.Child("cinderAPI"))...)
+
+	for name, volume := range spec.CinderVolumes {
+		path := basePath.Child("cinderVolumes").Key(name)
+		allErrs = append(allErrs, volume.ValidateReplication(path)...)
+		allErrs = append(allErrs, volume.ValidateLVM(path)...)
+		allErrs = append(allErrs, volume.AvailabilityZone.Validate(volume.NodeSelector, path)...)
+		allErrs = append(allErrs, volume.ValidateStorageNetwork(volume.StorageNetwork, path)...)
+	}
+	allErrs = append(allErrs, spec.CinderBackup.AvailabilityZone.Validate(
+		spec.CinderBackup.NodeSelector, basePath.Child("cinderBackup"))...)
+	allErrs = append(allErrs, spec.CinderBackup.ValidateStorageNetwork(
+		spec.CinderBackup.StorageNetwork, basePath.Child("cinderBackup"))...)
+	allErrs = append(allErrs, spec.CinderScheduler.SchedulerPolicy.Validate(
+		basePath.Child("cinderScheduler"), maps.Keys(spec.CinderVolumes))...)
+
 	allErrs = append(allErrs, spec.ValidateCinderTopology(basePath, namespace)...)
 	return allErrs
 }
@@ -235,7 +284,10 @@ func (r *Cinder) ValidateUpdate(old runtime.Object) (admission.Warnings, error)
 			r.Name, allErrs)
 	}
 
-	return nil, nil
+	warnings := r.Spec.CinderScheduler.GetWarnings(basePath.Child("cinderScheduler"))
+	warnings = append(warnings, r.Spec.GetAvailabilityZoneWarnings(basePath)...)
+	warnings = append(warnings, r.Spec.CinderAPI.GetAuthStrategyWarnings(basePath.Child("cinderAPI"))...)
+	return warnings, nil
 }
 
 // ValidateUpdate - Exported function wrapping non-exported validate functions,
@@ -248,6 +300,29 @@ func (spec *CinderSpec) ValidateUpdate(old CinderSpec, basePath *field.Path, nam
 		basePath.Child("cinderAPI").Child("override").Child("service"),
 		spec.CinderAPI.Override.Service)...)
 
+	allErrs = append(allErrs, spec.CinderAPI.ValidateAPIServer(basePath.Child("cinderAPI"))...)
+	allErrs = append(allErrs, spec.CinderAPI.ValidateProbes(basePath.Child("cinderAPI"))...)
+	allErrs = append(allErrs, spec.CinderAPI.ValidateAudit(spec.Notifications, basePath.Child("cinderAPI"))...)
+	allErrs = append(allErrs, spec.CinderAPI.ValidateAuthStrategy(basePath.Child("cinderAPI"))...)
+	allErrs = append(allErrs, spec.CinderAPI.ValidateInternalClientAuth(basePath.Child("cinderAPI"))...)
+	allErrs = append(allErrs, spec.CinderAPI.ValidateEndpointNetworks(basePath.Child("cinderAPI"))...)
+	allErrs = append(allErrs, spec.CinderAPI.Override.ValidateIPFamilies(basePath.Child("cinderAPI", "override"))...)
+	allErrs = append(allErrs, spec.CinderAPI.Policy.Validate(basePath.Child("cinderAPI"))...)
+
+	for name, volume := range spec.CinderVolumes {
+		path := basePath.Child("cinderVolumes").Key(name)
+		allErrs = append(allErrs, volume.ValidateReplication(path)...)
+		allErrs = append(allErrs, volume.ValidateLVM(path)...)
+		allErrs = append(allErrs, volume.AvailabilityZone.Validate(volume.NodeSelector, path)...)
+		allErrs = append(allErrs, volume.ValidateStorageNetwork(volume.StorageNetwork, path)...)
+	}
+	allErrs = append(allErrs, spec.CinderBackup.AvailabilityZone.Validate(
+		spec.CinderBackup.NodeSelector, basePath.Child("cinderBackup"))...)
+	allErrs = append(allErrs, spec.CinderBackup.ValidateStorageNetwork(
+		spec.CinderBackup.StorageNetwork, basePath.Child("cinderBackup"))...)
+	allErrs = append(allErrs, spec.CinderScheduler.SchedulerPolicy.Validate(
+		basePath.Child("cinderScheduler"), maps.Keys(spec.CinderVolumes))...)
+
 	allErrs = append(allErrs, spec.ValidateCinderTopology(basePath, namespace)...)
 	return allErrs
 }
@@ -260,6 +335,29 @@ func (spec *CinderSpecCore) ValidateUpdate(old CinderSpecCore, basePath *field.P
 		basePath.Child("cinderAPI").Child("override").Child("service"),
 		spec.CinderAPI.Override.Service)...)
 
+	allErrs = append(allErrs, spec.CinderAPI.ValidateAPIServer(basePath.Child("cinderAPI"))...)
+	allErrs = append(allErrs, spec.CinderAPI.ValidateProbes(basePath.Child("cinderAPI"))...)
+	allErrs = append(allErrs, spec.CinderAPI.ValidateAudit(spec.Notifications, basePath.Child("cinderAPI"))...)
+	allErrs = append(allErrs, spec.CinderAPI.ValidateAuthStrategy(basePath.Child("cinderAPI"))...)
+	allErrs = append(allErrs, spec.CinderAPI.ValidateInternalClientAuth(basePath.Child("cinderAPI"))...)
+	allErrs = append(allErrs, spec.CinderAPI.ValidateEndpointNetworks(basePath.Child("cinderAPI"))...)
+	allErrs = append(allErrs, spec.CinderAPI.Override.ValidateIPFamilies(basePath.Child("cinderAPI", "override"))...)
+	allErrs = append(allErrs, spec.CinderAPI.Policy.Validate(basePath.Child("cinderAPI"))...)
+
+	for name, volume := range spec.CinderVolumes {
+		path := basePath.Child("cinderVolumes").Key(name)
+		allErrs = append(allErrs, volume.ValidateReplication(path)...)
+		allErrs = append(allErrs, volume.ValidateLVM(path)...)
+		allErrs = append(allErrs, volume.AvailabilityZone.Validate(volume.NodeSelector, path)...)
+		allErrs = append(allErrs, volume.ValidateStorageNetwork(volume.StorageNetwork, path)...)
+	}
+	allErrs = append(allErrs, spec.CinderBackup.AvailabilityZone.Validate(
+		spec.CinderBackup.NodeSelector, basePath.Child("cinderBackup"))...)
+	allErrs = append(allErrs, spec.CinderBackup.ValidateStorageNetwork(
+		spec.CinderBackup.StorageNetwork, basePath.Child("cinderBackup"))...)
+	allErrs = append(allErrs, spec.CinderScheduler.SchedulerPolicy.Validate(
+		basePath.Child("cinderScheduler"), maps.Keys(spec.CinderVolumes))...)
+
 	allErrs = append(allErrs, spec.ValidateCinderTopology(basePath, namespace)...)
 	return allErrs
 }
@@ -387,3 +485,25 @@ func (spec *CinderSpec) ValidateCinderTopology(basePath *field.Path, namespace s
 	}
 	return allErrs
 }
+
+// GetAvailabilityZoneWarnings - returns a warning when services are placed in
+// availability zones while the volumes created without a zone still go to the
+// default one, which may have no back-end
+func (spec *CinderSpec) GetAvailabilityZoneWarnings(basePath *field.Path) admission.Warnings {
+	var warnings admission.Warnings
+	filter := spec.CinderScheduler.AvailabilityZoneFilter
+	if filter != nil && filter.DefaultAvailabilityZone != "" {
+		return warnings
+	}
+
+	zoned := spec.CinderBackup.AvailabilityZone != nil
+	for _, volume := range spec.CinderVolumes {
+		zoned = zoned || volume.AvailabilityZone != nil
+	}
+	if zoned {
+		warnings = append(warnings, fmt.Sprintf(
+			"%s is not set, volumes created without an availability zone go to the nova zone, which may have no back-end",
+			basePath.Child("cinderScheduler", "availabilityZoneFilter", "defaultAvailabilityZone")))
+	}
+	return warnings
+}
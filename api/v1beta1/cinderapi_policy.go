@@ -0,0 +1,310 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/yaml"
+)
+
+// CinderAPIPolicy defines the oslo.policy rules of the API
+type CinderAPIPolicy struct {
+	// +kubebuilder:validation:Optional
+	// Rules - inline policy.yaml content. Only the rules that differ from the
+	// defaults need to be present. Mutually exclusive with ConfigMapRef.
+	Rules string `json:"rules,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// ConfigMapRef - ConfigMap key holding the policy.yaml content. Mutually
+	// exclusive with Rules.
+	ConfigMapRef *PolicyConfigMapRef `json:"configMapRef,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=true
+	// SecureRBAC - enable the new secure RBAC defaults (enforce_scope and
+	// enforce_new_defaults)
+	SecureRBAC bool `json:"secureRBAC"`
+}
+
+// PolicyConfigMapRef - reference to the ConfigMap key holding a policy.yaml
+type PolicyConfigMapRef struct {
+	// +kubebuilder:validation:Required
+	// Name of the ConfigMap
+	Name string `json:"name"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=policy.yaml
+	// Key of the ConfigMap holding the policy.yaml content
+	Key string `json:"key"`
+}
+
+// policyRuleReference matches the references to other rules in a check string
+var policyRuleReference = regexp.MustCompile(`rule:([A-Za-z0-9_:.-]+)`)
+
+// PolicyRules - rules and aliases defined by Cinder that can be overridden
+// in a policy.yaml
+var PolicyRules = map[string]struct{}{
+	// base rules
+	"context_is_admin":                    {},
+	"admin_or_owner":                      {},
+	"admin_api":                           {},
+	"system_or_domain_or_project_admin":   {},
+	"xena_system_admin_or_project_reader": {},
+	"xena_system_admin_or_project_member": {},
+	"xena_system_admin_or_project_admin":  {},
+	"project_reader":                      {},
+	"project_member":                      {},
+	"project_admin":                       {},
+	"project_reader_or_admin":             {},
+	"project_member_or_admin":             {},
+	// attachments
+	"volume:attachment_create":           {},
+	"volume:attachment_update":           {},
+	"volume:attachment_delete":           {},
+	"volume:attachment_complete":         {},
+	"volume:multiattach_bootable_volume": {},
+	// backups
+	"backup:get_all":                  {},
+	"backup:backup_project_attribute": {},
+	"backup:get":                      {},
+	"backup:update":                   {},
+	"backup:create":                   {},
+	"backup:delete":                   {},
+	"backup:restore":                  {},
+	"backup:backup-import":            {},
+	"backup:export-import":            {},
+	"backup:encryption_key_id":        {},
+	"volume_extension:backup_admin_actions:reset_status": {},
+	"volume_extension:backup_admin_actions:force_delete": {},
+	// clusters
+	"clusters:get_all": {},
+	"clusters:get":     {},
+	"clusters:update":  {},
+	// default types
+	"volume_extension:default_set_or_update": {},
+	"volume_extension:default_get":           {},
+	"volume_extension:default_get_all":       {},
+	"volume_extension:default_unset":         {},
+	// groups
+	"group:get_all":                          {},
+	"group:create":                           {},
+	"group:get":                              {},
+	"group:update":                           {},
+	"group:delete":                           {},
+	"group:group_project_attribute":          {},
+	"group:reset_status":                     {},
+	"group:enable_replication":               {},
+	"group:disable_replication":              {},
+	"group:failover_replication":             {},
+	"group:list_replication_targets":         {},
+	"group:get_all_group_snapshots":          {},
+	"group:create_group_snapshot":            {},
+	"group:get_group_snapshot":               {},
+	"group:delete_group_snapshot":            {},
+	"group:update_group_snapshot":            {},
+	"group:group_snapshot_project_attribute": {},
+	"group:reset_group_snapshot_status":      {},
+	"group:group_types:create":               {},
+	"group:group_types:update":               {},
+	"group:group_types:delete":               {},
+	"group:access_group_types_specs":         {},
+	"group:group_types_specs:get":            {},
+	"group:group_types_specs:get_all":        {},
+	"group:group_types_specs:create":         {},
+	"group:group_types_specs:update":         {},
+	"group:group_types_specs:delete":         {},
+	// limits, messages and quotas
+	"limits_extension:used_limits":                  {},
+	"message:get_all":                               {},
+	"message:get":                                   {},
+	"message:delete":                                {},
+	"volume_extension:quota_classes:get":            {},
+	"volume_extension:quota_classes:update":         {},
+	"volume_extension:quotas:show":                  {},
+	"volume_extension:quotas:update":                {},
+	"volume_extension:quotas:delete":                {},
+	"scheduler_extension:scheduler_stats:get_pools": {},
+	// manageable resources
+	"snapshot_extension:list_manageable":   {},
+	"snapshot_extension:snapshot_manage":   {},
+	"snapshot_extension:snapshot_unmanage": {},
+	"volume_extension:list_manageable":     {},
+	"volume_extension:volume_manage":       {},
+	"volume_extension:volume_unmanage":     {},
+	// QoS specs
+	"volume_extension:qos_specs_manage:get_all": {},
+	"volume_extension:qos_specs_manage:get":     {},
+	"volume_extension:qos_specs_manage:create":  {},
+	"volume_extension:qos_specs_manage:update":  {},
+	"volume_extension:qos_specs_manage:delete":  {},
+	// services
+	"volume_extension:capabilities":    {},
+	"volume_extension:hosts":           {},
+	"volume_extension:services:index":  {},
+	"volume_extension:services:update": {},
+	"volume:freeze_host":               {},
+	"volume:thaw_host":                 {},
+	"volume:failover_host":             {},
+	"workers:cleanup":                  {},
+	// snapshots
+	"volume_extension:extended_snapshot_attributes":              {},
+	"volume:get_all_snapshots":                                   {},
+	"volume:create_snapshot":                                     {},
+	"volume:get_snapshot":                                        {},
+	"volume:update_snapshot":                                     {},
+	"volume:delete_snapshot":                                     {},
+	"volume:get_snapshot_metadata":                               {},
+	"volume:update_snapshot_metadata":                            {},
+	"volume:delete_snapshot_metadata":                            {},
+	"volume_extension:snapshot_admin_actions:reset_status":       {},
+	"volume_extension:snapshot_admin_actions:force_delete":       {},
+	"snapshot_extension:snapshot_actions:update_snapshot_status": {},
+	// volume types
+	"volume_extension:types_manage":                           {},
+	"volume_extension:volume_type_access":                     {},
+	"volume_extension:type_create":                            {},
+	"volume_extension:type_update":                            {},
+	"volume_extension:type_delete":                            {},
+	"volume_extension:type_get":                               {},
+	"volume_extension:type_get_all":                           {},
+	"volume_extension:access_types_extra_specs":               {},
+	"volume_extension:access_types_qos_specs_id":              {},
+	"volume_extension:types_extra_specs:index":                {},
+	"volume_extension:types_extra_specs:create":               {},
+	"volume_extension:types_extra_specs:show":                 {},
+	"volume_extension:types_extra_specs:update":               {},
+	"volume_extension:types_extra_specs:delete":               {},
+	"volume_extension:types_extra_specs:read_sensitive":       {},
+	"volume_extension:volume_type_access:addProjectAccess":    {},
+	"volume_extension:volume_type_access:removeProjectAccess": {},
+	"volume_extension:volume_type_access:get_all_for_type":    {},
+	"volume_extension:volume_type_encryption":                 {},
+	"volume_extension:volume_type_encryption:create":          {},
+	"volume_extension:volume_type_encryption:get":             {},
+	"volume_extension:volume_type_encryption:update":          {},
+	"volume_extension:volume_type_encryption:delete":          {},
+	// volumes
+	"volume_extension:volume_host_attribute":        {},
+	"volume_extension:volume_tenant_attribute":      {},
+	"volume_extension:volume_mig_status_attribute":  {},
+	"volume_extension:volume_encryption_metadata":   {},
+	"volume_extension:volume_image_metadata":        {},
+	"volume_extension:volume_image_metadata:show":   {},
+	"volume_extension:volume_image_metadata:set":    {},
+	"volume_extension:volume_image_metadata:remove": {},
+	"volume:create":                                        {},
+	"volume:create_from_image":                             {},
+	"volume:get":                                           {},
+	"volume:get_all":                                       {},
+	"volume:update":                                        {},
+	"volume:delete":                                        {},
+	"volume:force_delete":                                  {},
+	"volume:multiattach":                                   {},
+	"volume:extend":                                        {},
+	"volume:extend_attached_volume":                        {},
+	"volume:revert_to_snapshot":                            {},
+	"volume:retype":                                        {},
+	"volume:update_readonly_flag":                          {},
+	"volume:reimage":                                       {},
+	"volume:reimage_reserved":                              {},
+	"volume:get_volume_metadata":                           {},
+	"volume:create_volume_metadata":                        {},
+	"volume:update_volume_metadata":                        {},
+	"volume:delete_volume_metadata":                        {},
+	"volume:update_volume_admin_metadata":                  {},
+	"volume_extension:volume_admin_actions:reset_status":   {},
+	"volume_extension:volume_admin_actions:force_delete":   {},
+	"volume_extension:volume_admin_actions:force_detach":   {},
+	"volume_extension:volume_admin_actions:migrate_volume": {},
+	"volume_extension:volume_admin_actions:migrate_volume_completion": {},
+	"volume_extension:volume_actions:upload_public":                   {},
+	"volume_extension:volume_actions:upload_image":                    {},
+	"volume_extension:volume_actions:initialize_connection":           {},
+	"volume_extension:volume_actions:terminate_connection":            {},
+	"volume_extension:volume_actions:roll_detaching":                  {},
+	"volume_extension:volume_actions:reserve":                         {},
+	"volume_extension:volume_actions:unreserve":                       {},
+	"volume_extension:volume_actions:begin_detaching":                 {},
+	"volume_extension:volume_actions:attach":                          {},
+	"volume_extension:volume_actions:detach":                          {},
+	// transfers
+	"volume:get_all_transfers": {},
+	"volume:create_transfer":   {},
+	"volume:get_transfer":      {},
+	"volume:accept_transfer":   {},
+	"volume:delete_transfer":   {},
+}
+
+// ValidatePolicyRules - checks that the policy.yaml content parses and that
+// it only overrides Cinder rules or aliases referenced by its other rules
+func ValidatePolicyRules(content string) error {
+	rules := map[string]interface{}{}
+	if err := yaml.Unmarshal([]byte(content), &rules); err != nil {
+		return fmt.Errorf("invalid policy YAML: %w", err)
+	}
+
+	aliases := map[string]struct{}{}
+	for name, check := range rules {
+		checkStr, ok := check.(string)
+		if !ok {
+			return fmt.Errorf("rule %s is not a string", name)
+		}
+		for _, match := range policyRuleReference.FindAllStringSubmatch(checkStr, -1) {
+			aliases[match[1]] = struct{}{}
+		}
+	}
+
+	unknown := []string{}
+	for name := range rules {
+		if _, ok := PolicyRules[name]; ok {
+			continue
+		}
+		if _, ok := aliases[name]; ok {
+			continue
+		}
+		unknown = append(unknown, name)
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("unknown Cinder policies: %v", unknown)
+	}
+	return nil
+}
+
+// Validate - checks that only one source of rules is used and that the inline
+// rules are valid. The content of the ConfigMap is checked by the controller.
+func (p *CinderAPIPolicy) Validate(basePath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if p == nil {
+		return allErrs
+	}
+
+	path := basePath.Child("policy")
+	if p.Rules != "" && p.ConfigMapRef != nil {
+		allErrs = append(allErrs, field.Forbidden(
+			path.Child("configMapRef"), "rules and configMapRef are mutually exclusive"))
+	}
+	if p.Rules != "" {
+		if err := ValidatePolicyRules(p.Rules); err != nil {
+			allErrs = append(allErrs, field.Invalid(path.Child("rules"), "", err.Error()))
+		}
+	}
+	return allErrs
+}
@@ -17,13 +17,49 @@ limitations under the License.
 package v1beta1
 
 import (
+	"fmt"
+	"slices"
+
 	condition "github.com/openstack-k8s-operators/lib-common/modules/common/condition"
 	"github.com/openstack-k8s-operators/lib-common/modules/common/service"
 	"github.com/openstack-k8s-operators/lib-common/modules/common/tls"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 	topologyv1 "github.com/openstack-k8s-operators/infra-operator/apis/topology/v1beta1"
 )
 
+const (
+	// APIServerHttpd - cinder-api is hosted by Apache httpd using mod_wsgi
+	APIServerHttpd APIServerType = "httpd"
+	// APIServerUWSGI - cinder-api is hosted by the uWSGI native server
+	APIServerUWSGI APIServerType = "uwsgi"
+)
+
+// APIServerType - server used to host the cinder-api WSGI application
+type APIServerType string
+
+const (
+	// AuthStrategyKeystone - the API validates the tokens with Keystone
+	AuthStrategyKeystone AuthStrategyType = "keystone"
+	// AuthStrategyNoAuth - the API doesn't authenticate the requests
+	AuthStrategyNoAuth AuthStrategyType = "noauth"
+)
+
+// AuthStrategyType - how the API authenticates the requests
+type AuthStrategyType string
+
+const (
+	// AuditOutputNotifications - audit records are sent to the notification bus
+	AuditOutputNotifications AuditOutputType = "notifications"
+	// AuditOutputFile - audit records are written to a file streamed by a sidecar
+	AuditOutputFile AuditOutputType = "file"
+)
+
+// AuditOutputType - where the audit records of the API calls go
+type AuditOutputType string
+
 // CinderAPITemplate defines the input parameters for the Cinder API service
 type CinderAPITemplateCore struct {
 	// Common input parameters for the Cinder API service
@@ -43,6 +79,157 @@ type CinderAPITemplateCore struct {
 	// +operator-sdk:csv:customresourcedefinitions:type=spec
 	// TLS - Parameters related to the TLS
 	TLS tls.API `json:"tls,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=httpd
+	// +kubebuilder:validation:Enum=httpd;uwsgi
+	// APIServer - Server used to host the cinder-api WSGI application. With
+	// httpd the application runs inside Apache mod_wsgi, with uwsgi it runs
+	// in the uWSGI native server as the cinder user, which also terminates TLS.
+	APIServer APIServerType `json:"apiServer"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=keystone
+	// +kubebuilder:validation:Enum=keystone;noauth
+	// AuthStrategy - how the API authenticates the requests. With keystone the
+	// tokens are validated with Keystone, whether the API is registered in its
+	// catalog or not. noauth accepts any request without authentication, and
+	// is only allowed for standalone deployments that don't register the API.
+	AuthStrategy AuthStrategyType `json:"authStrategy"`
+
+	// +kubebuilder:validation:Optional
+	// Audit - emit CADF audit records of the API calls using the keystonemiddleware
+	// audit filter
+	Audit *CinderAPIAudit `json:"audit,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Policy - oslo.policy rules of the API, either inline or from a ConfigMap
+	Policy *CinderAPIPolicy `json:"policy,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// RateLimit - protections of the API against abusive clients
+	RateLimit *CinderAPIRateLimit `json:"rateLimit,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// InternalClientAuth - mutual TLS on the internal network: the internal
+	// endpoint requires client certificates, and Cinder presents its own to
+	// the internal endpoints of Nova and Glance.
+	InternalClientAuth *CinderAPIClientAuth `json:"internalClientAuth,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// EndpointNetworks - NetworkAttachment each endpoint is also served on.
+	// The vhost of the endpoint listens on the address of the pod on that
+	// network besides the ones the Services reach, and the vhosts of the other
+	// endpoints don't. The internal endpoint is then served on its own port of
+	// the pods, so the public Service can't reach it. Each endpoint needs its
+	// own network. The key must be the endpoint type (public, internal)
+	EndpointNetworks map[service.Endpoint]string `json:"endpointNetworks,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Keystone - registration of the API in the Keystone catalog
+	Keystone *CinderAPIKeystone `json:"keystone,omitempty"`
+}
+
+// CinderAPIKeystone defines how the API is registered in the Keystone catalog
+type CinderAPIKeystone struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=true
+	// Register - create the KeystoneService and KeystoneEndpoint CRs of the
+	// API. It doesn't change how the API authenticates the requests, see
+	// authStrategy
+	Register bool `json:"register"`
+
+	// +kubebuilder:validation:Optional
+	// Description - description of the services in the catalog
+	Description string `json:"description,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=true
+	// Enabled - enabled state of the services in the catalog
+	Enabled bool `json:"enabled"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern="^/v3(/.*)?$"
+	// Path - path of the URLs of the volumev3 endpoints, for example
+	// /v3/%(project_id)s for clients that expect the project in the URL. It
+	// replaces the default /v3 path, so it must include it
+	Path string `json:"path,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// BlockStorage - also register the API with the block-storage service
+	// type, whose endpoints are always at /v3
+	BlockStorage bool `json:"blockStorage,omitempty"`
+}
+
+// CinderAPIClientAuth defines the mutual TLS settings of the internal network
+type CinderAPIClientAuth struct {
+	// +kubebuilder:validation:Required
+	// CaBundleSecretName - Secret holding, in its tls-ca-bundle.pem key, the
+	// CA bundle used to verify the client certificates presented to the
+	// internal endpoint
+	CaBundleSecretName string `json:"caBundleSecretName"`
+
+	// +kubebuilder:validation:Optional
+	// ClientCertSecretName - Secret holding the certificate and key (tls.crt
+	// and tls.key) Cinder presents on its calls to Nova and Glance
+	ClientCertSecretName string `json:"clientCertSecretName,omitempty"`
+}
+
+// CinderAPIRateLimit defines the protections of the API against abusive clients
+type CinderAPIRateLimit struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1024
+	// MaxRequestBodySize - largest request body, in bytes, accepted by the API
+	MaxRequestBodySize *int64 `json:"maxRequestBodySize,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Limits - number of requests each user can make to the API in a period
+	// of time, enforced by the cinder rate limiting middleware. The counters
+	// are kept in memory by each API process, so they are not shared between
+	// processes nor replicas.
+	Limits []CinderAPIRequestLimit `json:"limits,omitempty"`
+}
+
+// CinderAPIRequestLimit defines the number of requests each user can make in
+// a period of time
+type CinderAPIRequestLimit struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Enum=GET;POST;PUT;PATCH;DELETE
+	// Verb - HTTP method of the requests
+	Verb string `json:"verb"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=".*"
+	// +kubebuilder:validation:Pattern=`^[^,;()]+$`
+	// Regex - only the requests whose URL matches this regular expression count
+	Regex string `json:"regex"`
+
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Minimum=1
+	// Value - number of requests allowed per unit of time
+	Value int32 `json:"value"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=MINUTE
+	// +kubebuilder:validation:Enum=SECOND;MINUTE;HOUR;DAY
+	// Unit - period of time of the limit
+	Unit string `json:"unit"`
+}
+
+// CinderAPIAudit defines the audit records of the API calls
+type CinderAPIAudit struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=notifications
+	// +kubebuilder:validation:Enum=notifications;file
+	// Output - with notifications the records are sent to the notification bus
+	// configured in the notifications section of Cinder, with file they are
+	// written to a file streamed by a sidecar container.
+	Output AuditOutputType `json:"output"`
+
+	// +kubebuilder:validation:Optional
+	// +listType=set
+	// IgnoreReqList - HTTP methods of the API calls that are not audited, e.g. GET
+	IgnoreReqList []string `json:"ignoreReqList,omitempty"`
 }
 
 // CinderAPITemplate defines the input parameters for the Cinder API service
@@ -59,6 +246,12 @@ type APIOverrideSpec struct {
 	// Override configuration for the Service created to serve traffic to the cluster.
 	// The key must be the endpoint type (public, internal)
 	Service map[service.Endpoint]service.RoutedOverrideSpec `json:"service,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// IPFamilies of the Service created for each endpoint, the cluster
+	// default is used for the endpoints that are not listed.
+	// The key must be the endpoint type (public, internal)
+	IPFamilies map[service.Endpoint][]corev1.IPFamily `json:"ipFamilies,omitempty"`
 }
 
 // CinderAPISpec defines the desired state of CinderAPI
@@ -77,6 +270,10 @@ type CinderAPISpec struct {
 	// Secret containing RabbitMq transport URL
 	TransportURLSecret string `json:"transportURLSecret"`
 
+	// +kubebuilder:validation:Optional
+	// Secret containing the RabbitMq transport URL used for notifications
+	NotificationURLSecret string `json:"notificationURLSecret,omitempty"`
+
 	// +kubebuilder:validation:Optional
 	// ExtraMounts containing conf files and credentials
 	ExtraMounts []CinderExtraVolMounts `json:"extraMounts,omitempty"`
@@ -116,6 +313,9 @@ type CinderAPIStatus struct {
 
 	// LastAppliedTopology - the last applied Topology
 	LastAppliedTopology *topologyv1.TopoRef `json:"lastAppliedTopology,omitempty"`
+
+	// Image - image the service is deployed with and the images its pods use
+	Image *CinderImageStatus `json:"image,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -154,6 +354,245 @@ func (instance CinderAPI) IsReady() bool {
 			(instance.Status.Conditions.IsFalse(condition.DeploymentReadyCondition) && *instance.Spec.Replicas == 0))
 }
 
+// UsesUWSGI - returns true if cinder-api is hosted by the uWSGI native server
+func (instance CinderAPITemplateCore) UsesUWSGI() bool {
+	return instance.APIServer == APIServerUWSGI
+}
+
+// UsesNoAuth - returns true if the API doesn't authenticate the requests
+func (instance CinderAPITemplateCore) UsesNoAuth() bool {
+	return instance.AuthStrategy == AuthStrategyNoAuth
+}
+
+// ValidateAuthStrategy - an API that doesn't authenticate the requests can't
+// be registered in the Keystone catalog, so noauth requires both the explicit
+// strategy and the registration to be turned off.
+func (instance *CinderAPITemplateCore) ValidateAuthStrategy(basePath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if instance.UsesNoAuth() && instance.Keystone.Registers() {
+		allErrs = append(allErrs, field.Invalid(
+			basePath.Child("authStrategy"), instance.AuthStrategy,
+			"noauth requires keystone.register to be false"))
+	}
+	return allErrs
+}
+
+// GetAuthStrategyWarnings - returns a warning when the API doesn't
+// authenticate the requests
+func (instance *CinderAPITemplateCore) GetAuthStrategyWarnings(basePath *field.Path) admission.Warnings {
+	var warnings admission.Warnings
+	if instance.UsesNoAuth() {
+		warnings = append(warnings, fmt.Sprintf(
+			"%s: the API accepts requests without authentication", basePath.Child("authStrategy")))
+	}
+	return warnings
+}
+
+// ValidateProbes - the API has no startup probe, so neither the startup
+// overrides nor the profiles, which only tune the startup probe, apply to it
+func (instance *CinderAPITemplateCore) ValidateProbes(basePath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if instance.Probes == nil {
+		return allErrs
+	}
+	path := basePath.Child("probes")
+	if instance.Probes.Startup != nil {
+		allErrs = append(allErrs, field.Forbidden(path.Child("startup"),
+			"the API has no startup probe"))
+	}
+	if instance.Probes.Profile != "" && instance.Probes.Profile != ProbeProfileDefault {
+		allErrs = append(allErrs, field.Forbidden(path.Child("profile"),
+			"the profiles only tune the startup probe, which the API doesn't have"))
+	}
+	return allErrs
+}
+
+// ValidateAPIServer - uWSGI serves the internal and public endpoints from a
+// single socket, so TLS has to be enabled either on both or on none of them.
+func (instance *CinderAPITemplateCore) ValidateAPIServer(basePath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if !instance.UsesUWSGI() {
+		return allErrs
+	}
+	if instance.TLS.API.Enabled(service.EndpointInternal) != instance.TLS.API.Enabled(service.EndpointPublic) {
+		allErrs = append(allErrs, field.Invalid(
+			basePath.Child("apiServer"), instance.APIServer,
+			"uwsgi requires TLS to be enabled on both the internal and public endpoints or on none of them"))
+	}
+	return allErrs
+}
+
+// ValidateInternalClientAuth - client certificates can only be verified on
+// the internal endpoint when it has TLS, and uWSGI can't require them on the
+// internal endpoint only because it shares its socket with the public one.
+func (instance *CinderAPITemplateCore) ValidateInternalClientAuth(basePath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if instance.InternalClientAuth == nil {
+		return allErrs
+	}
+	path := basePath.Child("internalClientAuth")
+	if !instance.TLS.API.Enabled(service.EndpointInternal) {
+		allErrs = append(allErrs, field.Invalid(
+			path, instance.InternalClientAuth.CaBundleSecretName,
+			"client certificates require TLS on the internal endpoint"))
+	}
+	if instance.UsesUWSGI() {
+		allErrs = append(allErrs, field.Invalid(
+			path, instance.InternalClientAuth.CaBundleSecretName,
+			"uwsgi can't require client certificates on the internal endpoint only"))
+	}
+	return allErrs
+}
+
+// UsesIPv6 - returns true if the Service of any of the endpoints is requested
+// with an IPv6 address, so the API must listen on IPv6 too
+func (o APIOverrideSpec) UsesIPv6() bool {
+	for _, families := range o.IPFamilies {
+		for _, family := range families {
+			if family == corev1.IPv6Protocol {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ValidateIPFamilies - the IP families must be set for known endpoints, be
+// valid and not repeated, and a second family needs a dual-stack policy
+func (o APIOverrideSpec) ValidateIPFamilies(basePath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	path := basePath.Child("ipFamilies")
+	for endpt, families := range o.IPFamilies {
+		endptPath := path.Key(string(endpt))
+		if endpt != service.EndpointPublic && endpt != service.EndpointInternal {
+			allErrs = append(allErrs, field.NotSupported(
+				endptPath, endpt, []string{string(service.EndpointPublic), string(service.EndpointInternal)}))
+			continue
+		}
+		if len(families) > 2 {
+			allErrs = append(allErrs, field.TooMany(endptPath, len(families), 2))
+			continue
+		}
+		seen := map[corev1.IPFamily]bool{}
+		for i, family := range families {
+			if family != corev1.IPv4Protocol && family != corev1.IPv6Protocol {
+				allErrs = append(allErrs, field.NotSupported(
+					endptPath.Index(i), family, []string{string(corev1.IPv4Protocol), string(corev1.IPv6Protocol)}))
+			} else if seen[family] {
+				allErrs = append(allErrs, field.Duplicate(endptPath.Index(i), family))
+			}
+			seen[family] = true
+		}
+		if len(families) == 2 {
+			spec := o.Service[endpt].Spec
+			if spec != nil && spec.IPFamilyPolicy != nil && *spec.IPFamilyPolicy == corev1.IPFamilyPolicySingleStack {
+				allErrs = append(allErrs, field.Invalid(
+					basePath.Child("service").Key(string(endpt)).Child("spec", "ipFamilyPolicy"),
+					*spec.IPFamilyPolicy, "two IP families require a dual-stack ipFamilyPolicy"))
+			}
+		}
+	}
+	return allErrs
+}
+
+// ValidateEndpointNetworks - the endpoints can only be served on networks the
+// pods are attached to, each on its own, and the address pools of their
+// Services must be on those networks. uWSGI serves all of them on the same
+// socket
+func (instance *CinderAPITemplateCore) ValidateEndpointNetworks(basePath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	path := basePath.Child("endpointNetworks")
+	for endpt, network := range instance.EndpointNetworks {
+		if endpt != service.EndpointPublic && endpt != service.EndpointInternal {
+			allErrs = append(allErrs, field.NotSupported(
+				path.Key(string(endpt)), endpt, []string{string(service.EndpointPublic), string(service.EndpointInternal)}))
+			continue
+		}
+		if !slices.Contains(instance.NetworkAttachments, network) {
+			allErrs = append(allErrs, field.Invalid(
+				path.Key(string(endpt)), network, "must be one of the networkAttachments of the service"))
+		}
+	}
+	internal, hasInternal := instance.EndpointNetworks[service.EndpointInternal]
+	if public, ok := instance.EndpointNetworks[service.EndpointPublic]; ok && hasInternal && public == internal {
+		allErrs = append(allErrs, field.Invalid(
+			path.Key(string(service.EndpointPublic)), public,
+			"the public and internal endpoints can't be served on the same network"))
+	}
+	if len(instance.EndpointNetworks) > 0 {
+		overridePath := basePath.Child("override", "service")
+		for endpt, svcOverride := range instance.Override.Service {
+			if svcOverride.EmbeddedLabelsAnnotations == nil {
+				continue
+			}
+			pool, ok := svcOverride.Annotations[service.MetalLBAddressPoolAnnotation]
+			if !ok {
+				continue
+			}
+			poolPath := overridePath.Key(string(endpt)).Child("metadata", "annotations").Key(service.MetalLBAddressPoolAnnotation)
+			if network, ok := instance.EndpointNetworks[endpt]; ok {
+				if pool != network {
+					allErrs = append(allErrs, field.Invalid(
+						poolPath, pool, fmt.Sprintf("must be the network the endpoint is served on: %s", network)))
+				}
+			} else if !slices.Contains(instance.NetworkAttachments, pool) {
+				allErrs = append(allErrs, field.Invalid(
+					poolPath, pool, "must be one of the networkAttachments of the service"))
+			}
+		}
+	}
+	if len(instance.EndpointNetworks) > 0 && instance.UsesUWSGI() {
+		allErrs = append(allErrs, field.Invalid(
+			basePath.Child("apiServer"), instance.APIServer,
+			"uwsgi can't serve the endpoints on different networks"))
+	}
+	return allErrs
+}
+
+// ValidateAudit - audit records sent to the notification bus require the
+// notifications to be enabled.
+func (instance *CinderAPITemplateCore) ValidateAudit(notifications Notifications, basePath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if instance.Audit == nil || instance.Audit.Output == AuditOutputFile {
+		return allErrs
+	}
+	if !notifications.Enabled {
+		allErrs = append(allErrs, field.Invalid(
+			basePath.Child("audit", "output"), instance.Audit.Output,
+			"audit records can't be sent to the notification bus when notifications are disabled"))
+	}
+	return allErrs
+}
+
+// GetClientCertSecretName - Returns the secret with the client certificate
+// Cinder presents to Nova and Glance, or an empty string if there is none
+func (c *CinderAPIClientAuth) GetClientCertSecretName() string {
+	if c == nil {
+		return ""
+	}
+	return c.ClientCertSecretName
+}
+
+// Registers - Returns true if the API creates its KeystoneService and
+// KeystoneEndpoint CRs
+func (k *CinderAPIKeystone) Registers() bool {
+	return k == nil || k.Register
+}
+
+// IsEnabled - Returns the enabled state of the services in the catalog
+func (k *CinderAPIKeystone) IsEnabled() bool {
+	return k == nil || k.Enabled
+}
+
+// GetMaxRequestBodySize - Returns the largest request body accepted by the
+// API, or 0 to keep the defaults
+func (r *CinderAPIRateLimit) GetMaxRequestBodySize() int64 {
+	if r == nil || r.MaxRequestBodySize == nil {
+		return 0
+	}
+	return *r.MaxRequestBodySize
+}
+
 // GetSpecTopologyRef - Returns the LastAppliedTopology Set in the Status
 func (instance *CinderAPI) GetSpecTopologyRef() *topologyv1.TopoRef {
 	return instance.Spec.TopologyRef
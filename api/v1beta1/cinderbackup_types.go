@@ -33,6 +33,23 @@ type CinderBackupTemplateCore struct {
 	// +kubebuilder:validation:Minimum=0
 	// Replicas - Cinder Backup Replicas
 	Replicas *int32 `json:"replicas"`
+
+	// +kubebuilder:validation:Optional
+	// FibreChannel - discover the FC HBAs of the nodes the service can run on,
+	// and optionally only run the service on the nodes that have them
+	FibreChannel *FibreChannel `json:"fibreChannel,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// AvailabilityZone - set the availability zone of the service to the zone
+	// of the nodes it runs on
+	AvailabilityZone *AvailabilityZone `json:"availabilityZone,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// StorageNetwork - NetworkAttachment the service uses to reach the
+	// storage. The service is started with my_ip set to the address of the
+	// pod on this network, which is also the default target_ip_address of
+	// the backends. When not set the service uses my_ip from its config.
+	StorageNetwork string `json:"storageNetwork,omitempty"`
 }
 
 // CinderBackupTemplate defines the input parameters for the Cinder Backup service
@@ -60,6 +77,10 @@ type CinderBackupSpec struct {
 	// Secret containing RabbitMq transport URL
 	TransportURLSecret string `json:"transportURLSecret"`
 
+	// +kubebuilder:validation:Optional
+	// Secret containing the RabbitMq transport URL used for notifications
+	NotificationURLSecret string `json:"notificationURLSecret,omitempty"`
+
 	// +kubebuilder:validation:Optional
 	// ExtraMounts containing conf files and credentials
 	ExtraMounts []CinderExtraVolMounts `json:"extraMounts,omitempty"`
@@ -98,6 +119,15 @@ type CinderBackupStatus struct {
 
 	// LastAppliedTopology - the last applied Topology
 	LastAppliedTopology *topologyv1.TopoRef `json:"lastAppliedTopology,omitempty"`
+
+	// FibreChannel - FC HBAs discovered on the nodes the service can run on
+	FibreChannel []FibreChannelNodeStatus `json:"fibreChannel,omitempty"`
+
+	// Image - image the service is deployed with and the images its pods use
+	Image *CinderImageStatus `json:"image,omitempty"`
+
+	// AvailabilityZone - availability zone of the service
+	AvailabilityZone string `json:"availabilityZone,omitempty"`
 }
 
 //+kubebuilder:object:root=true
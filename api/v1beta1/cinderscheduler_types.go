@@ -17,10 +17,16 @@ limitations under the License.
 package v1beta1
 
 import (
+	"fmt"
+	"slices"
+	"strings"
+
 	condition "github.com/openstack-k8s-operators/lib-common/modules/common/condition"
 	"github.com/openstack-k8s-operators/lib-common/modules/common/tls"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	topologyv1 "github.com/openstack-k8s-operators/infra-operator/apis/topology/v1beta1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 )
 
 // CinderSchedulerTemplate defines the input parameters for the Cinder Scheduler service
@@ -33,6 +39,125 @@ type CinderSchedulerTemplateCore struct {
 	// +kubebuilder:validation:Minimum=0
 	// Replicas - Cinder Scheduler Replicas
 	Replicas *int32 `json:"replicas"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=false
+	// CapabilitiesWarmup - only report a scheduler pod as ready once it has
+	// received the capabilities of every enabled back-end of the cinderVolumes
+	// of the Cinder CR, so rolling updates wait for each restarted scheduler
+	// to have them before restarting the next one. It is off by default, and
+	// it doesn't stop a scheduler that is not ready from taking requests from
+	// the message bus, so a restarted scheduler can still fail placements
+	// until the capabilities arrive.
+	CapabilitiesWarmup bool `json:"capabilitiesWarmup"`
+
+	// +kubebuilder:validation:Optional
+	// AvailabilityZoneFilter - availability zone settings of the
+	// AvailabilityZoneFilter. The API validates the zone of the new volumes
+	// with them too, so they apply to all the services
+	AvailabilityZoneFilter *AvailabilityZoneFilter `json:"availabilityZoneFilter,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// SchedulerPolicy - filters and weighers used to choose the back-end of
+	// the volumes
+	SchedulerPolicy *SchedulerPolicy `json:"schedulerPolicy,omitempty"`
+}
+
+// SchedulerPolicy - how the scheduler chooses the back-end of the volumes
+type SchedulerPolicy struct {
+	// +kubebuilder:validation:Optional
+	// +listType=set
+	// Filters - filters used when the request doesn't specify them, in the
+	// order they are applied
+	Filters []string `json:"filters,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +listType=set
+	// Weighers - weighers used to rank the back-ends that pass the filters
+	Weighers []string `json:"weighers,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern=`^-?[0-9]+(\.[0-9]+)?$`
+	// CapacityWeightMultiplier - multiplier of the CapacityWeigher. Negative
+	// values stack volumes instead of spreading them
+	CapacityWeightMultiplier string `json:"capacityWeightMultiplier,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern=`^-?[0-9]+(\.[0-9]+)?$`
+	// AllocatedCapacityWeightMultiplier - multiplier of the
+	// AllocatedCapacityWeigher. Negative values spread volumes
+	AllocatedCapacityWeightMultiplier string `json:"allocatedCapacityWeightMultiplier,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern=`^-?[0-9]+(\.[0-9]+)?$`
+	// VolumeNumberMultiplier - multiplier of the VolumeNumberWeigher.
+	// Negative values spread volumes
+	VolumeNumberMultiplier string `json:"volumeNumberMultiplier,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=false
+	// Stochastic - choose the back-end randomly, with a probability
+	// proportional to its weight, instead of always choosing the one with the
+	// highest weight
+	Stochastic bool `json:"stochastic"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	// DriverInitWaitTime - seconds to wait after starting to receive the
+	// capabilities of the volume services before scheduling
+	DriverInitWaitTime *int32 `json:"driverInitWaitTime,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Backends - goodness and filter functions of each of the cinderVolumes,
+	// used by the GoodnessWeigher and DriverFilter
+	Backends map[string]SchedulerBackendFunctions `json:"backends,omitempty"`
+}
+
+// SchedulerBackendFunctions - functions a back-end reports to the scheduler
+type SchedulerBackendFunctions struct {
+	// +kubebuilder:validation:Optional
+	// GoodnessFunction - expression rating the back-end from 0 to 100 for a
+	// request, used by the GoodnessWeigher
+	GoodnessFunction string `json:"goodnessFunction,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// FilterFunction - expression deciding whether the back-end can take a
+	// request, used by the DriverFilter
+	FilterFunction string `json:"filterFunction,omitempty"`
+}
+
+// SchedulerFilters - filters known to the cinder scheduler
+var SchedulerFilters = []string{
+	"AvailabilityZoneFilter",
+	"CapabilitiesFilter",
+	"CapacityFilter",
+	"DifferentBackendFilter",
+	"DriverFilter",
+	"InstanceLocalityFilter",
+	"JsonFilter",
+	"RetryFilter",
+	"SameBackendFilter",
+}
+
+// defaultSchedulerFilters - filters the cinder scheduler uses by default
+var defaultSchedulerFilters = []string{
+	"AvailabilityZoneFilter",
+	"CapacityFilter",
+	"CapabilitiesFilter",
+}
+
+// defaultSchedulerWeighers - weighers the cinder scheduler uses by default
+var defaultSchedulerWeighers = []string{
+	"CapacityWeigher",
+}
+
+// SchedulerWeighers - weighers known to the cinder scheduler
+var SchedulerWeighers = []string{
+	"AllocatedCapacityWeigher",
+	"CapacityWeigher",
+	"ChanceWeigher",
+	"GoodnessWeigher",
+	"VolumeNumberWeigher",
 }
 
 // CinderSchedulerTemplate defines the input parameters for the Cinder Scheduler service
@@ -60,6 +185,10 @@ type CinderSchedulerSpec struct {
 	// Secret containing RabbitMq transport URL
 	TransportURLSecret string `json:"transportURLSecret"`
 
+	// +kubebuilder:validation:Optional
+	// Secret containing the RabbitMq transport URL used for notifications
+	NotificationURLSecret string `json:"notificationURLSecret,omitempty"`
+
 	// +kubebuilder:validation:Optional
 	// ExtraMounts containing conf files and credentials
 	ExtraMounts []CinderExtraVolMounts `json:"extraMounts,omitempty"`
@@ -98,6 +227,9 @@ type CinderSchedulerStatus struct {
 
 	// LastAppliedTopology - the last applied Topology
 	LastAppliedTopology *topologyv1.TopoRef `json:"lastAppliedTopology,omitempty"`
+
+	// Image - image the service is deployed with and the images its pods use
+	Image *CinderImageStatus `json:"image,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -150,3 +282,78 @@ func (instance *CinderScheduler) GetLastAppliedTopology() *topologyv1.TopoRef {
 func (instance *CinderScheduler) SetLastAppliedTopology(topologyRef *topologyv1.TopoRef) {
 	instance.Status.LastAppliedTopology = topologyRef
 }
+
+// Validate - ensures the scheduler policy only uses known filters and
+// weighers, and has functions for existing back-ends
+func (p *SchedulerPolicy) Validate(basePath *field.Path, backendNames []string) field.ErrorList {
+	var allErrs field.ErrorList
+	if p == nil {
+		return allErrs
+	}
+
+	path := basePath.Child("schedulerPolicy")
+	for i, filter := range p.Filters {
+		if !slices.Contains(SchedulerFilters, filter) {
+			allErrs = append(allErrs, field.NotSupported(
+				path.Child("filters").Index(i), filter, SchedulerFilters))
+		}
+	}
+	for i, weigher := range p.Weighers {
+		if !slices.Contains(SchedulerWeighers, weigher) {
+			allErrs = append(allErrs, field.NotSupported(
+				path.Child("weighers").Index(i), weigher, SchedulerWeighers))
+		}
+	}
+
+	filters := p.Filters
+	if len(filters) == 0 {
+		filters = defaultSchedulerFilters
+	}
+	weighers := p.Weighers
+	if len(weighers) == 0 {
+		weighers = defaultSchedulerWeighers
+	}
+	for name, functions := range p.Backends {
+		backendPath := path.Child("backends").Key(name)
+		if !slices.Contains(backendNames, name) {
+			allErrs = append(allErrs, field.NotFound(backendPath, name))
+		}
+		// The functions are rendered quoted in a single line of the config
+		if strings.ContainsAny(functions.FilterFunction, "\"\r\n") {
+			allErrs = append(allErrs, field.Invalid(
+				backendPath.Child("filterFunction"), functions.FilterFunction,
+				"must not contain double quotes or line breaks"))
+		}
+		if strings.ContainsAny(functions.GoodnessFunction, "\"\r\n") {
+			allErrs = append(allErrs, field.Invalid(
+				backendPath.Child("goodnessFunction"), functions.GoodnessFunction,
+				"must not contain double quotes or line breaks"))
+		}
+		// The functions are ignored unless their filter or weigher is used
+		if functions.FilterFunction != "" && !slices.Contains(filters, "DriverFilter") {
+			allErrs = append(allErrs, field.Invalid(
+				backendPath.Child("filterFunction"), functions.FilterFunction,
+				"requires the DriverFilter in the filters"))
+		}
+		if functions.GoodnessFunction != "" && !slices.Contains(weighers, "GoodnessWeigher") {
+			allErrs = append(allErrs, field.Invalid(
+				backendPath.Child("goodnessFunction"), functions.GoodnessFunction,
+				"requires the GoodnessWeigher in the weighers"))
+		}
+	}
+	return allErrs
+}
+
+// GetWarnings - returns the warnings about the scheduler configuration
+func (instance *CinderSchedulerTemplateCore) GetWarnings(basePath *field.Path) admission.Warnings {
+	var warnings admission.Warnings
+	// A restarted scheduler doesn't have the capabilities of the back-ends
+	// until they report them, and without the warmup the rolling update
+	// doesn't wait for them before restarting the next scheduler
+	if instance.Replicas != nil && *instance.Replicas > 1 && !instance.CapabilitiesWarmup {
+		warnings = append(warnings, fmt.Sprintf(
+			"%s: running %d schedulers without %s lets a rolling update restart all of them before any has the capabilities of the back-ends",
+			basePath.Child("replicas"), *instance.Replicas, basePath.Child("capabilitiesWarmup")))
+	}
+	return warnings
+}
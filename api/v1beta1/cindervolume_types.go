@@ -17,15 +17,25 @@ limitations under the License.
 package v1beta1
 
 import (
+	"fmt"
+	"sort"
+	"strings"
+
 	condition "github.com/openstack-k8s-operators/lib-common/modules/common/condition"
 	"github.com/openstack-k8s-operators/lib-common/modules/common/tls"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	topologyv1 "github.com/openstack-k8s-operators/infra-operator/apis/topology/v1beta1"
 )
 
 const (
 	// Backend -
 	Backend = "backend"
+
+	// FailoverPrimary - failover target used to run the backends on their
+	// primary storage
+	FailoverPrimary = "primary"
 )
 
 // CinderVolumeTemplate defines the input parameters for the Cinder Volume service
@@ -39,6 +49,155 @@ type CinderVolumeTemplateCore struct {
 	// +kubebuilder:validation:Maximum=1
 	// Replicas - Cinder Volume Replicas
 	Replicas *int32 `json:"replicas"`
+
+	// +kubebuilder:validation:Optional
+	// Replication - replication targets of the backends of this service
+	Replication *CinderVolumeReplication `json:"replication,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=primary
+	// Failover - backend ID of the replication target the backends must be
+	// failed over to, or "primary" to run them on (or fail them back to) the
+	// primary storage. Changing it runs a failover job against the service.
+	Failover string `json:"failover,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// LVM - deploy an LVM backend, the operator prepares its volume group and
+	// pins the service to the node that has it
+	LVM *CinderVolumeLVM `json:"lvm,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +listType=set
+	// HostPrerequisites - transports the backends need from the nodes. They
+	// are verified on every node the service could run on, and the service
+	// is kept off the nodes that fail the verification.
+	HostPrerequisites []HostPrerequisite `json:"hostPrerequisites,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// FibreChannel - discover the FC HBAs of the nodes the service can run on,
+	// and optionally only run the service on the nodes that have them
+	FibreChannel *FibreChannel `json:"fibreChannel,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// AvailabilityZone - set the availability zone of the service to the zone
+	// of the nodes it runs on
+	AvailabilityZone *AvailabilityZone `json:"availabilityZone,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// StorageNetwork - NetworkAttachment the service uses to reach the
+	// storage. The service is started with my_ip set to the address of the
+	// pod on this network, which is also the default target_ip_address of
+	// the backends. When not set the service uses my_ip from its config.
+	StorageNetwork string `json:"storageNetwork,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +listType=map
+	// +listMapKey=name
+	// DriverPlugins - images with the vendor libraries the drivers of this
+	// service need. They are added on top of the service image when the pod
+	// starts, so there's no need to build a custom image for the service.
+	DriverPlugins []CinderDriverPlugin `json:"driverPlugins,omitempty"`
+}
+
+// CinderDriverPlugin - image with the Python packages needed by a driver
+type CinderDriverPlugin struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MaxLength=40
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// Name - name of the plugin
+	Name string `json:"name"`
+
+	// +kubebuilder:validation:Required
+	// ContainerImage - image of the plugin
+	ContainerImage string `json:"containerImage"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=/plugin
+	// Path - directory in the plugin image with the Python packages, as
+	// installed with "pip install --target"
+	Path string `json:"path"`
+}
+
+// HostPrerequisite - host service and configuration required by a transport
+// +kubebuilder:validation:Enum=iscsi;multipath;nvme
+type HostPrerequisite string
+
+const (
+	// HostPrerequisiteISCSI - iscsid running and an initiator name configured
+	HostPrerequisiteISCSI HostPrerequisite = "iscsi"
+	// HostPrerequisiteMultipath - multipathd running with find_multipaths enabled
+	HostPrerequisiteMultipath HostPrerequisite = "multipath"
+	// HostPrerequisiteNVMe - nvme-fabrics loaded and a host NQN and ID configured
+	HostPrerequisiteNVMe HostPrerequisite = "nvme"
+)
+
+// LVMTargetHelper - tool used by the LVM driver to export the volumes
+type LVMTargetHelper string
+
+const (
+	// LVMTargetHelperLIO - export volumes over iSCSI using LIO
+	LVMTargetHelperLIO LVMTargetHelper = "lioadm"
+	// LVMTargetHelperNVMET - export volumes over NVMe-TCP using nvmet
+	LVMTargetHelperNVMET LVMTargetHelper = "nvmet"
+)
+
+// CinderVolumeLVM - LVM backend running on one of the OpenShift nodes
+type CinderVolumeLVM struct {
+	// +kubebuilder:validation:Required
+	// NodeName - node that has the volume group, the service only runs there
+	NodeName string `json:"nodeName"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=cinder-volumes
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9+_.][a-zA-Z0-9+_.-]*$`
+	// +kubebuilder:validation:MaxLength=127
+	// VolumeGroup - name of the LVM volume group, it names the loop file in
+	// the node too
+	VolumeGroup string `json:"volumeGroup"`
+
+	// +kubebuilder:validation:Optional
+	// Devices - block devices of the node used to create the volume group
+	Devices []string `json:"devices,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// LoopFileSize - size of a file in the node used as a loop device to create
+	// the volume group when there are no devices, only meant for testing
+	LoopFileSize *resource.Quantity `json:"loopFileSize,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=lioadm
+	// +kubebuilder:validation:Enum=lioadm;nvmet
+	// TargetHelper - tool used to export the volumes, lioadm for iSCSI and
+	// nvmet for NVMe-TCP
+	TargetHelper LVMTargetHelper `json:"targetHelper"`
+
+	// +kubebuilder:validation:Optional
+	// TargetIPAddress - IP address of the node used to export the volumes
+	TargetIPAddress string `json:"targetIPAddress,omitempty"`
+}
+
+// CinderVolumeReplication - replication configuration of the backends of a
+// Cinder Volume service
+type CinderVolumeReplication struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems=1
+	// Devices - replication targets, rendered as replication_device options
+	Devices []CinderVolumeReplicationDevice `json:"devices"`
+}
+
+// CinderVolumeReplicationDevice - replication target of a backend
+type CinderVolumeReplicationDevice struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9_.-]+$`
+	// BackendID - identifier of the replication target, used in the failover
+	// field
+	BackendID string `json:"backendID"`
+
+	// +kubebuilder:validation:Optional
+	// Options - driver specific options of the replication target, for
+	// example san_ip or pool names. They are stored in plain text, so they
+	// must not hold credentials
+	Options map[string]string `json:"options,omitempty"`
 }
 
 // CinderVolumeTemplate defines the input parameters for the Cinder Volume service
@@ -66,6 +225,15 @@ type CinderVolumeSpec struct {
 	// Secret containing RabbitMq transport URL
 	TransportURLSecret string `json:"transportURLSecret"`
 
+	// +kubebuilder:validation:Optional
+	// Secret containing the RabbitMq transport URL used for notifications
+	NotificationURLSecret string `json:"notificationURLSecret,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// ClientCertSecretName - Secret holding the client certificate and key
+	// used on the calls to Nova and Glance
+	ClientCertSecretName string `json:"clientCertSecretName,omitempty"`
+
 	// +kubebuilder:validation:Optional
 	// ExtraMounts containing conf files and credentials
 	ExtraMounts []CinderExtraVolMounts `json:"extraMounts,omitempty"`
@@ -78,6 +246,11 @@ type CinderVolumeSpec struct {
 	// +operator-sdk:csv:customresourcedefinitions:type=spec
 	// TLS - Parameters related to the TLS
 	TLS tls.Ca `json:"tls,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// SchedulerFunctions - goodness and filter functions of the back-end from
+	// the scheduler policy
+	SchedulerFunctions *SchedulerBackendFunctions `json:"schedulerFunctions,omitempty"`
 }
 
 // CinderVolumeStatus defines the observed state of CinderVolume
@@ -104,6 +277,100 @@ type CinderVolumeStatus struct {
 
 	// LastAppliedTopology - the last applied Topology
 	LastAppliedTopology *topologyv1.TopoRef `json:"lastAppliedTopology,omitempty"`
+
+	// Backends - state of the backends as reported by the cinder-volume pods
+	Backends []CinderVolumeBackendStatus `json:"backends,omitempty"`
+
+	// ActiveBackendID - backend ID of the replication target the backends have
+	// been failed over to, empty when running on the primary storage
+	ActiveBackendID string `json:"activeBackendID,omitempty"`
+
+	// LVM - state of the volume group of an LVM backend
+	LVM *CinderVolumeLVMStatus `json:"lvm,omitempty"`
+
+	// FibreChannel - FC HBAs discovered on the nodes the service can run on
+	FibreChannel []FibreChannelNodeStatus `json:"fibreChannel,omitempty"`
+
+	// Image - image the service is deployed with and the images its pods use
+	Image *CinderImageStatus `json:"image,omitempty"`
+
+	// AvailabilityZone - availability zone of the service
+	AvailabilityZone string `json:"availabilityZone,omitempty"`
+}
+
+// CinderVolumeLVMStatus - state of the volume group of an LVM backend
+type CinderVolumeLVMStatus struct {
+	// NodeName - node that has the volume group
+	NodeName string `json:"nodeName"`
+
+	// VolumeGroup - name of the volume group
+	VolumeGroup string `json:"volumeGroup"`
+
+	// TotalCapacityGB - size of the volume group
+	TotalCapacityGB string `json:"totalCapacityGB,omitempty"`
+
+	// FreeCapacityGB - free space in the volume group
+	FreeCapacityGB string `json:"freeCapacityGB,omitempty"`
+}
+
+// CinderVolumeBackendStatus - state of a backend reported by the cinder-volume
+// service
+type CinderVolumeBackendStatus struct {
+	// Name - name of the backend in enabled_backends
+	Name string `json:"name"`
+
+	// Host - host of the cinder-volume service for the backend
+	Host string `json:"host"`
+
+	// Up - the service is doing its heartbeats in the database
+	Up bool `json:"up"`
+
+	// Disabled - the service has been disabled
+	Disabled bool `json:"disabled,omitempty"`
+
+	// Frozen - the backend has been frozen
+	Frozen bool `json:"frozen,omitempty"`
+
+	// ReplicationStatus - replication status of the backend
+	ReplicationStatus string `json:"replicationStatus,omitempty"`
+
+	// LastHeartbeat - time of the last heartbeat of the service
+	LastHeartbeat *metav1.Time `json:"lastHeartbeat,omitempty"`
+
+	// Capabilities - summary of the capabilities reported by the driver
+	Capabilities *CinderVolumeBackendCapabilities `json:"capabilities,omitempty"`
+
+	// Pools - state of the pools of the backend
+	Pools []CinderVolumePoolStatus `json:"pools,omitempty"`
+
+	// Error - problem found while collecting the state of the backend
+	Error string `json:"error,omitempty"`
+}
+
+// CinderVolumeBackendCapabilities - summary of the capabilities of a backend
+type CinderVolumeBackendCapabilities struct {
+	// VendorName - vendor of the storage array
+	VendorName string `json:"vendorName,omitempty"`
+
+	// DriverVersion - version of the cinder driver
+	DriverVersion string `json:"driverVersion,omitempty"`
+
+	// StorageProtocol - protocol used to access the volumes
+	StorageProtocol string `json:"storageProtocol,omitempty"`
+}
+
+// CinderVolumePoolStatus - state of a pool of a backend
+type CinderVolumePoolStatus struct {
+	// Name - name of the pool
+	Name string `json:"name"`
+
+	// TotalCapacityGB - total capacity of the pool, it can also be "unknown"
+	// or "infinite"
+	TotalCapacityGB string `json:"totalCapacityGB,omitempty"`
+
+	// FreeCapacityGB - free capacity of the pool, it can also be "unknown"
+	// or "infinite"
+	FreeCapacityGB string `json:"freeCapacityGB,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -142,6 +409,85 @@ func (instance CinderVolume) IsReady() bool {
 			(instance.Status.Conditions.IsFalse(condition.DeploymentReadyCondition) && *instance.Spec.Replicas == 0))
 }
 
+// FailoverTarget - returns the backend ID of the replication target the
+// backends must run on, or an empty string for the primary storage
+func (instance CinderVolumeTemplateCore) FailoverTarget() string {
+	if instance.Failover == FailoverPrimary {
+		return ""
+	}
+	return instance.Failover
+}
+
+// ValidateReplication - the failover target must be one of the replication
+// devices and their backend IDs must be unique
+func (instance *CinderVolumeTemplateCore) ValidateReplication(basePath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	backendIDs := map[string]bool{}
+	if instance.Replication != nil {
+		for idx, device := range instance.Replication.Devices {
+			if backendIDs[device.BackendID] || device.BackendID == FailoverPrimary {
+				allErrs = append(allErrs, field.Duplicate(
+					basePath.Child("replication").Child("devices").Index(idx).Child("backendID"),
+					device.BackendID))
+			}
+			backendIDs[device.BackendID] = true
+
+			// The options are rendered as key:value pairs joined with commas
+			// in a single line of the config
+			optionsPath := basePath.Child("replication").Child("devices").Index(idx).Child("options")
+			keys := make([]string, 0, len(device.Options))
+			for key := range device.Options {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			for _, key := range keys {
+				if strings.ContainsAny(key, ",:\r\n") {
+					allErrs = append(allErrs, field.Invalid(
+						optionsPath, key, "keys must not contain commas, colons or line breaks"))
+				}
+				if strings.ContainsAny(device.Options[key], ",\r\n") {
+					allErrs = append(allErrs, field.Invalid(
+						optionsPath.Key(key), device.Options[key], "must not contain commas or line breaks"))
+				}
+			}
+		}
+	}
+
+	if target := instance.FailoverTarget(); target != "" && !backendIDs[target] {
+		allErrs = append(allErrs, field.Invalid(
+			basePath.Child("failover"), instance.Failover,
+			fmt.Sprintf("must be %q or the backendID of a replication device", FailoverPrimary)))
+	}
+	return allErrs
+}
+
+// ValidateLVM - the volume group of an LVM backend is created either from
+// devices or from a loop file
+func (instance *CinderVolumeTemplateCore) ValidateLVM(basePath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if instance.LVM == nil {
+		return allErrs
+	}
+
+	path := basePath.Child("lvm")
+	if len(instance.LVM.Devices) > 0 && instance.LVM.LoopFileSize != nil {
+		allErrs = append(allErrs, field.Forbidden(
+			path.Child("loopFileSize"), "devices and loopFileSize are mutually exclusive"))
+	}
+	if len(instance.LVM.Devices) == 0 && instance.LVM.LoopFileSize == nil {
+		allErrs = append(allErrs, field.Required(
+			path.Child("devices"), "either devices or loopFileSize must be set"))
+	}
+	// LVM rejects these names, and they would point the loop file outside of
+	// /var/lib/cinder
+	if instance.LVM.VolumeGroup == "." || instance.LVM.VolumeGroup == ".." {
+		allErrs = append(allErrs, field.Invalid(
+			path.Child("volumeGroup"), instance.LVM.VolumeGroup, "is not a valid volume group name"))
+	}
+	return allErrs
+}
+
 // BackendName - returns the backend name of a CinderVolume instance based on the labels
 func (instance CinderVolume) BackendName() string {
 	return instance.Labels[Backend]
@@ -17,6 +17,9 @@ limitations under the License.
 package v1beta1
 
 import (
+	"fmt"
+	"slices"
+
 	corev1 "k8s.io/api/core/v1"
 	topologyv1 "github.com/openstack-k8s-operators/infra-operator/apis/topology/v1beta1"
 	"k8s.io/apimachinery/pkg/util/validation/field"
@@ -42,6 +45,37 @@ type CinderTemplate struct {
 	// +kubebuilder:default={service: CinderPassword}
 	// PasswordSelectors - Selectors to identify the ServiceUser password from the Secret
 	PasswordSelectors PasswordSelector `json:"passwordSelectors"`
+
+	// +kubebuilder:validation:Optional
+	// ImagePinning - resolve the tags of the container images of the services
+	// to digests when they are first deployed, and keep running those digests
+	// until the image in the spec or the refresh field changes
+	ImagePinning *ImagePinning `json:"imagePinning,omitempty"`
+}
+
+// ImagePinning - pinning of the container images to digests
+type ImagePinning struct {
+	// +kubebuilder:validation:Optional
+	// Refresh - changing this value resolves the tags of the images again,
+	// rolling the services whose digest has changed
+	Refresh string `json:"refresh,omitempty"`
+}
+
+// CinderImageStatus - images used by a Cinder service
+type CinderImageStatus struct {
+	// ContainerImage - image in the spec the service is deployed from
+	ContainerImage string `json:"containerImage,omitempty"`
+
+	// ResolvedImage - image the service is deployed with, pinned to a digest
+	// when image pinning is enabled
+	ResolvedImage string `json:"resolvedImage,omitempty"`
+
+	// Refresh - value of the refresh field of the image pinning when the
+	// image was resolved
+	Refresh string `json:"refresh,omitempty"`
+
+	// PodImages - image ID each running pod of the service is using
+	PodImages map[string]string `json:"podImages,omitempty"`
 }
 
 // CinderServiceTemplate defines the input parameters that can be defined for a given
@@ -78,6 +112,126 @@ type CinderServiceTemplate struct {
 	// TopologyRef to apply the Topology defined by the associated CR referenced
 	// by name
 	TopologyRef *topologyv1.TopoRef `json:"topologyRef,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Probes - tune the startup, liveness and readiness probes of the service
+	Probes *ProbeOverrides `json:"probes,omitempty"`
+}
+
+// ProbeProfile - named set of default probe timings
+type ProbeProfile string
+
+const (
+	// ProbeProfileDefault - probe timings suitable for most backends
+	ProbeProfileDefault ProbeProfile = "default"
+	// ProbeProfileNetApp - longer startup for NetApp backends with many
+	// FlexVols/pools, where do_setup can take several minutes
+	ProbeProfileNetApp ProbeProfile = "netapp"
+	// ProbeProfilePowerMax - longer startup for Dell PowerMax backends, where
+	// do_setup queries Unisphere for every storage group
+	ProbeProfilePowerMax ProbeProfile = "powermax"
+	// ProbeProfileSlow - generic profile for any other backend with a slow
+	// initialization
+	ProbeProfileSlow ProbeProfile = "slow"
+)
+
+// ProbeOverrides - probe tuning for a Cinder service. The profile provides
+// the defaults, and any field set in startup, liveness or readiness takes
+// precedence over it.
+type ProbeOverrides struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=default
+	// +kubebuilder:validation:Enum=default;netapp;powermax;slow
+	// Profile - set of default probe timings, selected by backend type
+	Profile ProbeProfile `json:"profile,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Startup - overrides for the startup probe
+	Startup *ProbeOverride `json:"startup,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Liveness - overrides for the liveness probe
+	Liveness *ProbeOverride `json:"liveness,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Readiness - overrides for the readiness probe
+	Readiness *ProbeOverride `json:"readiness,omitempty"`
+}
+
+// ProbeOverride - timings for a single probe, unset fields keep the value
+// from the selected profile
+type ProbeOverride struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	// InitialDelaySeconds - seconds after the container has started before
+	// the probe is initiated
+	InitialDelaySeconds *int32 `json:"initialDelaySeconds,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// PeriodSeconds - how often (in seconds) to perform the probe
+	PeriodSeconds *int32 `json:"periodSeconds,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// TimeoutSeconds - seconds after which the probe times out
+	TimeoutSeconds *int32 `json:"timeoutSeconds,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// FailureThreshold - consecutive failures for the probe to be considered
+	// failed
+	FailureThreshold *int32 `json:"failureThreshold,omitempty"`
+}
+
+// FibreChannel - Fibre Channel awareness of a service that uses FC storage
+type FibreChannel struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=false
+	// Required - only schedule the service on nodes that have FC HBAs
+	Required bool `json:"required"`
+}
+
+// FibreChannelNodeStatus - FC HBAs discovered on a node
+type FibreChannelNodeStatus struct {
+	// NodeName - name of the node
+	NodeName string `json:"nodeName"`
+
+	// WWPNs - world wide port names of the FC HBAs of the node
+	WWPNs []string `json:"wwpns,omitempty"`
+
+	// Error - reason the discovery failed on the node, its WWPNs are unknown
+	Error string `json:"error,omitempty"`
+}
+
+// AvailabilityZone - availability zone of a service that uses storage
+type AvailabilityZone struct {
+	// +kubebuilder:validation:Optional
+	// Name - availability zone of the service. When empty it's the zone, from
+	// the topology.kubernetes.io/zone label, of the nodes the service can run
+	// on according to its nodeSelector and topologyRef
+	Name string `json:"name,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=false
+	// AllowMultipleZones - allow the nodes the service can run on to be in
+	// different zones, in which case the default availability zone is used
+	AllowMultipleZones bool `json:"allowMultipleZones"`
+}
+
+// AvailabilityZoneFilter - availability zone settings used when scheduling
+// volumes
+type AvailabilityZoneFilter struct {
+	// +kubebuilder:validation:Optional
+	// DefaultAvailabilityZone - availability zone of the volumes created
+	// without one. Defaults to the default availability zone of the services
+	DefaultAvailabilityZone string `json:"defaultAvailabilityZone,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=false
+	// AllowFallback - create the volume in the default availability zone when
+	// the requested one doesn't exist, instead of failing the request
+	AllowFallback bool `json:"allowFallback"`
 }
 
 // PasswordSelector to identify the DB and AdminUser password from the Secret
@@ -99,3 +253,44 @@ func (instance *CinderServiceTemplate) ValidateTopology(
 		*basePath.Child("topologyRef"), namespace)...)
 	return allErrs
 }
+
+// Validate - ensures the availability zone of a service doesn't contradict
+// the zone its nodeSelector places it in
+func (az *AvailabilityZone) Validate(
+	nodeSelector *map[string]string,
+	basePath *field.Path,
+) field.ErrorList {
+	var allErrs field.ErrorList
+	if az == nil || az.Name == "" {
+		return allErrs
+	}
+
+	path := basePath.Child("availabilityZone")
+	if az.AllowMultipleZones {
+		allErrs = append(allErrs, field.Forbidden(
+			path.Child("allowMultipleZones"),
+			"only applies when the zone is derived from the nodes"))
+	}
+	if nodeSelector == nil {
+		return allErrs
+	}
+	if zone, ok := (*nodeSelector)[corev1.LabelTopologyZone]; ok && zone != az.Name {
+		allErrs = append(allErrs, field.Invalid(
+			path.Child("name"), az.Name,
+			fmt.Sprintf("nodeSelector places the service in zone %s", zone)))
+	}
+	return allErrs
+}
+
+// ValidateStorageNetwork - the storage network must be one of the
+// NetworkAttachments of the service
+func (s *CinderServiceTemplate) ValidateStorageNetwork(storageNetwork string, basePath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if storageNetwork == "" || slices.Contains(s.NetworkAttachments, storageNetwork) {
+		return allErrs
+	}
+	allErrs = append(allErrs, field.Invalid(
+		basePath.Child("storageNetwork"), storageNetwork,
+		"must be one of the networkAttachments of the service"))
+	return allErrs
+}
@@ -32,6 +32,33 @@ const (
 
 	// CinderVolumeReadyCondition Status=True condition which indicates if the CinderVolume is configured and operational
 	CinderVolumeReadyCondition condition.Type = "CinderVolumeReady"
+
+	// CinderVolumeBackendsReadyCondition Status=True condition which indicates if all the backends of a CinderVolume are up
+	CinderVolumeBackendsReadyCondition condition.Type = "CinderVolumeBackendsReady"
+
+	// CinderVolumeFailoverReadyCondition Status=True condition which indicates if the backends of a CinderVolume are running on the requested replication target
+	CinderVolumeFailoverReadyCondition condition.Type = "CinderVolumeFailoverReady"
+
+	// CinderVolumeLVMReadyCondition Status=True condition which indicates if the volume group of an LVM backend is ready on its node
+	CinderVolumeLVMReadyCondition condition.Type = "CinderVolumeLVMReady"
+
+	// CinderVolumeHostPrerequisitesReadyCondition Status=True condition which indicates if all the nodes a CinderVolume can run on have the transports it needs
+	CinderVolumeHostPrerequisitesReadyCondition condition.Type = "CinderVolumeHostPrerequisitesReady"
+
+	// FibreChannelReadyCondition Status=True condition which indicates if the FC HBAs of the nodes a CinderVolume or CinderBackup can run on have been discovered
+	FibreChannelReadyCondition condition.Type = "FibreChannelReady"
+
+	// ImageResolvedCondition Status=True condition which indicates if the image of a Cinder service has been pinned to a digest
+	ImageResolvedCondition condition.Type = "ImageResolved"
+
+	// AvailabilityZoneReadyCondition Status=True condition which indicates if the availability zone of a Cinder service has been determined
+	AvailabilityZoneReadyCondition condition.Type = "AvailabilityZoneReady"
+
+	// CinderSchedulerCapabilitiesReadyCondition Status=True condition which indicates if all the CinderScheduler pods have the capabilities of the volume back-ends
+	CinderSchedulerCapabilitiesReadyCondition condition.Type = "CinderSchedulerCapabilitiesReady"
+
+	// NotificationTransportURLReadyCondition Status=True condition which indicates if the RabbitMQ TransportURL used for notifications is ready
+	NotificationTransportURLReadyCondition condition.Type = "NotificationTransportURLReady"
 )
 
 // Cinder Reasons used by API objects.
@@ -77,4 +104,160 @@ const (
 
 	// CinderVolumeReadyRunningMessage
 	CinderVolumeReadyRunningMessage = "CinderVolume deployments in progress"
+
+	//
+	// CinderVolumeBackendsReady condition messages
+	//
+	// CinderVolumeBackendsReadyInitMessage
+	CinderVolumeBackendsReadyInitMessage = "CinderVolume backends not reported"
+
+	// CinderVolumeBackendsReadyMessage
+	CinderVolumeBackendsReadyMessage = "CinderVolume backends are up"
+
+	// CinderVolumeBackendsReadyErrorMessage
+	CinderVolumeBackendsReadyErrorMessage = "CinderVolume backends error occured %s"
+
+	// CinderVolumeBackendsDownMessage
+	CinderVolumeBackendsDownMessage = "CinderVolume backends down: %s"
+
+	//
+	// CinderVolumeFailoverReady condition messages
+	//
+	// CinderVolumeFailoverReadyInitMessage
+	CinderVolumeFailoverReadyInitMessage = "CinderVolume failover not started"
+
+	// CinderVolumeFailoverReadyMessage
+	CinderVolumeFailoverReadyMessage = "CinderVolume backends running on %s"
+
+	// CinderVolumeFailoverReadyRunningMessage
+	CinderVolumeFailoverReadyRunningMessage = "CinderVolume failover to %s in progress"
+
+	// CinderVolumeFailoverReadyWaitingMessage
+	CinderVolumeFailoverReadyWaitingMessage = "CinderVolume failover waiting for the service to be running"
+
+	// CinderVolumeFailoverReadyErrorMessage
+	CinderVolumeFailoverReadyErrorMessage = "CinderVolume failover error occured %s"
+
+	//
+	// CinderVolumeLVMReady condition messages
+	//
+	// CinderVolumeLVMReadyInitMessage
+	CinderVolumeLVMReadyInitMessage = "CinderVolume LVM volume group not started"
+
+	// CinderVolumeLVMReadyMessage
+	CinderVolumeLVMReadyMessage = "CinderVolume LVM volume group %s ready on node %s"
+
+	// CinderVolumeLVMReadyRunningMessage
+	CinderVolumeLVMReadyRunningMessage = "CinderVolume LVM volume group %s being created on node %s"
+
+	// CinderVolumeLVMReadyErrorMessage
+	CinderVolumeLVMReadyErrorMessage = "CinderVolume LVM volume group error occured %s"
+
+	//
+	// CinderVolumeHostPrerequisitesReady condition messages
+	//
+	// CinderVolumeHostPrerequisitesReadyInitMessage
+	CinderVolumeHostPrerequisitesReadyInitMessage = "CinderVolume host prerequisites not verified"
+
+	// CinderVolumeHostPrerequisitesReadyMessage
+	CinderVolumeHostPrerequisitesReadyMessage = "CinderVolume host prerequisites verified on all nodes"
+
+	// CinderVolumeHostPrerequisitesReadyRunningMessage
+	CinderVolumeHostPrerequisitesReadyRunningMessage = "CinderVolume host prerequisites verification in progress"
+
+	// CinderVolumeHostPrerequisitesReadyErrorMessage
+	CinderVolumeHostPrerequisitesReadyErrorMessage = "CinderVolume host prerequisites error occured %s"
+
+	// CinderVolumeHostPrerequisitesFailedMessage
+	CinderVolumeHostPrerequisitesFailedMessage = "CinderVolume host prerequisites failed on nodes: %s"
+
+	// CinderVolumeHostPrerequisitesPendingMessage
+	CinderVolumeHostPrerequisitesPendingMessage = "CinderVolume host prerequisites not verified yet on nodes: %s"
+
+	//
+	// FibreChannelReady condition messages
+	//
+	// FibreChannelReadyInitMessage
+	FibreChannelReadyInitMessage = "Fibre Channel discovery not started"
+
+	// FibreChannelReadyMessage
+	FibreChannelReadyMessage = "Fibre Channel HBAs found on %d of %d nodes"
+
+	// FibreChannelReadyRunningMessage
+	FibreChannelReadyRunningMessage = "Fibre Channel discovery in progress"
+
+	// FibreChannelReadyErrorMessage
+	FibreChannelReadyErrorMessage = "Fibre Channel discovery error occured %s"
+
+	// FibreChannelReadyNoNodesMessage
+	FibreChannelReadyNoNodesMessage = "Fibre Channel HBAs not found on any node"
+
+	// FibreChannelReadyFailedMessage
+	FibreChannelReadyFailedMessage = "Fibre Channel discovery failed on nodes: %s"
+
+	// FibreChannelReadyPendingMessage
+	FibreChannelReadyPendingMessage = "Fibre Channel discovery not completed yet on nodes: %s"
+
+	//
+	// ImageResolved condition messages
+	//
+	// ImageResolvedInitMessage
+	ImageResolvedInitMessage = "Image digest not resolved"
+
+	// ImageResolvedMessage
+	ImageResolvedMessage = "Image pinned to %s"
+
+	// ImageResolvedRunningMessage
+	ImageResolvedRunningMessage = "Image digest resolution in progress"
+
+	// ImageResolvedErrorMessage
+	ImageResolvedErrorMessage = "Image digest resolution error occured %s"
+
+	//
+	// AvailabilityZoneReady condition messages
+	//
+	// AvailabilityZoneReadyInitMessage
+	AvailabilityZoneReadyInitMessage = "Availability zone not determined"
+
+	// AvailabilityZoneReadyMessage
+	AvailabilityZoneReadyMessage = "Availability zone is %s"
+
+	// AvailabilityZoneReadyDefaultMessage
+	AvailabilityZoneReadyDefaultMessage = "Using the default availability zone"
+
+	// AvailabilityZoneReadyErrorMessage
+	AvailabilityZoneReadyErrorMessage = "Availability zone error occured %s"
+
+	// AvailabilityZoneReadyMultipleMessage
+	AvailabilityZoneReadyMultipleMessage = "Service can run on nodes of several availability zones: %s"
+
+	//
+	// CinderSchedulerCapabilitiesReady condition messages
+	//
+	// CinderSchedulerCapabilitiesReadyInitMessage
+	CinderSchedulerCapabilitiesReadyInitMessage = "Scheduler capabilities not checked"
+
+	// CinderSchedulerCapabilitiesReadyMessage
+	CinderSchedulerCapabilitiesReadyMessage = "Schedulers have the capabilities of all the back-ends"
+
+	// CinderSchedulerCapabilitiesReadyRunningMessage
+	CinderSchedulerCapabilitiesReadyRunningMessage = "Waiting for the capabilities of the back-ends on: %s"
+
+	// CinderSchedulerCapabilitiesReadyErrorMessage
+	CinderSchedulerCapabilitiesReadyErrorMessage = "Scheduler capabilities error occured %s"
+
+	//
+	// NotificationTransportURLReady condition messages
+	//
+	// NotificationTransportURLReadyInitMessage
+	NotificationTransportURLReadyInitMessage = "Notification TransportURL not started"
+
+	// NotificationTransportURLReadyMessage
+	NotificationTransportURLReadyMessage = "Notification TransportURL successfully created"
+
+	// NotificationTransportURLReadyRunningMessage
+	NotificationTransportURLReadyRunningMessage = "Notification TransportURL creation in progress"
+
+	// NotificationTransportURLReadyErrorMessage
+	NotificationTransportURLReadyErrorMessage = "Notification TransportURL error occured %s"
 )
@@ -25,6 +25,7 @@ import (
 	"github.com/openstack-k8s-operators/lib-common/modules/common/condition"
 	"github.com/openstack-k8s-operators/lib-common/modules/common/service"
 	"github.com/openstack-k8s-operators/lib-common/modules/storage"
+	"k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -38,6 +39,22 @@ func (in *APIOverrideSpec) DeepCopyInto(out *APIOverrideSpec) {
 			(*out)[key] = *val.DeepCopy()
 		}
 	}
+	if in.IPFamilies != nil {
+		in, out := &in.IPFamilies, &out.IPFamilies
+		*out = make(map[service.Endpoint][]v1.IPFamily, len(*in))
+		for key, val := range *in {
+			var outVal []v1.IPFamily
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				inVal := (*in)[key]
+				in, out := &inVal, &outVal
+				*out = make([]v1.IPFamily, len(*in))
+				copy(*out, *in)
+			}
+			(*out)[key] = outVal
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIOverrideSpec.
@@ -50,6 +67,36 @@ func (in *APIOverrideSpec) DeepCopy() *APIOverrideSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AvailabilityZone) DeepCopyInto(out *AvailabilityZone) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AvailabilityZone.
+func (in *AvailabilityZone) DeepCopy() *AvailabilityZone {
+	if in == nil {
+		return nil
+	}
+	out := new(AvailabilityZone)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AvailabilityZoneFilter) DeepCopyInto(out *AvailabilityZoneFilter) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AvailabilityZoneFilter.
+func (in *AvailabilityZoneFilter) DeepCopy() *AvailabilityZoneFilter {
+	if in == nil {
+		return nil
+	}
+	out := new(AvailabilityZoneFilter)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Cinder) DeepCopyInto(out *Cinder) {
 	*out = *in
@@ -104,6 +151,56 @@ func (in *CinderAPI) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CinderAPIAudit) DeepCopyInto(out *CinderAPIAudit) {
+	*out = *in
+	if in.IgnoreReqList != nil {
+		in, out := &in.IgnoreReqList, &out.IgnoreReqList
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CinderAPIAudit.
+func (in *CinderAPIAudit) DeepCopy() *CinderAPIAudit {
+	if in == nil {
+		return nil
+	}
+	out := new(CinderAPIAudit)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CinderAPIClientAuth) DeepCopyInto(out *CinderAPIClientAuth) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CinderAPIClientAuth.
+func (in *CinderAPIClientAuth) DeepCopy() *CinderAPIClientAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(CinderAPIClientAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CinderAPIKeystone) DeepCopyInto(out *CinderAPIKeystone) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CinderAPIKeystone.
+func (in *CinderAPIKeystone) DeepCopy() *CinderAPIKeystone {
+	if in == nil {
+		return nil
+	}
+	out := new(CinderAPIKeystone)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CinderAPIList) DeepCopyInto(out *CinderAPIList) {
 	*out = *in
@@ -136,10 +233,70 @@ func (in *CinderAPIList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CinderAPIPolicy) DeepCopyInto(out *CinderAPIPolicy) {
+	*out = *in
+	if in.ConfigMapRef != nil {
+		in, out := &in.ConfigMapRef, &out.ConfigMapRef
+		*out = new(PolicyConfigMapRef)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CinderAPIPolicy.
+func (in *CinderAPIPolicy) DeepCopy() *CinderAPIPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(CinderAPIPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CinderAPIRateLimit) DeepCopyInto(out *CinderAPIRateLimit) {
+	*out = *in
+	if in.MaxRequestBodySize != nil {
+		in, out := &in.MaxRequestBodySize, &out.MaxRequestBodySize
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Limits != nil {
+		in, out := &in.Limits, &out.Limits
+		*out = make([]CinderAPIRequestLimit, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CinderAPIRateLimit.
+func (in *CinderAPIRateLimit) DeepCopy() *CinderAPIRateLimit {
+	if in == nil {
+		return nil
+	}
+	out := new(CinderAPIRateLimit)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CinderAPIRequestLimit) DeepCopyInto(out *CinderAPIRequestLimit) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CinderAPIRequestLimit.
+func (in *CinderAPIRequestLimit) DeepCopy() *CinderAPIRequestLimit {
+	if in == nil {
+		return nil
+	}
+	out := new(CinderAPIRequestLimit)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CinderAPISpec) DeepCopyInto(out *CinderAPISpec) {
 	*out = *in
-	out.CinderTemplate = in.CinderTemplate
+	in.CinderTemplate.DeepCopyInto(&out.CinderTemplate)
 	in.CinderAPITemplate.DeepCopyInto(&out.CinderAPITemplate)
 	if in.ExtraMounts != nil {
 		in, out := &in.ExtraMounts, &out.ExtraMounts
@@ -223,6 +380,11 @@ func (in *CinderAPIStatus) DeepCopyInto(out *CinderAPIStatus) {
 		*out = new(topologyv1beta1.TopoRef)
 		**out = **in
 	}
+	if in.Image != nil {
+		in, out := &in.Image, &out.Image
+		*out = new(CinderImageStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CinderAPIStatus.
@@ -262,6 +424,38 @@ func (in *CinderAPITemplateCore) DeepCopyInto(out *CinderAPITemplateCore) {
 	}
 	in.Override.DeepCopyInto(&out.Override)
 	in.TLS.DeepCopyInto(&out.TLS)
+	if in.Audit != nil {
+		in, out := &in.Audit, &out.Audit
+		*out = new(CinderAPIAudit)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Policy != nil {
+		in, out := &in.Policy, &out.Policy
+		*out = new(CinderAPIPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RateLimit != nil {
+		in, out := &in.RateLimit, &out.RateLimit
+		*out = new(CinderAPIRateLimit)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.InternalClientAuth != nil {
+		in, out := &in.InternalClientAuth, &out.InternalClientAuth
+		*out = new(CinderAPIClientAuth)
+		**out = **in
+	}
+	if in.EndpointNetworks != nil {
+		in, out := &in.EndpointNetworks, &out.EndpointNetworks
+		*out = make(map[service.Endpoint]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Keystone != nil {
+		in, out := &in.Keystone, &out.Keystone
+		*out = new(CinderAPIKeystone)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CinderAPITemplateCore.
@@ -336,7 +530,7 @@ func (in *CinderBackupList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CinderBackupSpec) DeepCopyInto(out *CinderBackupSpec) {
 	*out = *in
-	out.CinderTemplate = in.CinderTemplate
+	in.CinderTemplate.DeepCopyInto(&out.CinderTemplate)
 	in.CinderBackupTemplate.DeepCopyInto(&out.CinderBackupTemplate)
 	if in.ExtraMounts != nil {
 		in, out := &in.ExtraMounts, &out.ExtraMounts
@@ -396,6 +590,18 @@ func (in *CinderBackupStatus) DeepCopyInto(out *CinderBackupStatus) {
 		*out = new(topologyv1beta1.TopoRef)
 		**out = **in
 	}
+	if in.FibreChannel != nil {
+		in, out := &in.FibreChannel, &out.FibreChannel
+		*out = make([]FibreChannelNodeStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Image != nil {
+		in, out := &in.Image, &out.Image
+		*out = new(CinderImageStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CinderBackupStatus.
@@ -433,6 +639,16 @@ func (in *CinderBackupTemplateCore) DeepCopyInto(out *CinderBackupTemplateCore)
 		*out = new(int32)
 		**out = **in
 	}
+	if in.FibreChannel != nil {
+		in, out := &in.FibreChannel, &out.FibreChannel
+		*out = new(FibreChannel)
+		**out = **in
+	}
+	if in.AvailabilityZone != nil {
+		in, out := &in.AvailabilityZone, &out.AvailabilityZone
+		*out = new(AvailabilityZone)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CinderBackupTemplateCore.
@@ -460,6 +676,21 @@ func (in *CinderDefaults) DeepCopy() *CinderDefaults {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CinderDriverPlugin) DeepCopyInto(out *CinderDriverPlugin) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CinderDriverPlugin.
+func (in *CinderDriverPlugin) DeepCopy() *CinderDriverPlugin {
+	if in == nil {
+		return nil
+	}
+	out := new(CinderDriverPlugin)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CinderExtraVolMounts) DeepCopyInto(out *CinderExtraVolMounts) {
 	*out = *in
@@ -482,6 +713,28 @@ func (in *CinderExtraVolMounts) DeepCopy() *CinderExtraVolMounts {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CinderImageStatus) DeepCopyInto(out *CinderImageStatus) {
+	*out = *in
+	if in.PodImages != nil {
+		in, out := &in.PodImages, &out.PodImages
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CinderImageStatus.
+func (in *CinderImageStatus) DeepCopy() *CinderImageStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CinderImageStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CinderList) DeepCopyInto(out *CinderList) {
 	*out = *in
@@ -576,7 +829,7 @@ func (in *CinderSchedulerList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CinderSchedulerSpec) DeepCopyInto(out *CinderSchedulerSpec) {
 	*out = *in
-	out.CinderTemplate = in.CinderTemplate
+	in.CinderTemplate.DeepCopyInto(&out.CinderTemplate)
 	in.CinderSchedulerTemplate.DeepCopyInto(&out.CinderSchedulerTemplate)
 	if in.ExtraMounts != nil {
 		in, out := &in.ExtraMounts, &out.ExtraMounts
@@ -636,6 +889,11 @@ func (in *CinderSchedulerStatus) DeepCopyInto(out *CinderSchedulerStatus) {
 		*out = new(topologyv1beta1.TopoRef)
 		**out = **in
 	}
+	if in.Image != nil {
+		in, out := &in.Image, &out.Image
+		*out = new(CinderImageStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CinderSchedulerStatus.
@@ -673,6 +931,16 @@ func (in *CinderSchedulerTemplateCore) DeepCopyInto(out *CinderSchedulerTemplate
 		*out = new(int32)
 		**out = **in
 	}
+	if in.AvailabilityZoneFilter != nil {
+		in, out := &in.AvailabilityZoneFilter, &out.AvailabilityZoneFilter
+		*out = new(AvailabilityZoneFilter)
+		**out = **in
+	}
+	if in.SchedulerPolicy != nil {
+		in, out := &in.SchedulerPolicy, &out.SchedulerPolicy
+		*out = new(SchedulerPolicy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CinderSchedulerTemplateCore.
@@ -715,6 +983,11 @@ func (in *CinderServiceTemplate) DeepCopyInto(out *CinderServiceTemplate) {
 		*out = new(topologyv1beta1.TopoRef)
 		**out = **in
 	}
+	if in.Probes != nil {
+		in, out := &in.Probes, &out.Probes
+		*out = new(ProbeOverrides)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CinderServiceTemplate.
@@ -756,7 +1029,7 @@ func (in *CinderSpec) DeepCopy() *CinderSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CinderSpecBase) DeepCopyInto(out *CinderSpecBase) {
 	*out = *in
-	out.CinderTemplate = in.CinderTemplate
+	in.CinderTemplate.DeepCopyInto(&out.CinderTemplate)
 	if in.ExtraMounts != nil {
 		in, out := &in.ExtraMounts, &out.ExtraMounts
 		*out = make([]CinderExtraVolMounts, len(*in))
@@ -781,6 +1054,7 @@ func (in *CinderSpecBase) DeepCopyInto(out *CinderSpecBase) {
 		*out = new(topologyv1beta1.TopoRef)
 		**out = **in
 	}
+	in.Notifications.DeepCopyInto(&out.Notifications)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CinderSpecBase.
@@ -836,6 +1110,7 @@ func (in *CinderStatus) DeepCopyInto(out *CinderStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	out.TLS = in.TLS
 	if in.APIEndpoints != nil {
 		in, out := &in.APIEndpoints, &out.APIEndpoints
 		*out = make(map[string]map[string]string, len(*in))
@@ -868,6 +1143,29 @@ func (in *CinderStatus) DeepCopyInto(out *CinderStatus) {
 			(*out)[key] = val
 		}
 	}
+	if in.CinderVolumesBackends != nil {
+		in, out := &in.CinderVolumesBackends, &out.CinderVolumesBackends
+		*out = make(map[string][]CinderVolumeBackendStatus, len(*in))
+		for key, val := range *in {
+			var outVal []CinderVolumeBackendStatus
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				inVal := (*in)[key]
+				in, out := &inVal, &outVal
+				*out = make([]CinderVolumeBackendStatus, len(*in))
+				for i := range *in {
+					(*in)[i].DeepCopyInto(&(*out)[i])
+				}
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.Image != nil {
+		in, out := &in.Image, &out.Image
+		*out = new(CinderImageStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CinderStatus.
@@ -880,10 +1178,30 @@ func (in *CinderStatus) DeepCopy() *CinderStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CinderTLSStatus) DeepCopyInto(out *CinderTLSStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CinderTLSStatus.
+func (in *CinderTLSStatus) DeepCopy() *CinderTLSStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CinderTLSStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CinderTemplate) DeepCopyInto(out *CinderTemplate) {
 	*out = *in
 	out.PasswordSelectors = in.PasswordSelectors
+	if in.ImagePinning != nil {
+		in, out := &in.ImagePinning, &out.ImagePinning
+		*out = new(ImagePinning)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CinderTemplate.
@@ -923,6 +1241,90 @@ func (in *CinderVolume) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CinderVolumeBackendCapabilities) DeepCopyInto(out *CinderVolumeBackendCapabilities) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CinderVolumeBackendCapabilities.
+func (in *CinderVolumeBackendCapabilities) DeepCopy() *CinderVolumeBackendCapabilities {
+	if in == nil {
+		return nil
+	}
+	out := new(CinderVolumeBackendCapabilities)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CinderVolumeBackendStatus) DeepCopyInto(out *CinderVolumeBackendStatus) {
+	*out = *in
+	if in.LastHeartbeat != nil {
+		in, out := &in.LastHeartbeat, &out.LastHeartbeat
+		*out = (*in).DeepCopy()
+	}
+	if in.Capabilities != nil {
+		in, out := &in.Capabilities, &out.Capabilities
+		*out = new(CinderVolumeBackendCapabilities)
+		**out = **in
+	}
+	if in.Pools != nil {
+		in, out := &in.Pools, &out.Pools
+		*out = make([]CinderVolumePoolStatus, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CinderVolumeBackendStatus.
+func (in *CinderVolumeBackendStatus) DeepCopy() *CinderVolumeBackendStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CinderVolumeBackendStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CinderVolumeLVM) DeepCopyInto(out *CinderVolumeLVM) {
+	*out = *in
+	if in.Devices != nil {
+		in, out := &in.Devices, &out.Devices
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LoopFileSize != nil {
+		in, out := &in.LoopFileSize, &out.LoopFileSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CinderVolumeLVM.
+func (in *CinderVolumeLVM) DeepCopy() *CinderVolumeLVM {
+	if in == nil {
+		return nil
+	}
+	out := new(CinderVolumeLVM)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CinderVolumeLVMStatus) DeepCopyInto(out *CinderVolumeLVMStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CinderVolumeLVMStatus.
+func (in *CinderVolumeLVMStatus) DeepCopy() *CinderVolumeLVMStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CinderVolumeLVMStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CinderVolumeList) DeepCopyInto(out *CinderVolumeList) {
 	*out = *in
@@ -955,10 +1357,69 @@ func (in *CinderVolumeList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CinderVolumePoolStatus) DeepCopyInto(out *CinderVolumePoolStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CinderVolumePoolStatus.
+func (in *CinderVolumePoolStatus) DeepCopy() *CinderVolumePoolStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CinderVolumePoolStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CinderVolumeReplication) DeepCopyInto(out *CinderVolumeReplication) {
+	*out = *in
+	if in.Devices != nil {
+		in, out := &in.Devices, &out.Devices
+		*out = make([]CinderVolumeReplicationDevice, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CinderVolumeReplication.
+func (in *CinderVolumeReplication) DeepCopy() *CinderVolumeReplication {
+	if in == nil {
+		return nil
+	}
+	out := new(CinderVolumeReplication)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CinderVolumeReplicationDevice) DeepCopyInto(out *CinderVolumeReplicationDevice) {
+	*out = *in
+	if in.Options != nil {
+		in, out := &in.Options, &out.Options
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CinderVolumeReplicationDevice.
+func (in *CinderVolumeReplicationDevice) DeepCopy() *CinderVolumeReplicationDevice {
+	if in == nil {
+		return nil
+	}
+	out := new(CinderVolumeReplicationDevice)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CinderVolumeSpec) DeepCopyInto(out *CinderVolumeSpec) {
 	*out = *in
-	out.CinderTemplate = in.CinderTemplate
+	in.CinderTemplate.DeepCopyInto(&out.CinderTemplate)
 	in.CinderVolumeTemplate.DeepCopyInto(&out.CinderVolumeTemplate)
 	if in.ExtraMounts != nil {
 		in, out := &in.ExtraMounts, &out.ExtraMounts
@@ -968,6 +1429,11 @@ func (in *CinderVolumeSpec) DeepCopyInto(out *CinderVolumeSpec) {
 		}
 	}
 	out.TLS = in.TLS
+	if in.SchedulerFunctions != nil {
+		in, out := &in.SchedulerFunctions, &out.SchedulerFunctions
+		*out = new(SchedulerBackendFunctions)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CinderVolumeSpec.
@@ -1018,6 +1484,30 @@ func (in *CinderVolumeStatus) DeepCopyInto(out *CinderVolumeStatus) {
 		*out = new(topologyv1beta1.TopoRef)
 		**out = **in
 	}
+	if in.Backends != nil {
+		in, out := &in.Backends, &out.Backends
+		*out = make([]CinderVolumeBackendStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LVM != nil {
+		in, out := &in.LVM, &out.LVM
+		*out = new(CinderVolumeLVMStatus)
+		**out = **in
+	}
+	if in.FibreChannel != nil {
+		in, out := &in.FibreChannel, &out.FibreChannel
+		*out = make([]FibreChannelNodeStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Image != nil {
+		in, out := &in.Image, &out.Image
+		*out = new(CinderImageStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CinderVolumeStatus.
@@ -1055,6 +1545,36 @@ func (in *CinderVolumeTemplateCore) DeepCopyInto(out *CinderVolumeTemplateCore)
 		*out = new(int32)
 		**out = **in
 	}
+	if in.Replication != nil {
+		in, out := &in.Replication, &out.Replication
+		*out = new(CinderVolumeReplication)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LVM != nil {
+		in, out := &in.LVM, &out.LVM
+		*out = new(CinderVolumeLVM)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HostPrerequisites != nil {
+		in, out := &in.HostPrerequisites, &out.HostPrerequisites
+		*out = make([]HostPrerequisite, len(*in))
+		copy(*out, *in)
+	}
+	if in.FibreChannel != nil {
+		in, out := &in.FibreChannel, &out.FibreChannel
+		*out = new(FibreChannel)
+		**out = **in
+	}
+	if in.AvailabilityZone != nil {
+		in, out := &in.AvailabilityZone, &out.AvailabilityZone
+		*out = new(AvailabilityZone)
+		**out = **in
+	}
+	if in.DriverPlugins != nil {
+		in, out := &in.DriverPlugins, &out.DriverPlugins
+		*out = make([]CinderDriverPlugin, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CinderVolumeTemplateCore.
@@ -1082,6 +1602,76 @@ func (in *DBPurge) DeepCopy() *DBPurge {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FibreChannel) DeepCopyInto(out *FibreChannel) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FibreChannel.
+func (in *FibreChannel) DeepCopy() *FibreChannel {
+	if in == nil {
+		return nil
+	}
+	out := new(FibreChannel)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FibreChannelNodeStatus) DeepCopyInto(out *FibreChannelNodeStatus) {
+	*out = *in
+	if in.WWPNs != nil {
+		in, out := &in.WWPNs, &out.WWPNs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FibreChannelNodeStatus.
+func (in *FibreChannelNodeStatus) DeepCopy() *FibreChannelNodeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(FibreChannelNodeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImagePinning) DeepCopyInto(out *ImagePinning) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImagePinning.
+func (in *ImagePinning) DeepCopy() *ImagePinning {
+	if in == nil {
+		return nil
+	}
+	out := new(ImagePinning)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Notifications) DeepCopyInto(out *Notifications) {
+	*out = *in
+	if in.Topics != nil {
+		in, out := &in.Topics, &out.Topics
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Notifications.
+func (in *Notifications) DeepCopy() *Notifications {
+	if in == nil {
+		return nil
+	}
+	out := new(Notifications)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PasswordSelector) DeepCopyInto(out *PasswordSelector) {
 	*out = *in
@@ -1096,3 +1686,135 @@ func (in *PasswordSelector) DeepCopy() *PasswordSelector {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PolicyConfigMapRef) DeepCopyInto(out *PolicyConfigMapRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolicyConfigMapRef.
+func (in *PolicyConfigMapRef) DeepCopy() *PolicyConfigMapRef {
+	if in == nil {
+		return nil
+	}
+	out := new(PolicyConfigMapRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProbeOverride) DeepCopyInto(out *ProbeOverride) {
+	*out = *in
+	if in.InitialDelaySeconds != nil {
+		in, out := &in.InitialDelaySeconds, &out.InitialDelaySeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.PeriodSeconds != nil {
+		in, out := &in.PeriodSeconds, &out.PeriodSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TimeoutSeconds != nil {
+		in, out := &in.TimeoutSeconds, &out.TimeoutSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.FailureThreshold != nil {
+		in, out := &in.FailureThreshold, &out.FailureThreshold
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProbeOverride.
+func (in *ProbeOverride) DeepCopy() *ProbeOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(ProbeOverride)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProbeOverrides) DeepCopyInto(out *ProbeOverrides) {
+	*out = *in
+	if in.Startup != nil {
+		in, out := &in.Startup, &out.Startup
+		*out = new(ProbeOverride)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Liveness != nil {
+		in, out := &in.Liveness, &out.Liveness
+		*out = new(ProbeOverride)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Readiness != nil {
+		in, out := &in.Readiness, &out.Readiness
+		*out = new(ProbeOverride)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProbeOverrides.
+func (in *ProbeOverrides) DeepCopy() *ProbeOverrides {
+	if in == nil {
+		return nil
+	}
+	out := new(ProbeOverrides)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SchedulerBackendFunctions) DeepCopyInto(out *SchedulerBackendFunctions) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchedulerBackendFunctions.
+func (in *SchedulerBackendFunctions) DeepCopy() *SchedulerBackendFunctions {
+	if in == nil {
+		return nil
+	}
+	out := new(SchedulerBackendFunctions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SchedulerPolicy) DeepCopyInto(out *SchedulerPolicy) {
+	*out = *in
+	if in.Filters != nil {
+		in, out := &in.Filters, &out.Filters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Weighers != nil {
+		in, out := &in.Weighers, &out.Weighers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DriverInitWaitTime != nil {
+		in, out := &in.DriverInitWaitTime, &out.DriverInitWaitTime
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Backends != nil {
+		in, out := &in.Backends, &out.Backends
+		*out = make(map[string]SchedulerBackendFunctions, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchedulerPolicy.
+func (in *SchedulerPolicy) DeepCopy() *SchedulerPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(SchedulerPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -22,12 +22,21 @@ import (
 	"github.com/openstack-k8s-operators/lib-common/modules/common/condition"
 	"github.com/openstack-k8s-operators/lib-common/modules/common/secret"
 	"k8s.io/apimachinery/pkg/types"
+	"sort"
+	"strings"
 	"time"
 
+	cinderv1beta1 "github.com/openstack-k8s-operators/cinder-operator/api/v1beta1"
 	"github.com/openstack-k8s-operators/cinder-operator/pkg/cinder"
 	topologyv1 "github.com/openstack-k8s-operators/infra-operator/apis/topology/v1beta1"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/daemonset"
 	"github.com/openstack-k8s-operators/lib-common/modules/common/env"
 	"github.com/openstack-k8s-operators/lib-common/modules/common/helper"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/job"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/pod"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
 	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -160,3 +169,393 @@ func verifyConfigSecrets(
 
 	return ctrl.Result{}, nil
 }
+
+// ensureFibreChannel - Runs the DaemonSet that discovers the FC HBAs of the
+// nodes a CinderVolume or CinderBackup can run on, and labels the nodes that
+// have them. It returns the WWPNs of the nodes that completed the discovery
+// and the nodes where it failed. The nodes where it's pending or failed don't
+// hold back the service, which only runs on the labeled nodes when FC is
+// required.
+func ensureFibreChannel(
+	ctx context.Context,
+	h *helper.Helper,
+	dsDef *appsv1.DaemonSet,
+	required bool,
+	conditionUpdater conditionUpdater,
+) ([]cinderv1beta1.FibreChannelNodeStatus, ctrl.Result, error) {
+	ds := daemonset.NewDaemonSet(dsDef, cinder.ShortDuration)
+	ctrlResult, err := ds.CreateOrPatch(ctx, h)
+	if err != nil {
+		conditionUpdater.Set(condition.FalseCondition(
+			cinderv1beta1.FibreChannelReadyCondition,
+			condition.ErrorReason,
+			condition.SeverityWarning,
+			cinderv1beta1.FibreChannelReadyErrorMessage,
+			err.Error()))
+		return nil, ctrlResult, err
+	}
+
+	// Wait until there is an up to date pod on every node
+	dsData := ds.GetDaemonSet()
+	if (ctrlResult != ctrl.Result{}) ||
+		dsData.Generation != dsData.Status.ObservedGeneration ||
+		dsData.Status.UpdatedNumberScheduled != dsData.Status.DesiredNumberScheduled {
+		conditionUpdater.Set(condition.FalseCondition(
+			cinderv1beta1.FibreChannelReadyCondition,
+			condition.RequestedReason,
+			condition.SeverityInfo,
+			cinderv1beta1.FibreChannelReadyRunningMessage))
+		return nil, cinder.ResultRequeue, nil
+	}
+
+	podList, err := pod.GetPodListWithLabel(ctx, h, dsDef.Namespace, dsDef.Spec.Selector.MatchLabels)
+	if err != nil {
+		conditionUpdater.Set(condition.FalseCondition(
+			cinderv1beta1.FibreChannelReadyCondition,
+			condition.ErrorReason,
+			condition.SeverityWarning,
+			cinderv1beta1.FibreChannelReadyErrorMessage,
+			err.Error()))
+		return nil, ctrl.Result{}, err
+	}
+
+	results := cinder.GetFCDiscoveryResults(podList.Items)
+
+	fcNodes := 0
+	for _, fcNode := range results.Nodes {
+		hasFC := len(fcNode.WWPNs) > 0
+		if hasFC {
+			fcNodes++
+		}
+		err = labelFibreChannelNode(ctx, h, fcNode.NodeName, hasFC)
+		if err != nil {
+			conditionUpdater.Set(condition.FalseCondition(
+				cinderv1beta1.FibreChannelReadyCondition,
+				condition.ErrorReason,
+				condition.SeverityWarning,
+				cinderv1beta1.FibreChannelReadyErrorMessage,
+				err.Error()))
+			return nil, ctrl.Result{}, err
+		}
+	}
+
+	// The nodes where the discovery failed are reported in the status too
+	failedNodes := results.FailedNodes()
+	fcStatus := results.Nodes
+	for _, node := range failedNodes {
+		fcStatus = append(fcStatus, cinderv1beta1.FibreChannelNodeStatus{
+			NodeName: node,
+			Error:    results.Failed[node],
+		})
+	}
+	sort.Slice(fcStatus, func(i, j int) bool {
+		return fcStatus[i].NodeName < fcStatus[j].NodeName
+	})
+
+	switch {
+	case len(failedNodes) > 0:
+		failures := []string{}
+		for _, node := range failedNodes {
+			failures = append(failures, fmt.Sprintf("%s (%s)", node, results.Failed[node]))
+		}
+		log.FromContext(ctx).Info(fmt.Sprintf("Fibre Channel discovery failed on nodes %s", strings.Join(failedNodes, ", ")))
+		conditionUpdater.Set(condition.FalseCondition(
+			cinderv1beta1.FibreChannelReadyCondition,
+			condition.ErrorReason,
+			condition.SeverityWarning,
+			cinderv1beta1.FibreChannelReadyFailedMessage,
+			strings.Join(failures, ", ")))
+	case len(results.Pending) > 0:
+		conditionUpdater.Set(condition.FalseCondition(
+			cinderv1beta1.FibreChannelReadyCondition,
+			condition.RequestedReason,
+			condition.SeverityInfo,
+			cinderv1beta1.FibreChannelReadyPendingMessage,
+			strings.Join(results.Pending, ", ")))
+	case required && fcNodes == 0:
+		conditionUpdater.Set(condition.FalseCondition(
+			cinderv1beta1.FibreChannelReadyCondition,
+			condition.ErrorReason,
+			condition.SeverityWarning,
+			cinderv1beta1.FibreChannelReadyNoNodesMessage))
+	default:
+		conditionUpdater.MarkTrue(
+			cinderv1beta1.FibreChannelReadyCondition,
+			cinderv1beta1.FibreChannelReadyMessage,
+			fcNodes,
+			len(results.Nodes))
+	}
+	return fcStatus, ctrl.Result{}, nil
+}
+
+// labelFibreChannelNode - Adds the FC label to a node that has FC HBAs, or
+// removes it from one that doesn't
+func labelFibreChannelNode(
+	ctx context.Context,
+	h *helper.Helper,
+	nodeName string,
+	hasFC bool,
+) error {
+	node := &corev1.Node{}
+	err := h.GetClient().Get(ctx, types.NamespacedName{Name: nodeName}, node)
+	if err != nil {
+		return err
+	}
+
+	_, labeled := node.Labels[cinder.FibreChannelNodeLabel]
+	if labeled == hasFC {
+		return nil
+	}
+
+	patch := client.MergeFrom(node.DeepCopy())
+	if hasFC {
+		if node.Labels == nil {
+			node.Labels = map[string]string{}
+		}
+		node.Labels[cinder.FibreChannelNodeLabel] = "true"
+	} else {
+		delete(node.Labels, cinder.FibreChannelNodeLabel)
+	}
+	log.FromContext(ctx).Info(fmt.Sprintf("Setting %s label of node %s to %t", cinder.FibreChannelNodeLabel, nodeName, hasFC))
+	return h.GetClient().Patch(ctx, node, patch)
+}
+
+// deleteFibreChannelDiscovery - Deletes the FC discovery DaemonSet of a
+// CinderVolume or CinderBackup that is no longer FC aware. The node labels
+// are kept because they may be used by other services.
+func deleteFibreChannelDiscovery(
+	ctx context.Context,
+	h *helper.Helper,
+	instance client.Object,
+) error {
+	ds, err := daemonset.GetDaemonSetWithName(
+		ctx, h, cinder.GetFCDiscoveryName(instance), instance.GetNamespace())
+	if err != nil {
+		if k8s_errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	return daemonset.NewDaemonSet(ds, cinder.ShortDuration).Delete(ctx, h)
+}
+
+// ensureImage - Returns the image a Cinder service must be deployed with.
+// When image pinning is enabled the tag of the image is resolved to a digest
+// with a job, and the digest is kept until the image in the spec or the
+// refresh field of the pinning change, so the service only rolls when the
+// digest changes.
+func ensureImage(
+	ctx context.Context,
+	h *helper.Helper,
+	instance client.Object,
+	containerImage string,
+	serviceAccount string,
+	pinning *cinderv1beta1.ImagePinning,
+	serviceLabels map[string]string,
+	imageStatus *cinderv1beta1.CinderImageStatus,
+	hashes map[string]string,
+	conditionUpdater conditionUpdater,
+) (string, ctrl.Result, error) {
+	if pinning == nil || cinder.IsPinnedImage(containerImage) {
+		imageStatus.ContainerImage = containerImage
+		imageStatus.ResolvedImage = containerImage
+		imageStatus.Refresh = ""
+		return containerImage, ctrl.Result{}, nil
+	}
+
+	if imageStatus.ResolvedImage != "" &&
+		imageStatus.ContainerImage == containerImage &&
+		imageStatus.Refresh == pinning.Refresh {
+		conditionUpdater.MarkTrue(
+			cinderv1beta1.ImageResolvedCondition,
+			cinderv1beta1.ImageResolvedMessage,
+			imageStatus.ResolvedImage)
+		return imageStatus.ResolvedImage, ctrl.Result{}, nil
+	}
+
+	jobDef := cinder.ImageResolveJob(instance, containerImage, serviceAccount, pinning.Refresh, serviceLabels)
+	resolveJob := job.NewJob(
+		jobDef,
+		cinder.ImageResolveHash,
+		false,
+		cinder.ShortDuration,
+		hashes[cinder.ImageResolveHash],
+	)
+	ctrlResult, err := resolveJob.DoJob(ctx, h)
+	if (ctrlResult != ctrl.Result{}) {
+		conditionUpdater.Set(condition.FalseCondition(
+			cinderv1beta1.ImageResolvedCondition,
+			condition.RequestedReason,
+			condition.SeverityInfo,
+			cinderv1beta1.ImageResolvedRunningMessage))
+		return "", ctrlResult, nil
+	}
+	if err != nil {
+		conditionUpdater.Set(condition.FalseCondition(
+			cinderv1beta1.ImageResolvedCondition,
+			condition.ErrorReason,
+			condition.SeverityWarning,
+			cinderv1beta1.ImageResolvedErrorMessage,
+			err.Error()))
+		return "", ctrl.Result{}, err
+	}
+
+	// The digest is in the status of the pod of the job
+	podList, err := pod.GetPodListWithLabel(
+		ctx, h, instance.GetNamespace(), map[string]string{batchv1.JobNameLabel: jobDef.Name})
+	if err != nil {
+		conditionUpdater.Set(condition.FalseCondition(
+			cinderv1beta1.ImageResolvedCondition,
+			condition.ErrorReason,
+			condition.SeverityWarning,
+			cinderv1beta1.ImageResolvedErrorMessage,
+			err.Error()))
+		return "", ctrl.Result{}, err
+	}
+	imageID := ""
+	for _, p := range podList.Items {
+		if p.Status.Phase == corev1.PodSucceeded && len(p.Status.ContainerStatuses) > 0 {
+			imageID = p.Status.ContainerStatuses[0].ImageID
+		}
+	}
+	if imageID == "" {
+		// The job is gone, run it again
+		delete(hashes, cinder.ImageResolveHash)
+		conditionUpdater.Set(condition.FalseCondition(
+			cinderv1beta1.ImageResolvedCondition,
+			condition.RequestedReason,
+			condition.SeverityInfo,
+			cinderv1beta1.ImageResolvedRunningMessage))
+		return "", cinder.ResultRequeue, nil
+	}
+
+	resolvedImage, err := cinder.GetPinnedImage(containerImage, imageID)
+	if err != nil {
+		conditionUpdater.Set(condition.FalseCondition(
+			cinderv1beta1.ImageResolvedCondition,
+			condition.ErrorReason,
+			condition.SeverityWarning,
+			cinderv1beta1.ImageResolvedErrorMessage,
+			err.Error()))
+		return "", ctrl.Result{}, err
+	}
+	if resolveJob.HasChanged() {
+		hashes[cinder.ImageResolveHash] = resolveJob.GetHash()
+	}
+	log.FromContext(ctx).Info(fmt.Sprintf("Image %s resolved to %s", containerImage, resolvedImage))
+
+	imageStatus.ContainerImage = containerImage
+	imageStatus.ResolvedImage = resolvedImage
+	imageStatus.Refresh = pinning.Refresh
+	conditionUpdater.MarkTrue(
+		cinderv1beta1.ImageResolvedCondition,
+		cinderv1beta1.ImageResolvedMessage,
+		resolvedImage)
+	return resolvedImage, ctrl.Result{}, nil
+}
+
+// getPodImages - Returns the image ID each running pod of a Cinder service is
+// using for the service container
+func getPodImages(
+	ctx context.Context,
+	h *helper.Helper,
+	namespace string,
+	serviceLabels map[string]string,
+	containerName string,
+) (map[string]string, error) {
+	podList, err := pod.GetPodListWithLabel(ctx, h, namespace, serviceLabels)
+	if err != nil {
+		return nil, err
+	}
+	return cinder.GetPodImages(podList.Items, containerName), nil
+}
+
+// ensureAvailabilityZone - returns the availability zone of a service, which
+// is the zone of the nodes it can run on unless one is set explicitly. An
+// empty zone means the default availability zone is used.
+func ensureAvailabilityZone(
+	ctx context.Context,
+	h *helper.Helper,
+	namespace string,
+	az *cinderv1beta1.AvailabilityZone,
+	nodeSelector *map[string]string,
+	topologyRef *topologyv1.TopoRef,
+	conditionUpdater conditionUpdater,
+) (string, error) {
+	if az.Name != "" {
+		conditionUpdater.MarkTrue(
+			cinderv1beta1.AvailabilityZoneReadyCondition,
+			cinderv1beta1.AvailabilityZoneReadyMessage,
+			az.Name)
+		return az.Name, nil
+	}
+
+	var affinity *corev1.Affinity
+	if topologyRef != nil {
+		topology, _, err := topologyv1.GetTopologyByName(ctx, h, topologyRef.Name, namespace)
+		if err != nil {
+			conditionUpdater.Set(condition.FalseCondition(
+				cinderv1beta1.AvailabilityZoneReadyCondition,
+				condition.ErrorReason,
+				condition.SeverityWarning,
+				cinderv1beta1.AvailabilityZoneReadyErrorMessage,
+				err.Error()))
+			return "", err
+		}
+		affinity = topology.Spec.Affinity
+	}
+
+	nodeList := &corev1.NodeList{}
+	if err := h.GetClient().List(ctx, nodeList); err != nil {
+		conditionUpdater.Set(condition.FalseCondition(
+			cinderv1beta1.AvailabilityZoneReadyCondition,
+			condition.ErrorReason,
+			condition.SeverityWarning,
+			cinderv1beta1.AvailabilityZoneReadyErrorMessage,
+			err.Error()))
+		return "", err
+	}
+	nodes, err := cinder.GetSchedulableNodes(nodeList.Items, nodeSelector, affinity)
+	if err != nil {
+		conditionUpdater.Set(condition.FalseCondition(
+			cinderv1beta1.AvailabilityZoneReadyCondition,
+			condition.ErrorReason,
+			condition.SeverityWarning,
+			cinderv1beta1.AvailabilityZoneReadyErrorMessage,
+			err.Error()))
+		return "", err
+	}
+
+	zones := cinder.GetNodeZones(nodes)
+	for _, node := range nodes {
+		// Nodes without a zone would run the service outside of it
+		if len(zones) > 0 && node.Labels[corev1.LabelTopologyZone] == "" {
+			zones = append(zones, fmt.Sprintf("none (%s)", node.Name))
+			break
+		}
+	}
+
+	if len(zones) == 1 {
+		conditionUpdater.MarkTrue(
+			cinderv1beta1.AvailabilityZoneReadyCondition,
+			cinderv1beta1.AvailabilityZoneReadyMessage,
+			zones[0])
+		return zones[0], nil
+	}
+
+	if len(zones) > 1 && !az.AllowMultipleZones {
+		err := fmt.Errorf("service can run on nodes of several availability zones: %s", strings.Join(zones, ", "))
+		conditionUpdater.Set(condition.FalseCondition(
+			cinderv1beta1.AvailabilityZoneReadyCondition,
+			condition.ErrorReason,
+			condition.SeverityError,
+			cinderv1beta1.AvailabilityZoneReadyMultipleMessage,
+			strings.Join(zones, ", ")))
+		return "", err
+	}
+
+	conditionUpdater.MarkTrue(
+		cinderv1beta1.AvailabilityZoneReadyCondition,
+		cinderv1beta1.AvailabilityZoneReadyDefaultMessage)
+	return "", nil
+}
@@ -19,6 +19,7 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
@@ -36,6 +37,7 @@ import (
 	networkv1 "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
 	cinderv1beta1 "github.com/openstack-k8s-operators/cinder-operator/api/v1beta1"
 	"github.com/openstack-k8s-operators/cinder-operator/pkg/cinder"
+	"github.com/openstack-k8s-operators/cinder-operator/pkg/cinderapi"
 	memcachedv1 "github.com/openstack-k8s-operators/infra-operator/apis/memcached/v1beta1"
 	rabbitmqv1 "github.com/openstack-k8s-operators/infra-operator/apis/rabbitmq/v1beta1"
 	keystonev1 "github.com/openstack-k8s-operators/keystone-operator/api/v1beta1"
@@ -199,6 +201,12 @@ func (r *CinderReconciler) Reconcile(ctx context.Context, req ctrl.Request) (res
 		condition.UnknownCondition(condition.RoleReadyCondition, condition.InitReason, condition.RoleReadyInitMessage),
 		condition.UnknownCondition(condition.RoleBindingReadyCondition, condition.InitReason, condition.RoleBindingReadyInitMessage),
 	)
+	if instance.Spec.Notifications.Enabled && instance.Spec.Notifications.RabbitMqClusterName != "" {
+		cl.Set(condition.UnknownCondition(cinderv1beta1.NotificationTransportURLReadyCondition, condition.InitReason, cinderv1beta1.NotificationTransportURLReadyInitMessage))
+	}
+	if instance.Spec.ImagePinning != nil {
+		cl.Set(condition.UnknownCondition(cinderv1beta1.ImageResolvedCondition, condition.InitReason, cinderv1beta1.ImageResolvedInitMessage))
+	}
 	instance.Status.Conditions.Init(&cl)
 	// Always mark the Generation as observed early on
 	instance.Status.ObservedGeneration = instance.Generation
@@ -235,6 +243,10 @@ const (
 	tlsAPIInternalField     = ".spec.tls.api.internal.secretName"
 	tlsAPIPublicField       = ".spec.tls.api.public.secretName"
 	topologyField           = ".spec.topologyRef.Name"
+	policyConfigMapField    = ".spec.policy.configMapRef.name"
+	clientCAField           = ".spec.internalClientAuth.caBundleSecretName"
+	apiClientCertField      = ".spec.internalClientAuth.clientCertSecretName"
+	clientCertField         = ".spec.clientCertSecretName"
 )
 
 var (
@@ -249,6 +261,15 @@ var (
 		tlsAPIInternalField,
 		tlsAPIPublicField,
 		topologyField,
+		policyConfigMapField,
+		clientCAField,
+		apiClientCertField,
+	}
+	cinderVolumeWatchFields = []string{
+		passwordSecretField,
+		caBundleSecretNameField,
+		topologyField,
+		clientCertField,
 	}
 )
 
@@ -283,7 +304,8 @@ func (r *CinderReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		for _, ownerRef := range o.GetOwnerReferences() {
 			if ownerRef.Kind == "TransportURL" {
 				for _, cr := range cinders.Items {
-					if ownerRef.Name == fmt.Sprintf("%s-cinder-transport", cr.Name) {
+					if ownerRef.Name == fmt.Sprintf("%s-cinder-transport", cr.Name) ||
+						ownerRef.Name == fmt.Sprintf("%s-cinder-notification-transport", cr.Name) {
 						// return namespace and Name of CR
 						name := client.ObjectKey{
 							Namespace: o.GetNamespace(),
@@ -388,6 +410,7 @@ func (r *CinderReconciler) reconcileInit(
 	helper *helper.Helper,
 	serviceLabels map[string]string,
 	serviceAnnotations map[string]string,
+	containerImage string,
 ) (ctrl.Result, error) {
 	Log := r.GetLogger(ctx)
 
@@ -397,7 +420,7 @@ func (r *CinderReconciler) reconcileInit(
 	// run Cinder db sync
 	//
 	dbSyncHash := instance.Status.Hash[cinderv1beta1.DbSyncHash]
-	jobDef := cinder.DbSyncJob(instance, serviceLabels, serviceAnnotations)
+	jobDef := cinder.DbSyncJob(instance, serviceLabels, serviceAnnotations, containerImage)
 
 	dbSyncjob := job.NewJob(
 		jobDef,
@@ -506,6 +529,51 @@ func (r *CinderReconciler) reconcileNormal(ctx context.Context, instance *cinder
 
 	// end transportURL
 
+	//
+	// create the RabbitMQ transportURL CR used for notifications when they go to a dedicated cluster
+	//
+
+	if instance.Spec.Notifications.Enabled && instance.Spec.Notifications.RabbitMqClusterName != "" {
+		notificationURL, op, err := r.notificationTransportURLCreateOrUpdate(ctx, instance, serviceLabels)
+		if err != nil {
+			instance.Status.Conditions.Set(condition.FalseCondition(
+				cinderv1beta1.NotificationTransportURLReadyCondition,
+				condition.ErrorReason,
+				condition.SeverityWarning,
+				cinderv1beta1.NotificationTransportURLReadyErrorMessage,
+				err.Error()))
+			return ctrl.Result{}, err
+		}
+
+		if op != controllerutil.OperationResultNone {
+			Log.Info(fmt.Sprintf("TransportURL %s successfully reconciled - operation: %s", notificationURL.Name, string(op)))
+		}
+
+		instance.Status.NotificationURLSecret = notificationURL.Status.SecretName
+
+		if instance.Status.NotificationURLSecret == "" {
+			Log.Info(fmt.Sprintf("Waiting for TransportURL %s secret to be created", notificationURL.Name))
+			instance.Status.Conditions.Set(condition.FalseCondition(
+				cinderv1beta1.NotificationTransportURLReadyCondition,
+				condition.RequestedReason,
+				condition.SeverityInfo,
+				cinderv1beta1.NotificationTransportURLReadyRunningMessage))
+			return cinder.ResultRequeue, nil
+		}
+
+		instance.Status.Conditions.MarkTrue(cinderv1beta1.NotificationTransportURLReadyCondition, cinderv1beta1.NotificationTransportURLReadyMessage)
+	} else {
+		// notifications use the RPC transportURL, remove the one that may
+		// have been requested for a dedicated cluster
+		err = r.notificationTransportURLDelete(ctx, instance)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		instance.Status.NotificationURLSecret = ""
+	}
+
+	// end notification transportURL
+
 	//
 	// Check for required memcached used for caching
 	//
@@ -574,6 +642,30 @@ func (r *CinderReconciler) reconcileNormal(ctx context.Context, instance *cinder
 		return result, nil
 	}
 
+	// The services verify the certificates of the database and the messaging
+	// bus with the CA bundle, which is only mounted when one is set
+	tlsStatus, err := r.getTLSStatus(ctx, helper, instance, db)
+	if err != nil {
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			condition.ServiceConfigReadyCondition,
+			condition.ErrorReason,
+			condition.SeverityWarning,
+			condition.ServiceConfigReadyErrorMessage,
+			err.Error()))
+		return ctrl.Result{}, err
+	}
+	instance.Status.TLS = tlsStatus
+	if (tlsStatus.Database || tlsStatus.Messaging) && instance.Spec.CinderAPI.TLS.Ca.CaBundleSecretName == "" {
+		err = fmt.Errorf("the database or the messaging bus use TLS and no CA bundle is set to verify them")
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			condition.ServiceConfigReadyCondition,
+			condition.ErrorReason,
+			condition.SeverityError,
+			condition.ServiceConfigReadyErrorMessage,
+			err.Error()))
+		return ctrl.Result{}, err
+	}
+
 	//
 	// Create Secrets required as input for the Service and calculate an overall hash of hashes
 	//
@@ -657,8 +749,29 @@ func (r *CinderReconciler) reconcileNormal(ctx context.Context, instance *cinder
 			instance.Spec.CinderAPI.NetworkAttachments, err)
 	}
 
+	// The db sync job and the db purge cronjob run with the same pinned
+	// image as the CinderAPI
+	if instance.Status.Image == nil {
+		instance.Status.Image = &cinderv1beta1.CinderImageStatus{}
+	}
+	containerImage, ctrlResult, err := ensureImage(
+		ctx,
+		helper,
+		instance,
+		instance.Spec.CinderAPI.ContainerImage,
+		instance.RbacResourceName(),
+		instance.Spec.ImagePinning,
+		serviceLabels,
+		instance.Status.Image,
+		instance.Status.Hash,
+		&instance.Status.Conditions,
+	)
+	if err != nil || (ctrlResult != ctrl.Result{}) {
+		return ctrlResult, err
+	}
+
 	// Handle service init
-	ctrlResult, err := r.reconcileInit(ctx, instance, helper, serviceLabels, serviceAnnotations)
+	ctrlResult, err = r.reconcileInit(ctx, instance, helper, serviceLabels, serviceAnnotations, containerImage)
 	if err != nil {
 		return ctrlResult, err
 	} else if (ctrlResult != ctrl.Result{}) {
@@ -799,6 +912,14 @@ func (r *CinderReconciler) reconcileNormal(ctx context.Context, instance *cinder
 				instance.Status.CinderVolumesReadyCounts = map[string]int32{}
 			}
 			instance.Status.CinderVolumesReadyCounts[name] = cinderVolume.Status.ReadyCount
+			if len(cinderVolume.Status.Backends) > 0 {
+				if instance.Status.CinderVolumesBackends == nil {
+					instance.Status.CinderVolumesBackends = map[string][]cinderv1beta1.CinderVolumeBackendStatus{}
+				}
+				instance.Status.CinderVolumesBackends[name] = cinderVolume.Status.Backends
+			} else {
+				delete(instance.Status.CinderVolumesBackends, name)
+			}
 
 			// If this cinderVolume is not IsReady, mirror the condition to get the latest step it is in.
 			// Could also check the overall ReadyCondition of the cinderVolume.
@@ -810,6 +931,13 @@ func (r *CinderReconciler) reconcileNormal(ctx context.Context, instance *cinder
 		}
 	}
 
+	// Don't report the backends of the CinderVolumes removed from the spec
+	for name := range instance.Status.CinderVolumesBackends {
+		if _, exists := instance.Spec.CinderVolumes[name]; !exists {
+			delete(instance.Status.CinderVolumesBackends, name)
+		}
+	}
+
 	if volumeCondition != nil {
 		// If there was a Status=False condition, set that as the CinderVolumeReadyCondition
 		instance.Status.Conditions.Set(volumeCondition)
@@ -826,7 +954,7 @@ func (r *CinderReconciler) reconcileNormal(ctx context.Context, instance *cinder
 	}
 
 	// create CronJob
-	cronjobDef := cinder.CronJob(instance, serviceLabels, serviceAnnotations)
+	cronjobDef := cinder.CronJob(instance, serviceLabels, serviceAnnotations, containerImage)
 	cronjob := cronjob.NewCronJob(
 		cronjobDef,
 		5*time.Second,
@@ -860,6 +988,24 @@ func (r *CinderReconciler) reconcileNormal(ctx context.Context, instance *cinder
 }
 
 // generateServiceConfigs - create Secret which hold scripts and service configuration
+// getTLSStatus - returns whether the database and the messaging bus the
+// services connect to use TLS
+func (r *CinderReconciler) getTLSStatus(
+	ctx context.Context,
+	h *helper.Helper,
+	instance *cinderv1beta1.Cinder,
+	db *mariadbv1.Database,
+) (cinderv1beta1.CinderTLSStatus, error) {
+	transportURLSecret, _, err := secret.GetSecret(ctx, h, instance.Status.TransportURLSecret, instance.Namespace)
+	if err != nil {
+		return cinderv1beta1.CinderTLSStatus{}, err
+	}
+	return cinderv1beta1.CinderTLSStatus{
+		Database:  db.GetTLSSupport(),
+		Messaging: cinder.IsTransportURLTLS(string(transportURLSecret.Data["transport_url"])),
+	}, nil
+}
+
 func (r *CinderReconciler) generateServiceConfigs(
 	ctx context.Context,
 	h *helper.Helper,
@@ -888,17 +1034,22 @@ func (r *CinderReconciler) generateServiceConfigs(
 		cinder.MyCnfFileName:        db.GetDatabaseClientConfig(tlsCfg), //(mschuppert) for now just get the default my.cnf
 	}
 
+	// Standalone deployments using the noauth strategy may not have a
+	// KeystoneAPI, their config is rendered without the Keystone URLs
+	var keystoneInternalURL, keystonePublicURL string
 	keystoneAPI, err := keystonev1.GetKeystoneAPI(ctx, h, instance.Namespace, map[string]string{})
-	if err != nil {
+	if err != nil && (!k8s_errors.IsNotFound(err) || !instance.Spec.CinderAPI.UsesNoAuth()) {
 		return err
 	}
-	keystoneInternalURL, err := keystoneAPI.GetEndpoint(endpoint.EndpointInternal)
-	if err != nil {
-		return err
-	}
-	keystonePublicURL, err := keystoneAPI.GetEndpoint(endpoint.EndpointPublic)
-	if err != nil {
-		return err
+	if err == nil {
+		keystoneInternalURL, err = keystoneAPI.GetEndpoint(endpoint.EndpointInternal)
+		if err != nil {
+			return err
+		}
+		keystonePublicURL, err = keystoneAPI.GetEndpoint(endpoint.EndpointPublic)
+		if err != nil {
+			return err
+		}
 	}
 
 	ospSecret, _, err := secret.GetSecret(ctx, h, instance.Spec.Secret, instance.Namespace)
@@ -919,25 +1070,60 @@ func (r *CinderReconciler) generateServiceConfigs(
 	templateParameters["ServicePassword"] = string(ospSecret.Data[instance.Spec.PasswordSelectors.Service])
 	templateParameters["KeystoneInternalURL"] = keystoneInternalURL
 	templateParameters["KeystonePublicURL"] = keystonePublicURL
+	templateParameters["AuthStrategy"] = string(cinderv1beta1.AuthStrategyKeystone)
+	if instance.Spec.CinderAPI.UsesNoAuth() {
+		templateParameters["AuthStrategy"] = string(cinderv1beta1.AuthStrategyNoAuth)
+	}
 	templateParameters["TransportURL"] = string(transportURLSecret.Data["transport_url"])
-	templateParameters["DatabaseConnection"] = fmt.Sprintf("mysql+pymysql://%s:%s@%s/%s?read_default_file=/etc/my.cnf",
+	templateParameters["MessagingTLS"] = cinder.GetMessagingTLSConfig(instance.Status.TLS.Messaging)
+	templateParameters["Notifications"] = cinder.GetNotificationsConfig(instance.Spec.Notifications)
+	templateParameters["NotificationURL"] = templateParameters["TransportURL"]
+	if instance.Status.NotificationURLSecret != "" {
+		notificationURLSecret, _, err := secret.GetSecret(ctx, h, instance.Status.NotificationURLSecret, instance.Namespace)
+		if err != nil {
+			return err
+		}
+		templateParameters["NotificationURL"] = string(notificationURLSecret.Data["transport_url"])
+	}
+	templateParameters["DatabaseConnection"] = cinder.GetDatabaseConnection(
 		databaseAccount.Spec.UserName,
 		string(dbSecret.Data[mariadbv1.DatabasePasswordSelector]),
 		instance.Status.DatabaseHostname,
-		cinder.DatabaseName)
+		instance.Status.TLS.Database)
 	templateParameters["MemcachedServersWithInet"] = memcached.GetMemcachedServerListWithInetString()
 	templateParameters["TimeOut"] = instance.Spec.APITimeout
+	templateParameters["AvailabilityZoneFilter"] = instance.Spec.CinderScheduler.AvailabilityZoneFilter
+	templateParameters["MaxRequestBodySize"] = instance.Spec.CinderAPI.RateLimit.GetMaxRequestBodySize()
+	templateParameters["ClientCert"] = cinder.GetClientCertConfig(instance.Spec.CinderAPI.InternalClientAuth.GetClientCertSecretName())
+	// The API binds to the IPv4 wildcard address unless an IPv6 Service needs
+	// to reach it
+	templateParameters["ListenIPv6"] = instance.Spec.CinderAPI.Override.UsesIPv6()
 
 	// create httpd  vhost template parameters
 	httpdVhostConfig := map[string]interface{}{}
+	vhostListen := cinderapi.GetVHostListen(instance.Spec.CinderAPI.EndpointNetworks)
 	for _, endpt := range []service.Endpoint{service.EndpointInternal, service.EndpointPublic} {
 		endptConfig := map[string]interface{}{}
 		endptConfig["ServerName"] = fmt.Sprintf("%s-%s.%s.svc", cinder.ServiceName, endpt.String(), instance.Namespace)
+		endptConfig["Addresses"] = strings.Join(vhostListen[endpt].Addresses, " ")
+		endptConfig["Listen"] = vhostListen[endpt].Listen
 		endptConfig["TLS"] = false // default TLS to false, and set it bellow to true if enabled
+		endptConfig["ClientAuth"] = nil
 		if instance.Spec.CinderAPI.TLS.API.Enabled(endpt) {
 			endptConfig["TLS"] = true
 			endptConfig["SSLCertificateFile"] = fmt.Sprintf("/etc/pki/tls/certs/%s.crt", endpt.String())
 			endptConfig["SSLCertificateKeyFile"] = fmt.Sprintf("/etc/pki/tls/private/%s.key", endpt.String())
+			// httpd reads the certificates from the secret volume so it can
+			// pick up the renewed ones on a graceful reload
+			if !instance.Spec.CinderAPI.UsesUWSGI() {
+				endptConfig["SSLCertificateFile"] = fmt.Sprintf("%s/%s/%s", cinder.APICertsPath, endpt.String(), tls.CertKey)
+				endptConfig["SSLCertificateKeyFile"] = fmt.Sprintf("%s/%s/%s", cinder.APICertsPath, endpt.String(), tls.PrivateKey)
+			}
+			if endpt == service.EndpointInternal && instance.Spec.CinderAPI.InternalClientAuth != nil {
+				endptConfig["ClientAuth"] = map[string]interface{}{
+					"CaFile": fmt.Sprintf("%s/client-ca/%s", cinder.APICertsPath, tls.CABundleKey),
+				}
+			}
 		}
 		httpdVhostConfig[endpt.String()] = endptConfig
 	}
@@ -1013,14 +1199,65 @@ func (r *CinderReconciler) transportURLCreateOrUpdate(
 	return transportURL, op, err
 }
 
+func (r *CinderReconciler) notificationTransportURLCreateOrUpdate(
+	ctx context.Context,
+	instance *cinderv1beta1.Cinder,
+	serviceLabels map[string]string,
+) (*rabbitmqv1.TransportURL, controllerutil.OperationResult, error) {
+	transportURL := &rabbitmqv1.TransportURL{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-cinder-notification-transport", instance.Name),
+			Namespace: instance.Namespace,
+			Labels:    serviceLabels,
+		},
+	}
+
+	op, err := controllerutil.CreateOrUpdate(ctx, r.Client, transportURL, func() error {
+		transportURL.Spec.RabbitmqClusterName = instance.Spec.Notifications.RabbitMqClusterName
+
+		err := controllerutil.SetControllerReference(instance, transportURL, r.Scheme)
+		return err
+	})
+
+	return transportURL, op, err
+}
+
+func (r *CinderReconciler) notificationTransportURLDelete(
+	ctx context.Context,
+	instance *cinderv1beta1.Cinder,
+) error {
+	transportURL := &rabbitmqv1.TransportURL{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-cinder-notification-transport", instance.Name),
+			Namespace: instance.Namespace,
+		},
+	}
+
+	err := r.Client.Get(ctx, client.ObjectKeyFromObject(transportURL), transportURL)
+	if err != nil {
+		if k8s_errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	err = r.Client.Delete(ctx, transportURL)
+	if err != nil && !k8s_errors.IsNotFound(err) {
+		return err
+	}
+
+	return nil
+}
+
 func (r *CinderReconciler) apiDeploymentCreateOrUpdate(ctx context.Context, instance *cinderv1beta1.Cinder) (*cinderv1beta1.CinderAPI, controllerutil.OperationResult, error) {
 	cinderAPISpec := cinderv1beta1.CinderAPISpec{
-		CinderTemplate:     instance.Spec.CinderTemplate,
-		CinderAPITemplate:  instance.Spec.CinderAPI,
-		ExtraMounts:        instance.Spec.ExtraMounts,
-		DatabaseHostname:   instance.Status.DatabaseHostname,
-		TransportURLSecret: instance.Status.TransportURLSecret,
-		ServiceAccount:     instance.RbacResourceName(),
+		CinderTemplate:        instance.Spec.CinderTemplate,
+		CinderAPITemplate:     instance.Spec.CinderAPI,
+		ExtraMounts:           instance.Spec.ExtraMounts,
+		DatabaseHostname:      instance.Status.DatabaseHostname,
+		TransportURLSecret:    instance.Status.TransportURLSecret,
+		NotificationURLSecret: instance.Status.NotificationURLSecret,
+		ServiceAccount:        instance.RbacResourceName(),
 	}
 
 	if cinderAPISpec.NodeSelector == nil {
@@ -1061,6 +1298,7 @@ func (r *CinderReconciler) schedulerDeploymentCreateOrUpdate(ctx context.Context
 		ExtraMounts:             instance.Spec.ExtraMounts,
 		DatabaseHostname:        instance.Status.DatabaseHostname,
 		TransportURLSecret:      instance.Status.TransportURLSecret,
+		NotificationURLSecret:   instance.Status.NotificationURLSecret,
 		ServiceAccount:          instance.RbacResourceName(),
 		TLS:                     instance.Spec.CinderAPI.TLS.Ca,
 	}
@@ -1098,13 +1336,14 @@ func (r *CinderReconciler) schedulerDeploymentCreateOrUpdate(ctx context.Context
 
 func (r *CinderReconciler) backupDeploymentCreateOrUpdate(ctx context.Context, instance *cinderv1beta1.Cinder) (*cinderv1beta1.CinderBackup, controllerutil.OperationResult, error) {
 	cinderBackupSpec := cinderv1beta1.CinderBackupSpec{
-		CinderTemplate:       instance.Spec.CinderTemplate,
-		CinderBackupTemplate: instance.Spec.CinderBackup,
-		ExtraMounts:          instance.Spec.ExtraMounts,
-		DatabaseHostname:     instance.Status.DatabaseHostname,
-		TransportURLSecret:   instance.Status.TransportURLSecret,
-		ServiceAccount:       instance.RbacResourceName(),
-		TLS:                  instance.Spec.CinderAPI.TLS.Ca,
+		CinderTemplate:        instance.Spec.CinderTemplate,
+		CinderBackupTemplate:  instance.Spec.CinderBackup,
+		ExtraMounts:           instance.Spec.ExtraMounts,
+		DatabaseHostname:      instance.Status.DatabaseHostname,
+		TransportURLSecret:    instance.Status.TransportURLSecret,
+		NotificationURLSecret: instance.Status.NotificationURLSecret,
+		ServiceAccount:        instance.RbacResourceName(),
+		TLS:                   instance.Spec.CinderAPI.TLS.Ca,
 	}
 
 	if cinderBackupSpec.NodeSelector == nil {
@@ -1161,13 +1400,15 @@ func (r *CinderReconciler) backupCleanupDeployment(ctx context.Context, instance
 
 func (r *CinderReconciler) volumeDeploymentCreateOrUpdate(ctx context.Context, instance *cinderv1beta1.Cinder, name string, volTemplate cinderv1beta1.CinderVolumeTemplate) (*cinderv1beta1.CinderVolume, controllerutil.OperationResult, error) {
 	cinderVolumeSpec := cinderv1beta1.CinderVolumeSpec{
-		CinderTemplate:       instance.Spec.CinderTemplate,
-		CinderVolumeTemplate: volTemplate,
-		ExtraMounts:          instance.Spec.ExtraMounts,
-		DatabaseHostname:     instance.Status.DatabaseHostname,
-		TransportURLSecret:   instance.Status.TransportURLSecret,
-		ServiceAccount:       instance.RbacResourceName(),
-		TLS:                  instance.Spec.CinderAPI.TLS.Ca,
+		CinderTemplate:        instance.Spec.CinderTemplate,
+		CinderVolumeTemplate:  volTemplate,
+		ExtraMounts:           instance.Spec.ExtraMounts,
+		DatabaseHostname:      instance.Status.DatabaseHostname,
+		TransportURLSecret:    instance.Status.TransportURLSecret,
+		NotificationURLSecret: instance.Status.NotificationURLSecret,
+		ClientCertSecretName:  instance.Spec.CinderAPI.InternalClientAuth.GetClientCertSecretName(),
+		ServiceAccount:        instance.RbacResourceName(),
+		TLS:                   instance.Spec.CinderAPI.TLS.Ca,
 	}
 
 	if cinderVolumeSpec.CinderVolumeTemplate.NodeSelector == nil {
@@ -1177,6 +1418,13 @@ func (r *CinderReconciler) volumeDeploymentCreateOrUpdate(ctx context.Context, i
 	if cinderVolumeSpec.CinderVolumeTemplate.TopologyRef == nil {
 		cinderVolumeSpec.CinderVolumeTemplate.TopologyRef = instance.Spec.TopologyRef
 	}
+
+	if policy := instance.Spec.CinderScheduler.SchedulerPolicy; policy != nil {
+		if functions, ok := policy.Backends[name]; ok {
+			cinderVolumeSpec.SchedulerFunctions = &functions
+		}
+	}
+
 	deployment := &cinderv1beta1.CinderVolume{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      fmt.Sprintf("%s-volume-%s", instance.Name, name),
@@ -19,6 +19,7 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"maps"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -26,6 +27,7 @@ import (
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -46,6 +48,7 @@ import (
 	keystonev1 "github.com/openstack-k8s-operators/keystone-operator/api/v1beta1"
 	"github.com/openstack-k8s-operators/lib-common/modules/common"
 	"github.com/openstack-k8s-operators/lib-common/modules/common/condition"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/configmap"
 	"github.com/openstack-k8s-operators/lib-common/modules/common/endpoint"
 	"github.com/openstack-k8s-operators/lib-common/modules/common/env"
 	"github.com/openstack-k8s-operators/lib-common/modules/common/helper"
@@ -90,14 +93,43 @@ var keystoneServices = []map[string]string{
 		"type": cinder.ServiceTypeV3,
 		"name": cinder.ServiceNameV3,
 		"desc": "Cinder V3 Service",
+		"path": "/v3",
+	},
+	{
+		"type": cinder.ServiceTypeBlockStorage,
+		"name": cinder.ServiceNameBlockStorage,
+		"desc": "Cinder Block Storage Service",
+		"path": "/v3",
 	},
 }
 
+// getKeystoneServices - services of the API in the Keystone catalog, with the
+// description and endpoint path set in the spec. The block-storage alias is
+// only listed when the spec asks for it.
+func getKeystoneServices(keystone *cinderv1beta1.CinderAPIKeystone) []map[string]string {
+	services := []map[string]string{}
+	for _, ksSvc := range keystoneServices {
+		if ksSvc["type"] == cinder.ServiceTypeBlockStorage && (keystone == nil || !keystone.BlockStorage) {
+			continue
+		}
+		ksSvc = maps.Clone(ksSvc)
+		if keystone != nil && keystone.Description != "" {
+			ksSvc["desc"] = keystone.Description
+		}
+		if ksSvc["type"] == cinder.ServiceTypeV3 && keystone != nil && keystone.Path != "" {
+			ksSvc["path"] = keystone.Path
+		}
+		services = append(services, ksSvc)
+	}
+	return services
+}
+
 //+kubebuilder:rbac:groups=cinder.openstack.org,resources=cinderapis,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=cinder.openstack.org,resources=cinderapis/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=cinder.openstack.org,resources=cinderapis/finalizers,verbs=update;patch
 // +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=core,resources=services,verbs=get;list;create;update;patch;delete;watch
+// +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch
 // +kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;
 // +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;create;update;patch;delete;watch
 // +kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=get;list;create;update;patch;delete;watch
@@ -168,12 +200,17 @@ func (r *CinderAPIReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 		condition.UnknownCondition(condition.InputReadyCondition, condition.InitReason, condition.InputReadyInitMessage),
 		condition.UnknownCondition(condition.ServiceConfigReadyCondition, condition.InitReason, condition.ServiceConfigReadyInitMessage),
 		condition.UnknownCondition(condition.DeploymentReadyCondition, condition.InitReason, condition.DeploymentReadyInitMessage),
-		// right now we have no dedicated KeystoneServiceReadyInitMessage and KeystoneEndpointReadyInitMessage
-		condition.UnknownCondition(condition.KeystoneServiceReadyCondition, condition.InitReason, ""),
-		condition.UnknownCondition(condition.KeystoneEndpointReadyCondition, condition.InitReason, ""),
 		condition.UnknownCondition(condition.NetworkAttachmentsReadyCondition, condition.InitReason, condition.NetworkAttachmentsReadyInitMessage),
 		condition.UnknownCondition(condition.TLSInputReadyCondition, condition.InitReason, condition.InputReadyInitMessage),
 	)
+	if instance.Spec.Keystone.Registers() {
+		// right now we have no dedicated KeystoneServiceReadyInitMessage and KeystoneEndpointReadyInitMessage
+		cl.Set(condition.UnknownCondition(condition.KeystoneServiceReadyCondition, condition.InitReason, ""))
+		cl.Set(condition.UnknownCondition(condition.KeystoneEndpointReadyCondition, condition.InitReason, ""))
+	}
+	if instance.Spec.ImagePinning != nil {
+		cl.Set(condition.UnknownCondition(cinderv1beta1.ImageResolvedCondition, condition.InitReason, cinderv1beta1.ImageResolvedInitMessage))
+	}
 	instance.Status.Conditions.Init(&cl)
 	// Always mark the Generation as observed early on
 	instance.Status.ObservedGeneration = instance.Generation
@@ -319,6 +356,42 @@ func (r *CinderAPIReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Man
 		return err
 	}
 
+	// index policyConfigMapField
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &cinderv1beta1.CinderAPI{}, policyConfigMapField, func(rawObj client.Object) []string {
+		// Extract the ConfigMap name from the spec, if one is provided
+		cr := rawObj.(*cinderv1beta1.CinderAPI)
+		if cr.Spec.Policy == nil || cr.Spec.Policy.ConfigMapRef == nil {
+			return nil
+		}
+		return []string{cr.Spec.Policy.ConfigMapRef.Name}
+	}); err != nil {
+		return err
+	}
+
+	// index clientCAField
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &cinderv1beta1.CinderAPI{}, clientCAField, func(rawObj client.Object) []string {
+		// Extract the secret name from the spec, if one is provided
+		cr := rawObj.(*cinderv1beta1.CinderAPI)
+		if cr.Spec.InternalClientAuth == nil {
+			return nil
+		}
+		return []string{cr.Spec.InternalClientAuth.CaBundleSecretName}
+	}); err != nil {
+		return err
+	}
+
+	// index apiClientCertField
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &cinderv1beta1.CinderAPI{}, apiClientCertField, func(rawObj client.Object) []string {
+		// Extract the secret name from the spec, if one is provided
+		cr := rawObj.(*cinderv1beta1.CinderAPI)
+		if cr.Spec.InternalClientAuth.GetClientCertSecretName() == "" {
+			return nil
+		}
+		return []string{cr.Spec.InternalClientAuth.ClientCertSecretName}
+	}); err != nil {
+		return err
+	}
+
 	// index topologyField
 	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &cinderv1beta1.CinderAPI{}, topologyField, func(rawObj client.Object) []string {
 		// Extract the topology name from the spec, if one is provided
@@ -345,6 +418,11 @@ func (r *CinderAPIReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Man
 			handler.EnqueueRequestsFromMapFunc(r.findObjectsForSrc),
 			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{}),
 		).
+		Watches(
+			&corev1.ConfigMap{},
+			handler.EnqueueRequestsFromMapFunc(r.findObjectsForSrc),
+			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{}),
+		).
 		Watches(&topologyv1.Topology{},
 			handler.EnqueueRequestsFromMapFunc(r.findObjectsForSrc),
 			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
@@ -455,21 +533,24 @@ func (r *CinderAPIReconciler) reconcileInit(
 	// expose the service (create service and return the created endpoint URLs)
 	//
 
-	// V3
 	publicEndpointData := endpoint.Data{
 		Port: cinder.CinderPublicPort,
-		Path: "/v3",
 	}
 	internalEndpointData := endpoint.Data{
 		Port: cinder.CinderInternalPort,
-		Path: "/v3",
 	}
 	cinderEndpoints := map[service.Endpoint]endpoint.Data{
 		service.EndpointPublic:   publicEndpointData,
 		service.EndpointInternal: internalEndpointData,
 	}
 
-	apiEndpointsV3 := make(map[string]string)
+	// every service of the catalog has its own endpoint URLs, which only
+	// differ in their path
+	ksServices := getKeystoneServices(instance.Spec.Keystone)
+	apiEndpoints := map[string]map[string]string{}
+	for _, ksSvc := range ksServices {
+		apiEndpoints[ksSvc["name"]] = map[string]string{}
+	}
 
 	for endpointType, data := range cinderEndpoints {
 		endpointTypeStr := string(endpointType)
@@ -486,19 +567,33 @@ func (r *CinderAPIReconciler) reconcileInit(
 			},
 		)
 
+		svcDef := service.GenericService(&service.GenericServiceDetails{
+			Name:      endpointName,
+			Namespace: instance.Namespace,
+			Labels:    exportLabels,
+			Selector:  serviceLabels,
+			Port: service.GenericServicePort{
+				Name:     endpointName,
+				Port:     data.Port,
+				Protocol: corev1.ProtocolTCP,
+			},
+		})
+		// The internal endpoint is served on its own port of the pods when
+		// the endpoints are on their own networks
+		svcDef.Spec.Ports[0].TargetPort = intstr.FromInt32(
+			cinderapi.GetTargetPort(endpointType, instance.Spec.EndpointNetworks))
+		// Request the IP families of the endpoint, a second family requires
+		// dual-stack unless the service override sets a different policy
+		if families := instance.Spec.Override.IPFamilies[endpointType]; len(families) > 0 {
+			svcDef.Spec.IPFamilies = families
+			if len(families) > 1 {
+				svcDef.Spec.IPFamilyPolicy = ptr.To(corev1.IPFamilyPolicyRequireDualStack)
+			}
+		}
+
 		// Create the service
 		svc, err := service.NewService(
-			service.GenericService(&service.GenericServiceDetails{
-				Name:      endpointName,
-				Namespace: instance.Namespace,
-				Labels:    exportLabels,
-				Selector:  serviceLabels,
-				Port: service.GenericServicePort{
-					Name:     endpointName,
-					Port:     data.Port,
-					Protocol: corev1.ProtocolTCP,
-				},
-			}),
+			svcDef,
 			5,
 			&svcOverride.OverrideSpec,
 		)
@@ -559,16 +654,18 @@ func (r *CinderAPIReconciler) reconcileInit(
 			data.Protocol = ptr.To(service.ProtocolHTTPS)
 		}
 
-		apiEndpointsV3[string(endpointType)], err = svc.GetAPIEndpoint(
-			svcOverride.EndpointURL, data.Protocol, data.Path)
-		if err != nil {
-			instance.Status.Conditions.MarkFalse(
-				condition.CreateServiceReadyCondition,
-				condition.ErrorReason,
-				condition.SeverityWarning,
-				condition.CreateServiceReadyErrorMessage,
-				err.Error())
-			return ctrl.Result{}, err
+		for _, ksSvc := range ksServices {
+			apiEndpoints[ksSvc["name"]][string(endpointType)], err = svc.GetAPIEndpoint(
+				svcOverride.EndpointURL, data.Protocol, ksSvc["path"])
+			if err != nil {
+				instance.Status.Conditions.MarkFalse(
+					condition.CreateServiceReadyCondition,
+					condition.ErrorReason,
+					condition.SeverityWarning,
+					condition.CreateServiceReadyErrorMessage,
+					err.Error())
+				return ctrl.Result{}, err
+			}
 		}
 	}
 	instance.Status.Conditions.MarkTrue(condition.CreateServiceReadyCondition, condition.CreateServiceReadyMessage)
@@ -576,11 +673,7 @@ func (r *CinderAPIReconciler) reconcileInit(
 	//
 	// Update instance status with service endpoint url from route host information
 	//
-	if instance.Status.APIEndpoints == nil {
-		instance.Status.APIEndpoints = map[string]map[string]string{}
-	}
-	instance.Status.APIEndpoints[cinder.ServiceNameV3] = apiEndpointsV3
-	// V3 - end
+	instance.Status.APIEndpoints = apiEndpoints
 
 	// expose service - end
 
@@ -592,12 +685,61 @@ func (r *CinderAPIReconciler) reconcileInit(
 		instance.Status.ServiceIDs = map[string]string{}
 	}
 
+	// Drop the services the API no longer registers. While the API is still
+	// registered the KeystoneService of the block-storage alias is only
+	// disabled, because it shares the service user with the volumev3 service
+	registered := map[string]bool{}
+	if instance.Spec.Keystone.Registers() {
+		for _, ksSvc := range ksServices {
+			registered[ksSvc["name"]] = true
+		}
+	}
 	for _, ksSvc := range keystoneServices {
+		if registered[ksSvc["name"]] {
+			continue
+		}
+
+		keystoneEndpoint, err := keystonev1.GetKeystoneEndpointWithName(ctx, helper, ksSvc["name"], instance.Namespace)
+		if err != nil && !k8s_errors.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+		if err == nil {
+			if err = deleteKeystoneObject(ctx, helper, keystoneEndpoint); err != nil {
+				return ctrl.Result{}, err
+			}
+			util.LogForObject(helper, fmt.Sprintf("Deleted our KeystoneEndpoint %s", ksSvc["name"]), instance)
+		}
+
+		keystoneService, err := keystonev1.GetKeystoneServiceWithName(ctx, helper, ksSvc["name"], instance.Namespace)
+		if err != nil && !k8s_errors.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+		if err == nil && !instance.Spec.Keystone.Registers() {
+			if err = deleteKeystoneObject(ctx, helper, keystoneService); err != nil {
+				return ctrl.Result{}, err
+			}
+			util.LogForObject(helper, fmt.Sprintf("Deleted our KeystoneService %s", ksSvc["name"]), instance)
+		} else if err == nil && keystoneService.Spec.Enabled {
+			keystoneService.Spec.Enabled = false
+			if err = helper.GetClient().Update(ctx, keystoneService); err != nil && !k8s_errors.IsNotFound(err) {
+				return ctrl.Result{}, err
+			}
+			util.LogForObject(helper, fmt.Sprintf("Disabled our KeystoneService %s", ksSvc["name"]), instance)
+		}
+		delete(instance.Status.ServiceIDs, ksSvc["name"])
+	}
+
+	if !instance.Spec.Keystone.Registers() {
+		Log.Info(fmt.Sprintf("Reconciled Service '%s' init successfully", instance.Name))
+		return ctrl.Result{}, nil
+	}
+
+	for _, ksSvc := range ksServices {
 		ksSvcSpec := keystonev1.KeystoneServiceSpec{
 			ServiceType:        ksSvc["type"],
 			ServiceName:        ksSvc["name"],
 			ServiceDescription: ksSvc["desc"],
-			Enabled:            true,
+			Enabled:            instance.Spec.Keystone.IsEnabled(),
 			ServiceUser:        instance.Spec.ServiceUser,
 			Secret:             instance.Spec.Secret,
 			PasswordSelector:   instance.Spec.PasswordSelectors.Service,
@@ -666,6 +808,19 @@ func (r *CinderAPIReconciler) reconcileInit(
 	return ctrl.Result{}, nil
 }
 
+// deleteKeystoneObject - deletes a KeystoneService or KeystoneEndpoint CR of
+// the API, the keystone-operator removes it from the catalog
+func deleteKeystoneObject(ctx context.Context, helper *helper.Helper, obj client.Object) error {
+	controllerutil.RemoveFinalizer(obj, helper.GetFinalizer())
+	if err := helper.GetClient().Update(ctx, obj); err != nil && !k8s_errors.IsNotFound(err) {
+		return err
+	}
+	if err := helper.GetClient().Delete(ctx, obj); err != nil && !k8s_errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
 func (r *CinderAPIReconciler) reconcileNormal(ctx context.Context, instance *cinderv1beta1.CinderAPI, helper *helper.Helper) (ctrl.Result, error) {
 	Log := r.GetLogger(ctx)
 
@@ -704,6 +859,14 @@ func (r *CinderAPIReconciler) reconcileNormal(ctx context.Context, instance *cin
 		fmt.Sprintf("%s-scripts", parentCinderName),     // ScriptsSecret
 		fmt.Sprintf("%s-config-data", parentCinderName), // ConfigSecret
 	}
+	// Notifications go to a dedicated RabbitMQ cluster
+	if instance.Spec.NotificationURLSecret != "" {
+		secretNames = append(secretNames, instance.Spec.NotificationURLSecret)
+	}
+	// Client certificate presented to Nova and Glance
+	if clientCert := instance.Spec.InternalClientAuth.GetClientCertSecretName(); clientCert != "" {
+		secretNames = append(secretNames, clientCert)
+	}
 	// Append CustomServiceConfigSecrets that should be checked
 	secretNames = append(secretNames, instance.Spec.CustomServiceConfigSecrets...)
 
@@ -721,6 +884,27 @@ func (r *CinderAPIReconciler) reconcileNormal(ctx context.Context, instance *cin
 		return ctrlResult, nil
 	}
 
+	//
+	// check for the policy rules, which may come from a ConfigMap
+	//
+	policyRules, ctrlResult, err := r.getPolicyRules(ctx, helper, instance)
+	if err != nil {
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			condition.InputReadyCondition,
+			condition.ErrorReason,
+			condition.SeverityWarning,
+			condition.InputReadyErrorMessage,
+			err.Error()))
+		return ctrlResult, err
+	} else if (ctrlResult != ctrl.Result{}) {
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			condition.InputReadyCondition,
+			condition.RequestedReason,
+			condition.SeverityInfo,
+			condition.InputReadyWaitingMessage))
+		return ctrlResult, nil
+	}
+
 	instance.Status.Conditions.MarkTrue(condition.InputReadyCondition, condition.InputReadyMessage)
 
 	//
@@ -759,6 +943,36 @@ func (r *CinderAPIReconciler) reconcileNormal(ctx context.Context, instance *cin
 		}
 	}
 
+	// Validate the CA of the client certificates if provided. It isn't part
+	// of the hash because httpd is gracefully reloaded when it changes.
+	if instance.Spec.InternalClientAuth != nil {
+		_, err := tls.ValidateCACertSecret(
+			ctx,
+			helper.GetClient(),
+			types.NamespacedName{
+				Name:      instance.Spec.InternalClientAuth.CaBundleSecretName,
+				Namespace: instance.Namespace,
+			},
+		)
+		if err != nil {
+			if k8s_errors.IsNotFound(err) {
+				instance.Status.Conditions.Set(condition.FalseCondition(
+					condition.TLSInputReadyCondition,
+					condition.RequestedReason,
+					condition.SeverityInfo,
+					fmt.Sprintf(condition.TLSInputReadyWaitingMessage, instance.Spec.InternalClientAuth.CaBundleSecretName)))
+				return ctrl.Result{}, nil
+			}
+			instance.Status.Conditions.Set(condition.FalseCondition(
+				condition.TLSInputReadyCondition,
+				condition.ErrorReason,
+				condition.SeverityWarning,
+				condition.TLSInputErrorMessage,
+				err.Error()))
+			return ctrl.Result{}, err
+		}
+	}
+
 	// Validate API service certs secrets
 	certsHash, err := instance.Spec.TLS.API.ValidateCertSecrets(ctx, helper, instance.Namespace)
 	if err != nil {
@@ -778,7 +992,11 @@ func (r *CinderAPIReconciler) reconcileNormal(ctx context.Context, instance *cin
 			err.Error()))
 		return ctrl.Result{}, err
 	}
-	configVars[tls.TLSHashName] = env.SetValue(certsHash)
+	// httpd is gracefully reloaded when the certificates are renewed, so
+	// only uWSGI needs a restart of the pods to use them
+	if instance.Spec.UsesUWSGI() {
+		configVars[tls.TLSHashName] = env.SetValue(certsHash)
+	}
 
 	// all cert input checks out so report InputReady
 	instance.Status.Conditions.MarkTrue(condition.TLSInputReadyCondition, condition.InputReadyMessage)
@@ -794,7 +1012,7 @@ func (r *CinderAPIReconciler) reconcileNormal(ctx context.Context, instance *cin
 	//
 	// create custom config for this cinder service
 	//
-	err = r.generateServiceConfigs(ctx, helper, instance, &configVars, serviceLabels)
+	err = r.generateServiceConfigs(ctx, helper, instance, &configVars, serviceLabels, policyRules)
 	if err != nil {
 		instance.Status.Conditions.Set(condition.FalseCondition(
 			condition.ServiceConfigReadyCondition,
@@ -910,8 +1128,28 @@ func (r *CinderAPIReconciler) reconcileNormal(ctx context.Context, instance *cin
 		return ctrl.Result{}, nil
 	}
 
+	// Pin the image of the service to a digest when requested
+	if instance.Status.Image == nil {
+		instance.Status.Image = &cinderv1beta1.CinderImageStatus{}
+	}
+	containerImage, ctrlResult, err := ensureImage(
+		ctx,
+		helper,
+		instance,
+		instance.Spec.ContainerImage,
+		instance.Spec.ServiceAccount,
+		instance.Spec.ImagePinning,
+		serviceLabels,
+		instance.Status.Image,
+		instance.Status.Hash,
+		&instance.Status.Conditions,
+	)
+	if err != nil || (ctrlResult != ctrl.Result{}) {
+		return ctrlResult, err
+	}
+
 	// Deploy a statefulset
-	ssDef, err := cinderapi.StatefulSet(instance, inputHash, serviceLabels, serviceAnnotations, topology)
+	ssDef, err := cinderapi.StatefulSet(instance, inputHash, serviceLabels, serviceAnnotations, topology, containerImage)
 	if err != nil {
 		instance.Status.Conditions.Set(condition.FalseCondition(
 			condition.DeploymentReadyCondition,
@@ -960,6 +1198,13 @@ func (r *CinderAPIReconciler) reconcileNormal(ctx context.Context, instance *cin
 
 	instance.Status.ReadyCount = ssData.Status.ReadyReplicas
 
+	// Report the image each pod is actually using
+	podImages, err := getPodImages(ctx, helper, instance.Namespace, serviceLabels, cinderapi.ComponentName)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	instance.Status.Image.PodImages = podImages
+
 	// verify if network attachment matches expectations
 	networkReady := false
 	networkAttachmentStatus := map[string][]string{}
@@ -1035,6 +1280,7 @@ func (r *CinderAPIReconciler) generateServiceConfigs(
 	instance *cinderv1beta1.CinderAPI,
 	envVars *map[string]env.Setter,
 	serviceLabels map[string]string,
+	policyRules string,
 ) error {
 	//
 	// create custom Secret for cinder service-specific config input
@@ -1069,25 +1315,74 @@ func (r *CinderAPIReconciler) generateServiceConfigs(
 	}
 	customData[cinder.CustomServiceConfigSecretsFileName] = customSecrets
 
+	// the policy rules are part of the secret, so changing them rolls the API
+	policyFile := ""
+	if policyRules != "" {
+		customData[cinderapi.PolicyFileName] = policyRules
+		policyFile = cinderapi.PolicyFile
+	}
+
+	secureRBAC := true
+	if instance.Spec.Policy != nil {
+		secureRBAC = instance.Spec.Policy.SecureRBAC
+	}
+
 	templateParameters := map[string]interface{}{
-		"LogFile": cinderapi.LogFile,
+		"LogFile":            cinderapi.LogFile,
+		"Audit":              cinderapi.GetAuditConfig(instance),
+		"Paste":              cinderapi.GetPasteConfig(instance),
+		"PolicyFile":         policyFile,
+		"SecureRBAC":         secureRBAC,
+		"MaxRequestBodySize": instance.Spec.RateLimit.GetMaxRequestBodySize(),
 	}
 
 	configTemplates := []util.Template{
 		{
-			Name:          fmt.Sprintf("%s-config-data", instance.Name),
-			Namespace:     instance.Namespace,
-			Type:          util.TemplateTypeConfig,
-			InstanceType:  instance.Kind,
-			CustomData:    customData,
-			ConfigOptions: templateParameters,
-			Labels:        labels,
+			Name:               fmt.Sprintf("%s-config-data", instance.Name),
+			Namespace:          instance.Namespace,
+			Type:               util.TemplateTypeConfig,
+			InstanceType:       instance.Kind,
+			CustomData:         customData,
+			ConfigOptions:      templateParameters,
+			AdditionalTemplate: cinderapi.GetConfigTemplates(instance),
+			Labels:             labels,
 		},
 	}
 
 	return secret.EnsureSecrets(ctx, h, instance, configTemplates, envVars)
 }
 
+// getPolicyRules - returns the policy.yaml content of the API, reading and
+// validating it when it comes from a ConfigMap
+func (r *CinderAPIReconciler) getPolicyRules(
+	ctx context.Context,
+	h *helper.Helper,
+	instance *cinderv1beta1.CinderAPI,
+) (string, ctrl.Result, error) {
+	policy := instance.Spec.Policy
+	if policy == nil {
+		return "", ctrl.Result{}, nil
+	}
+	if policy.ConfigMapRef == nil {
+		return policy.Rules, ctrl.Result{}, nil
+	}
+
+	cm, ctrlResult, err := configmap.GetConfigMap(ctx, h, instance, policy.ConfigMapRef.Name, cinder.NormalDuration)
+	if err != nil || (ctrlResult != ctrl.Result{}) {
+		return "", ctrlResult, err
+	}
+
+	rules, ok := cm.Data[policy.ConfigMapRef.Key]
+	if !ok {
+		return "", ctrl.Result{}, fmt.Errorf("key %s not found in ConfigMap %s", policy.ConfigMapRef.Key, cm.Name)
+	}
+	if err := cinderv1beta1.ValidatePolicyRules(rules); err != nil {
+		return "", ctrl.Result{}, fmt.Errorf("ConfigMap %s: %w", cm.Name, err)
+	}
+
+	return rules, ctrl.Result{}, nil
+}
+
 // createHashOfInputHashes - creates a hash of hashes which gets added to the resources which requires a restart
 // if any of the input resources change, like configs, passwords, ...
 //
@@ -86,7 +86,10 @@ func (r *CinderBackupReconciler) GetLogger(ctx context.Context) logr.Logger {
 //+kubebuilder:rbac:groups=cinder.openstack.org,resources=cinderbackups/finalizers,verbs=update;patch
 // +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;create;update;patch;delete;watch
 // +kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=get;list;create;update;patch;delete;watch
+// +kubebuilder:rbac:groups=apps,resources=daemonsets,verbs=get;list;create;update;patch;delete;watch
+// +kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch;patch
 // +kubebuilder:rbac:groups=k8s.cni.cncf.io,resources=network-attachment-definitions,verbs=get;list;watch
 // +kubebuilder:rbac:groups=topology.openstack.org,resources=topologies,verbs=get;list;watch;update
 
@@ -154,6 +157,15 @@ func (r *CinderBackupReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		condition.UnknownCondition(condition.NetworkAttachmentsReadyCondition, condition.InitReason, condition.NetworkAttachmentsReadyInitMessage),
 		condition.UnknownCondition(condition.TLSInputReadyCondition, condition.InitReason, condition.InputReadyInitMessage),
 	)
+	if instance.Spec.FibreChannel != nil {
+		cl.Set(condition.UnknownCondition(cinderv1beta1.FibreChannelReadyCondition, condition.InitReason, cinderv1beta1.FibreChannelReadyInitMessage))
+	}
+	if instance.Spec.ImagePinning != nil {
+		cl.Set(condition.UnknownCondition(cinderv1beta1.ImageResolvedCondition, condition.InitReason, cinderv1beta1.ImageResolvedInitMessage))
+	}
+	if instance.Spec.AvailabilityZone != nil {
+		cl.Set(condition.UnknownCondition(cinderv1beta1.AvailabilityZoneReadyCondition, condition.InitReason, cinderv1beta1.AvailabilityZoneReadyInitMessage))
+	}
 	instance.Status.Conditions.Init(&cl)
 	// Always mark the Generation as observed early on
 	instance.Status.ObservedGeneration = instance.Generation
@@ -284,6 +296,7 @@ func (r *CinderBackupReconciler) SetupWithManager(ctx context.Context, mgr ctrl.
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&cinderv1beta1.CinderBackup{}).
 		Owns(&appsv1.StatefulSet{}).
+		Owns(&appsv1.DaemonSet{}).
 		// watch the secrets we don't own
 		Watches(&corev1.Secret{},
 			handler.EnqueueRequestsFromMapFunc(secretFn)).
@@ -295,9 +308,45 @@ func (r *CinderBackupReconciler) SetupWithManager(ctx context.Context, mgr ctrl.
 		Watches(&topologyv1.Topology{},
 			handler.EnqueueRequestsFromMapFunc(r.findObjectsForSrc),
 			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		// the zone of the service follows the zone labels of the nodes
+		Watches(&corev1.Node{},
+			handler.EnqueueRequestsFromMapFunc(r.findObjectsForNode),
+			builder.WithPredicates(predicate.LabelChangedPredicate{})).
 		Complete(r)
 }
 
+// findObjectsForNode - returns the services whose availability zone is
+// derived from the zone of their nodes
+func (r *CinderBackupReconciler) findObjectsForNode(ctx context.Context, node client.Object) []reconcile.Request {
+	requests := []reconcile.Request{}
+
+	l := log.FromContext(ctx).WithName("Controllers").WithName("CinderBackup")
+
+	crList := &cinderv1beta1.CinderBackupList{}
+	if err := r.List(ctx, crList); err != nil {
+		l.Error(err, fmt.Sprintf("listing %s for node: %s", crList.GroupVersionKind().Kind, node.GetName()))
+		return requests
+	}
+
+	for _, item := range crList.Items {
+		if item.Spec.AvailabilityZone == nil || item.Spec.AvailabilityZone.Name != "" {
+			continue
+		}
+		l.Info(fmt.Sprintf("node %s labels changed, reconcile: %s - %s", node.GetName(), item.GetName(), item.GetNamespace()))
+
+		requests = append(requests,
+			reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      item.GetName(),
+					Namespace: item.GetNamespace(),
+				},
+			},
+		)
+	}
+
+	return requests
+}
+
 func (r *CinderBackupReconciler) findObjectsForSrc(ctx context.Context, src client.Object) []reconcile.Request {
 	requests := []reconcile.Request{}
 
@@ -393,6 +442,10 @@ func (r *CinderBackupReconciler) reconcileNormal(ctx context.Context, instance *
 		fmt.Sprintf("%s-scripts", parentCinderName),     // ScriptsSecret
 		fmt.Sprintf("%s-config-data", parentCinderName), // ConfigSecret
 	}
+	// Notifications go to a dedicated RabbitMQ cluster
+	if instance.Spec.NotificationURLSecret != "" {
+		secretNames = append(secretNames, instance.Spec.NotificationURLSecret)
+	}
 	// Append CustomServiceConfigSecrets that should be checked
 	secretNames = append(secretNames, instance.Spec.CustomServiceConfigSecrets...)
 
@@ -458,10 +511,30 @@ func (r *CinderBackupReconciler) reconcileNormal(ctx context.Context, instance *
 		common.ComponentSelector: cinderbackup.ComponentName,
 	}
 
+	//
+	// Determine the availability zone of the service
+	//
+	availabilityZone := ""
+	if instance.Spec.AvailabilityZone != nil {
+		availabilityZone, err = ensureAvailabilityZone(
+			ctx,
+			helper,
+			instance.Namespace,
+			instance.Spec.AvailabilityZone,
+			instance.Spec.NodeSelector,
+			instance.Spec.TopologyRef,
+			&instance.Status.Conditions,
+		)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	instance.Status.AvailabilityZone = availabilityZone
+
 	//
 	// create custom config for this cinder service
 	//
-	err = r.generateServiceConfigs(ctx, helper, instance, &configVars, serviceLabels)
+	err = r.generateServiceConfigs(ctx, helper, instance, &configVars, serviceLabels, availabilityZone)
 	if err != nil {
 		instance.Status.Conditions.Set(condition.FalseCondition(
 			condition.ServiceConfigReadyCondition,
@@ -568,8 +641,53 @@ func (r *CinderBackupReconciler) reconcileNormal(ctx context.Context, instance *
 		return ctrl.Result{}, fmt.Errorf("waiting for Topology requirements: %w", err)
 	}
 
+	// Pin the image of the service to a digest when requested, the jobs and
+	// daemonsets of the service run with it too
+	if instance.Status.Image == nil {
+		instance.Status.Image = &cinderv1beta1.CinderImageStatus{}
+	}
+	containerImage, ctrlResult, err := ensureImage(
+		ctx,
+		helper,
+		instance,
+		instance.Spec.ContainerImage,
+		instance.Spec.ServiceAccount,
+		instance.Spec.ImagePinning,
+		serviceLabels,
+		instance.Status.Image,
+		instance.Status.Hash,
+		&instance.Status.Conditions,
+	)
+	if err != nil || (ctrlResult != ctrl.Result{}) {
+		return ctrlResult, err
+	}
+
+	// Discover the FC HBAs of the nodes the service can run on
+	if instance.Spec.FibreChannel != nil {
+		dsDef := cinder.FCDiscoveryDaemonSet(
+			instance,
+			containerImage,
+			instance.Spec.ServiceAccount,
+			instance.Spec.NodeSelector,
+			topology,
+			cinder.GetFCDiscoveryLabels(serviceLabels),
+		)
+		fcNodes, ctrlResult, err := ensureFibreChannel(
+			ctx, helper, dsDef, instance.Spec.FibreChannel.Required, &instance.Status.Conditions)
+		if err != nil || (ctrlResult != ctrl.Result{}) {
+			return ctrlResult, err
+		}
+		instance.Status.FibreChannel = fcNodes
+	} else {
+		instance.Status.FibreChannel = nil
+		err = deleteFibreChannelDiscovery(ctx, helper, instance)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
 	// Deploy a statefulset
-	ssDef := cinderbackup.StatefulSet(instance, inputHash, serviceLabels, serviceAnnotations, topology)
+	ssDef := cinderbackup.StatefulSet(instance, inputHash, serviceLabels, serviceAnnotations, topology, containerImage)
 	ss := statefulset.NewStatefulSet(ssDef, cinder.ShortDuration)
 
 	var ssData appsv1.StatefulSet
@@ -609,6 +727,13 @@ func (r *CinderBackupReconciler) reconcileNormal(ctx context.Context, instance *
 
 	instance.Status.ReadyCount = ssData.Status.ReadyReplicas
 
+	// Report the image each pod is actually using
+	podImages, err := getPodImages(ctx, helper, instance.Namespace, serviceLabels, cinderbackup.ComponentName)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	instance.Status.Image.PodImages = podImages
+
 	// verify if network attachment matches expectations
 	networkReady := false
 	networkAttachmentStatus := map[string][]string{}
@@ -683,6 +808,7 @@ func (r *CinderBackupReconciler) generateServiceConfigs(
 	instance *cinderv1beta1.CinderBackup,
 	envVars *map[string]env.Setter,
 	serviceLabels map[string]string,
+	availabilityZone string,
 ) error {
 	//
 	// create custom Secret for cinder service-specific config input
@@ -717,14 +843,19 @@ func (r *CinderBackupReconciler) generateServiceConfigs(
 	}
 	customData[cinder.CustomServiceConfigSecretsFileName] = customSecrets
 
+	templateParameters := map[string]interface{}{
+		"AvailabilityZone": availabilityZone,
+	}
+
 	configTemplates := []util.Template{
 		{
-			Name:         fmt.Sprintf("%s-config-data", instance.Name),
-			Namespace:    instance.Namespace,
-			Type:         util.TemplateTypeConfig,
-			InstanceType: instance.Kind,
-			CustomData:   customData,
-			Labels:       labels,
+			Name:          fmt.Sprintf("%s-config-data", instance.Name),
+			Namespace:     instance.Namespace,
+			Type:          util.TemplateTypeConfig,
+			InstanceType:  instance.Kind,
+			CustomData:    customData,
+			ConfigOptions: templateParameters,
+			Labels:        labels,
 		},
 	}
 
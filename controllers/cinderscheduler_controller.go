@@ -19,10 +19,14 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"slices"
+	"sort"
+	"strings"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -48,6 +52,7 @@ import (
 	"github.com/openstack-k8s-operators/lib-common/modules/common/helper"
 	"github.com/openstack-k8s-operators/lib-common/modules/common/labels"
 	nad "github.com/openstack-k8s-operators/lib-common/modules/common/networkattachment"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/pod"
 	"github.com/openstack-k8s-operators/lib-common/modules/common/secret"
 	"github.com/openstack-k8s-operators/lib-common/modules/common/statefulset"
 	"github.com/openstack-k8s-operators/lib-common/modules/common/tls"
@@ -85,6 +90,9 @@ func (r *CinderSchedulerReconciler) GetLogger(ctx context.Context) logr.Logger {
 //+kubebuilder:rbac:groups=cinder.openstack.org,resources=cinderschedulers/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=cinder.openstack.org,resources=cinderschedulers/finalizers,verbs=update;patch
 // +kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;
+// +kubebuilder:rbac:groups=core,resources=pods/status,verbs=get;patch
+// +kubebuilder:rbac:groups=cinder.openstack.org,resources=cindervolumes,verbs=get;list;watch
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;create;update;patch;delete;watch
 // +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=get;list;create;update;patch;delete;watch
 // +kubebuilder:rbac:groups=k8s.cni.cncf.io,resources=network-attachment-definitions,verbs=get;list;watch
@@ -154,6 +162,12 @@ func (r *CinderSchedulerReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 		condition.UnknownCondition(condition.NetworkAttachmentsReadyCondition, condition.InitReason, condition.NetworkAttachmentsReadyInitMessage),
 		condition.UnknownCondition(condition.TLSInputReadyCondition, condition.InitReason, condition.InputReadyInitMessage),
 	)
+	if instance.Spec.ImagePinning != nil {
+		cl.Set(condition.UnknownCondition(cinderv1beta1.ImageResolvedCondition, condition.InitReason, cinderv1beta1.ImageResolvedInitMessage))
+	}
+	if instance.Spec.CapabilitiesWarmup {
+		cl.Set(condition.UnknownCondition(cinderv1beta1.CinderSchedulerCapabilitiesReadyCondition, condition.InitReason, cinderv1beta1.CinderSchedulerCapabilitiesReadyInitMessage))
+	}
 	instance.Status.Conditions.Init(&cl)
 	// Always mark the Generation as observed early on
 	instance.Status.ObservedGeneration = instance.Generation
@@ -295,9 +309,42 @@ func (r *CinderSchedulerReconciler) SetupWithManager(ctx context.Context, mgr ct
 		Watches(&topologyv1.Topology{},
 			handler.EnqueueRequestsFromMapFunc(r.findObjectsForSrc),
 			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		// the capabilities warmup waits for the back-ends the volumes report
+		Watches(&cinderv1beta1.CinderVolume{},
+			handler.EnqueueRequestsFromMapFunc(r.findObjectsForVolume)).
 		Complete(r)
 }
 
+// findObjectsForVolume - returns the schedulers of the same Cinder CR as the
+// volume that wait for the capabilities of its back-ends
+func (r *CinderSchedulerReconciler) findObjectsForVolume(ctx context.Context, src client.Object) []reconcile.Request {
+	requests := []reconcile.Request{}
+
+	l := log.FromContext(ctx).WithName("Controllers").WithName("CinderScheduler")
+
+	crList := &cinderv1beta1.CinderSchedulerList{}
+	if err := r.List(ctx, crList, client.InNamespace(src.GetNamespace())); err != nil {
+		l.Error(err, fmt.Sprintf("listing %s for volume: %s - %s", crList.GroupVersionKind().Kind, src.GetName(), src.GetNamespace()))
+		return requests
+	}
+
+	for _, item := range crList.Items {
+		if !item.Spec.CapabilitiesWarmup || cinder.GetOwningCinderName(&item) != cinder.GetOwningCinderName(src) {
+			continue
+		}
+		requests = append(requests,
+			reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      item.GetName(),
+					Namespace: item.GetNamespace(),
+				},
+			},
+		)
+	}
+
+	return requests
+}
+
 func (r *CinderSchedulerReconciler) findObjectsForSrc(ctx context.Context, src client.Object) []reconcile.Request {
 	requests := []reconcile.Request{}
 
@@ -391,6 +438,10 @@ func (r *CinderSchedulerReconciler) reconcileNormal(ctx context.Context, instanc
 		fmt.Sprintf("%s-scripts", parentCinderName),     // ScriptsSecret
 		fmt.Sprintf("%s-config-data", parentCinderName), // ConfigSecret
 	}
+	// Notifications go to a dedicated RabbitMQ cluster
+	if instance.Spec.NotificationURLSecret != "" {
+		secretNames = append(secretNames, instance.Spec.NotificationURLSecret)
+	}
 	// Append CustomServiceConfigSecrets that should be checked
 	secretNames = append(secretNames, instance.Spec.CustomServiceConfigSecrets...)
 
@@ -566,8 +617,28 @@ func (r *CinderSchedulerReconciler) reconcileNormal(ctx context.Context, instanc
 		return ctrl.Result{}, fmt.Errorf("waiting for Topology requirements: %w", err)
 	}
 
+	// Pin the image of the service to a digest when requested
+	if instance.Status.Image == nil {
+		instance.Status.Image = &cinderv1beta1.CinderImageStatus{}
+	}
+	containerImage, ctrlResult, err := ensureImage(
+		ctx,
+		helper,
+		instance,
+		instance.Spec.ContainerImage,
+		instance.Spec.ServiceAccount,
+		instance.Spec.ImagePinning,
+		serviceLabels,
+		instance.Status.Image,
+		instance.Status.Hash,
+		&instance.Status.Conditions,
+	)
+	if err != nil || (ctrlResult != ctrl.Result{}) {
+		return ctrlResult, err
+	}
+
 	// Deploy a statefulset
-	ssDef := cinderscheduler.StatefulSet(instance, inputHash, serviceLabels, serviceAnnotations, topology)
+	ssDef := cinderscheduler.StatefulSet(instance, inputHash, serviceLabels, serviceAnnotations, topology, containerImage)
 	ss := statefulset.NewStatefulSet(ssDef, cinder.ShortDuration)
 
 	var ssData appsv1.StatefulSet
@@ -607,6 +678,22 @@ func (r *CinderSchedulerReconciler) reconcileNormal(ctx context.Context, instanc
 
 	instance.Status.ReadyCount = ssData.Status.ReadyReplicas
 
+	// Report the image each pod is actually using
+	podImages, err := getPodImages(ctx, helper, instance.Namespace, serviceLabels, cinderscheduler.ComponentName)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	instance.Status.Image.PodImages = podImages
+
+	// The pods are not ready until they have the capabilities of the back-ends
+	warmupResult := ctrl.Result{}
+	if instance.Spec.CapabilitiesWarmup {
+		warmupResult, err = r.reconcileCapabilities(ctx, instance, helper, serviceLabels)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
 	// verify if network attachment matches expectations
 	networkReady := false
 	networkAttachmentStatus := map[string][]string{}
@@ -671,6 +758,173 @@ func (r *CinderSchedulerReconciler) reconcileNormal(ctx context.Context, instanc
 		instance.Status.Conditions.MarkTrue(condition.ReadyCondition, condition.ReadyMessage)
 	}
 	// For non ready we'll let the main defer func handle the status update using the Mirror function
+	return warmupResult, nil
+}
+
+// getExpectedBackends - Returns the hosts of the back-ends the schedulers
+// must have the capabilities of, and the volume services of the cinderVolumes
+// of the Cinder CR that haven't reported their back-ends yet
+func (r *CinderSchedulerReconciler) getExpectedBackends(
+	ctx context.Context,
+	instance *cinderv1beta1.CinderScheduler,
+) ([]string, []string, error) {
+	expected := []string{}
+	unreported := []string{}
+
+	cinderName := cinder.GetOwningCinderName(instance)
+	if cinderName == "" {
+		return expected, unreported, nil
+	}
+	owner := &cinderv1beta1.Cinder{}
+	err := r.Client.Get(ctx, types.NamespacedName{Name: cinderName, Namespace: instance.Namespace}, owner)
+	if err != nil {
+		if k8s_errors.IsNotFound(err) {
+			return expected, unreported, nil
+		}
+		return nil, nil, err
+	}
+
+	names := make([]string, 0, len(owner.Spec.CinderVolumes))
+	for name, volTemplate := range owner.Spec.CinderVolumes {
+		// Volume services without replicas don't report capabilities
+		if volTemplate.Replicas != nil && *volTemplate.Replicas == 0 {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		volumeName := fmt.Sprintf("%s-volume-%s", cinderName, name)
+		volume := &cinderv1beta1.CinderVolume{}
+		err := r.Client.Get(ctx, types.NamespacedName{Name: volumeName, Namespace: instance.Namespace}, volume)
+		if err != nil && !k8s_errors.IsNotFound(err) {
+			return nil, nil, err
+		}
+		if err != nil || len(volume.Status.Backends) == 0 {
+			unreported = append(unreported, volumeName)
+			continue
+		}
+		for _, backend := range volume.Status.Backends {
+			// Disabled back-ends aren't placed on, so they can be missing
+			if !backend.Disabled {
+				expected = append(expected, backend.Host)
+			}
+		}
+	}
+	return expected, unreported, nil
+}
+
+// reconcileCapabilities - sets the readiness gate of the scheduler pods once
+// they have received the capabilities of all the volume back-ends that are up
+func (r *CinderSchedulerReconciler) reconcileCapabilities(
+	ctx context.Context,
+	instance *cinderv1beta1.CinderScheduler,
+	helper *helper.Helper,
+	serviceLabels map[string]string,
+) (ctrl.Result, error) {
+	Log := r.GetLogger(ctx)
+
+	// The back-ends are the ones of every volume service in the cinderVolumes
+	// of the Cinder CR. A volume service that hasn't reported its back-ends
+	// yet, or whose back-ends are down, keeps the schedulers waiting.
+	expected, unreported, err := r.getExpectedBackends(ctx, instance)
+	if err != nil {
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			cinderv1beta1.CinderSchedulerCapabilitiesReadyCondition,
+			condition.ErrorReason,
+			condition.SeverityWarning,
+			cinderv1beta1.CinderSchedulerCapabilitiesReadyErrorMessage,
+			err.Error()))
+		return ctrl.Result{}, err
+	}
+
+	podList, err := pod.GetPodListWithLabel(ctx, helper, instance.Namespace, serviceLabels)
+	if err != nil {
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			cinderv1beta1.CinderSchedulerCapabilitiesReadyCondition,
+			condition.ErrorReason,
+			condition.SeverityWarning,
+			cinderv1beta1.CinderSchedulerCapabilitiesReadyErrorMessage,
+			err.Error()))
+		return ctrl.Result{}, err
+	}
+
+	waiting := []string{}
+	for idx := range podList.Items {
+		p := &podList.Items[idx]
+		if p.DeletionTimestamp != nil {
+			continue
+		}
+		// Nothing else triggers a reconcile when the pod starts, since it
+		// doesn't become ready until the gate is set
+		if p.Status.Phase != corev1.PodRunning || p.Status.PodIP == "" {
+			waiting = append(waiting, p.Name)
+			continue
+		}
+
+		status := corev1.ConditionTrue
+		message := ""
+		hosts, err := cinderscheduler.GetSchedulerHosts(ctx, p.Status.PodIP)
+		if err != nil {
+			Log.Info(fmt.Sprintf("Failed to get the pools from pod %s: %s", p.Name, err))
+			status = corev1.ConditionFalse
+			message = err.Error()
+		} else {
+			missing := slices.Clone(unreported)
+			for _, host := range expected {
+				if !slices.Contains(hosts, host) {
+					missing = append(missing, host)
+				}
+			}
+			if len(missing) > 0 {
+				status = corev1.ConditionFalse
+				message = fmt.Sprintf("Missing capabilities of %s", strings.Join(missing, ", "))
+			}
+		}
+		if status != corev1.ConditionTrue {
+			waiting = append(waiting, p.Name)
+		}
+
+		if cond := cinderscheduler.GetCapabilitiesCondition(p); cond != nil &&
+			cond.Status == status && cond.Message == message {
+			continue
+		}
+		orig := p.DeepCopy()
+		newCond := corev1.PodCondition{
+			Type:               cinderscheduler.CapabilitiesReadinessGate,
+			Status:             status,
+			Message:            message,
+			LastTransitionTime: metav1.Now(),
+		}
+		if cond := cinderscheduler.GetCapabilitiesCondition(p); cond != nil {
+			*cond = newCond
+		} else {
+			p.Status.Conditions = append(p.Status.Conditions, newCond)
+		}
+		if err := r.Client.Status().Patch(ctx, p, client.StrategicMergeFrom(orig)); err != nil {
+			instance.Status.Conditions.Set(condition.FalseCondition(
+				cinderv1beta1.CinderSchedulerCapabilitiesReadyCondition,
+				condition.ErrorReason,
+				condition.SeverityWarning,
+				cinderv1beta1.CinderSchedulerCapabilitiesReadyErrorMessage,
+				err.Error()))
+			return ctrl.Result{}, err
+		}
+	}
+
+	if len(waiting) > 0 {
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			cinderv1beta1.CinderSchedulerCapabilitiesReadyCondition,
+			condition.RequestedReason,
+			condition.SeverityInfo,
+			cinderv1beta1.CinderSchedulerCapabilitiesReadyRunningMessage,
+			strings.Join(waiting, ", ")))
+		return cinder.ResultRequeue, nil
+	}
+	instance.Status.Conditions.MarkTrue(
+		cinderv1beta1.CinderSchedulerCapabilitiesReadyCondition,
+		cinderv1beta1.CinderSchedulerCapabilitiesReadyMessage)
 	return ctrl.Result{}, nil
 }
 
@@ -715,14 +969,19 @@ func (r *CinderSchedulerReconciler) generateServiceConfigs(
 	}
 	customData[cinder.CustomServiceConfigSecretsFileName] = customSecrets
 
+	templateParameters := map[string]interface{}{
+		"SchedulerPolicy": cinderscheduler.GetSchedulerPolicyConfig(instance),
+	}
+
 	configTemplates := []util.Template{
 		{
-			Name:         fmt.Sprintf("%s-config-data", instance.Name),
-			Namespace:    instance.Namespace,
-			Type:         util.TemplateTypeConfig,
-			InstanceType: instance.Kind,
-			CustomData:   customData,
-			Labels:       labels,
+			Name:          fmt.Sprintf("%s-config-data", instance.Name),
+			Namespace:     instance.Namespace,
+			Type:          util.TemplateTypeConfig,
+			InstanceType:  instance.Kind,
+			CustomData:    customData,
+			ConfigOptions: templateParameters,
+			Labels:        labels,
 		},
 	}
 
@@ -22,6 +22,7 @@ import (
 	"strings"
 
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/fields"
@@ -45,10 +46,13 @@ import (
 	topologyv1 "github.com/openstack-k8s-operators/infra-operator/apis/topology/v1beta1"
 	"github.com/openstack-k8s-operators/lib-common/modules/common"
 	"github.com/openstack-k8s-operators/lib-common/modules/common/condition"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/daemonset"
 	"github.com/openstack-k8s-operators/lib-common/modules/common/env"
 	"github.com/openstack-k8s-operators/lib-common/modules/common/helper"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/job"
 	"github.com/openstack-k8s-operators/lib-common/modules/common/labels"
 	nad "github.com/openstack-k8s-operators/lib-common/modules/common/networkattachment"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/pod"
 	"github.com/openstack-k8s-operators/lib-common/modules/common/secret"
 	"github.com/openstack-k8s-operators/lib-common/modules/common/statefulset"
 	"github.com/openstack-k8s-operators/lib-common/modules/common/tls"
@@ -88,6 +92,9 @@ func (r *CinderVolumeReconciler) GetLogger(ctx context.Context) logr.Logger {
 // +kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;
 // +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=get;list;create;update;patch;delete;watch
+// +kubebuilder:rbac:groups=apps,resources=daemonsets,verbs=get;list;create;update;patch;delete;watch
+// +kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch;patch
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;create;update;patch;delete;watch
 // +kubebuilder:rbac:groups=security.openshift.io,namespace=openstack,resources=securitycontextconstraints,resourceNames=privileged,verbs=use
 // +kubebuilder:rbac:groups=k8s.cni.cncf.io,resources=network-attachment-definitions,verbs=get;list;watch
 // +kubebuilder:rbac:groups=topology.openstack.org,resources=topologies,verbs=get;list;watch;update
@@ -155,7 +162,26 @@ func (r *CinderVolumeReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		condition.UnknownCondition(condition.DeploymentReadyCondition, condition.InitReason, condition.DeploymentReadyInitMessage),
 		condition.UnknownCondition(condition.NetworkAttachmentsReadyCondition, condition.InitReason, condition.NetworkAttachmentsReadyInitMessage),
 		condition.UnknownCondition(condition.TLSInputReadyCondition, condition.InitReason, condition.InputReadyInitMessage),
+		condition.UnknownCondition(cinderv1beta1.CinderVolumeBackendsReadyCondition, condition.InitReason, cinderv1beta1.CinderVolumeBackendsReadyInitMessage),
 	)
+	if instance.Spec.Replication != nil || instance.Status.ActiveBackendID != "" {
+		cl.Set(condition.UnknownCondition(cinderv1beta1.CinderVolumeFailoverReadyCondition, condition.InitReason, cinderv1beta1.CinderVolumeFailoverReadyInitMessage))
+	}
+	if len(instance.Spec.HostPrerequisites) > 0 {
+		cl.Set(condition.UnknownCondition(cinderv1beta1.CinderVolumeHostPrerequisitesReadyCondition, condition.InitReason, cinderv1beta1.CinderVolumeHostPrerequisitesReadyInitMessage))
+	}
+	if instance.Spec.FibreChannel != nil {
+		cl.Set(condition.UnknownCondition(cinderv1beta1.FibreChannelReadyCondition, condition.InitReason, cinderv1beta1.FibreChannelReadyInitMessage))
+	}
+	if instance.Spec.LVM != nil {
+		cl.Set(condition.UnknownCondition(cinderv1beta1.CinderVolumeLVMReadyCondition, condition.InitReason, cinderv1beta1.CinderVolumeLVMReadyInitMessage))
+	}
+	if instance.Spec.ImagePinning != nil {
+		cl.Set(condition.UnknownCondition(cinderv1beta1.ImageResolvedCondition, condition.InitReason, cinderv1beta1.ImageResolvedInitMessage))
+	}
+	if instance.Spec.AvailabilityZone != nil {
+		cl.Set(condition.UnknownCondition(cinderv1beta1.AvailabilityZoneReadyCondition, condition.InitReason, cinderv1beta1.AvailabilityZoneReadyInitMessage))
+	}
 	instance.Status.Conditions.Init(&cl)
 	// Always mark the Generation as observed early on
 	instance.Status.ObservedGeneration = instance.Generation
@@ -271,6 +297,18 @@ func (r *CinderVolumeReconciler) SetupWithManager(ctx context.Context, mgr ctrl.
 		return err
 	}
 
+	// index clientCertField
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &cinderv1beta1.CinderVolume{}, clientCertField, func(rawObj client.Object) []string {
+		// Extract the secret name from the spec, if one is provided
+		cr := rawObj.(*cinderv1beta1.CinderVolume)
+		if cr.Spec.ClientCertSecretName == "" {
+			return nil
+		}
+		return []string{cr.Spec.ClientCertSecretName}
+	}); err != nil {
+		return err
+	}
+
 	// index topologyField
 	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &cinderv1beta1.CinderVolume{}, topologyField, func(rawObj client.Object) []string {
 		// Extract the topology name from the spec, if one is provided
@@ -286,6 +324,8 @@ func (r *CinderVolumeReconciler) SetupWithManager(ctx context.Context, mgr ctrl.
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&cinderv1beta1.CinderVolume{}).
 		Owns(&appsv1.StatefulSet{}).
+		Owns(&appsv1.DaemonSet{}).
+		Owns(&batchv1.Job{}).
 		// watch the secrets we don't own
 		Watches(&corev1.Secret{},
 			handler.EnqueueRequestsFromMapFunc(secretFn)).
@@ -297,15 +337,51 @@ func (r *CinderVolumeReconciler) SetupWithManager(ctx context.Context, mgr ctrl.
 		Watches(&topologyv1.Topology{},
 			handler.EnqueueRequestsFromMapFunc(r.findObjectsForSrc),
 			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		// the zone of the service follows the zone labels of the nodes
+		Watches(&corev1.Node{},
+			handler.EnqueueRequestsFromMapFunc(r.findObjectsForNode),
+			builder.WithPredicates(predicate.LabelChangedPredicate{})).
 		Complete(r)
 }
 
+// findObjectsForNode - returns the services whose availability zone is
+// derived from the zone of their nodes
+func (r *CinderVolumeReconciler) findObjectsForNode(ctx context.Context, node client.Object) []reconcile.Request {
+	requests := []reconcile.Request{}
+
+	l := log.FromContext(ctx).WithName("Controllers").WithName("CinderVolume")
+
+	crList := &cinderv1beta1.CinderVolumeList{}
+	if err := r.List(ctx, crList); err != nil {
+		l.Error(err, fmt.Sprintf("listing %s for node: %s", crList.GroupVersionKind().Kind, node.GetName()))
+		return requests
+	}
+
+	for _, item := range crList.Items {
+		if item.Spec.AvailabilityZone == nil || item.Spec.AvailabilityZone.Name != "" {
+			continue
+		}
+		l.Info(fmt.Sprintf("node %s labels changed, reconcile: %s - %s", node.GetName(), item.GetName(), item.GetNamespace()))
+
+		requests = append(requests,
+			reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      item.GetName(),
+					Namespace: item.GetNamespace(),
+				},
+			},
+		)
+	}
+
+	return requests
+}
+
 func (r *CinderVolumeReconciler) findObjectsForSrc(ctx context.Context, src client.Object) []reconcile.Request {
 	requests := []reconcile.Request{}
 
 	l := log.FromContext(ctx).WithName("Controllers").WithName("CinderVolume")
 
-	for _, field := range commonWatchFields {
+	for _, field := range cinderVolumeWatchFields {
 		crList := &cinderv1beta1.CinderVolumeList{}
 		listOps := &client.ListOptions{
 			FieldSelector: fields.OneTermEqualSelector(field, src.GetName()),
@@ -394,6 +470,14 @@ func (r *CinderVolumeReconciler) reconcileNormal(ctx context.Context, instance *
 		fmt.Sprintf("%s-scripts", parentCinderName),     // ScriptsSecret
 		fmt.Sprintf("%s-config-data", parentCinderName), // ConfigSecret
 	}
+	// Notifications go to a dedicated RabbitMQ cluster
+	if instance.Spec.NotificationURLSecret != "" {
+		secretNames = append(secretNames, instance.Spec.NotificationURLSecret)
+	}
+	// Client certificate presented to Nova and Glance
+	if instance.Spec.ClientCertSecretName != "" {
+		secretNames = append(secretNames, instance.Spec.ClientCertSecretName)
+	}
 	// Append CustomServiceConfigSecrets that should be checked
 	secretNames = append(secretNames, instance.Spec.CustomServiceConfigSecrets...)
 
@@ -460,10 +544,30 @@ func (r *CinderVolumeReconciler) reconcileNormal(ctx context.Context, instance *
 		cinderv1beta1.Backend:    instance.BackendName(),
 	}
 
+	//
+	// Determine the availability zone of the service
+	//
+	availabilityZone := ""
+	if instance.Spec.AvailabilityZone != nil {
+		availabilityZone, err = ensureAvailabilityZone(
+			ctx,
+			helper,
+			instance.Namespace,
+			instance.Spec.AvailabilityZone,
+			instance.Spec.NodeSelector,
+			instance.Spec.TopologyRef,
+			&instance.Status.Conditions,
+		)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	instance.Status.AvailabilityZone = availabilityZone
+
 	//
 	// create custom Configmap for this cinder volume service
 	//
-	usesLVM, err := r.generateServiceConfigs(ctx, helper, instance, &configVars, serviceLabels)
+	usesLVM, err := r.generateServiceConfigs(ctx, helper, instance, &configVars, serviceLabels, availabilityZone)
 	if err != nil {
 		instance.Status.Conditions.Set(condition.FalseCondition(
 			condition.ServiceConfigReadyCondition,
@@ -570,8 +674,78 @@ func (r *CinderVolumeReconciler) reconcileNormal(ctx context.Context, instance *
 		return ctrl.Result{}, fmt.Errorf("waiting for Topology requirements: %w", err)
 	}
 
+	// Pin the image of the service to a digest when requested, the jobs and
+	// daemonsets of the service run with it too
+	if instance.Status.Image == nil {
+		instance.Status.Image = &cinderv1beta1.CinderImageStatus{}
+	}
+	containerImage, ctrlResult, err := ensureImage(
+		ctx,
+		helper,
+		instance,
+		instance.Spec.ContainerImage,
+		instance.Spec.ServiceAccount,
+		instance.Spec.ImagePinning,
+		serviceLabels,
+		instance.Status.Image,
+		instance.Status.Hash,
+		&instance.Status.Conditions,
+	)
+	if err != nil || (ctrlResult != ctrl.Result{}) {
+		return ctrlResult, err
+	}
+
+	// The volume group of an LVM backend must exist before the service starts
+	if instance.Spec.LVM != nil {
+		ctrlResult, err = r.reconcileLVM(ctx, instance, helper, serviceLabels, serviceAnnotations, containerImage)
+		if err != nil || (ctrlResult != ctrl.Result{}) {
+			return ctrlResult, err
+		}
+	} else {
+		instance.Status.LVM = nil
+	}
+
+	// Verify the transports on the nodes and keep the service off the ones
+	// that don't have them
+	excludedNodes := []string{}
+	if len(instance.Spec.HostPrerequisites) > 0 {
+		excludedNodes, ctrlResult, err = r.reconcileHostPrerequisites(ctx, instance, helper, serviceLabels, topology, containerImage)
+		if err != nil || (ctrlResult != ctrl.Result{}) {
+			return ctrlResult, err
+		}
+	} else {
+		err = r.deleteHostPreflight(ctx, instance, helper)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	// Discover the FC HBAs of the nodes the service can run on
+	if instance.Spec.FibreChannel != nil {
+		dsDef := cinder.FCDiscoveryDaemonSet(
+			instance,
+			containerImage,
+			instance.Spec.ServiceAccount,
+			instance.Spec.NodeSelector,
+			topology,
+			cinder.GetFCDiscoveryLabels(serviceLabels),
+		)
+		fcNodes, ctrlResult, err := ensureFibreChannel(
+			ctx, helper, dsDef, instance.Spec.FibreChannel.Required, &instance.Status.Conditions)
+		if err != nil || (ctrlResult != ctrl.Result{}) {
+			return ctrlResult, err
+		}
+		instance.Status.FibreChannel = fcNodes
+	} else {
+		instance.Status.FibreChannel = nil
+		err = deleteFibreChannelDiscovery(ctx, helper, instance)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
 	// Deploy a statefulset
-	ssDef := cindervolume.StatefulSet(instance, inputHash, serviceLabels, serviceAnnotations, usesLVM, topology)
+	ssDef := cindervolume.StatefulSet(instance, inputHash, serviceLabels, serviceAnnotations, usesLVM, topology, excludedNodes, containerImage)
 	ss := statefulset.NewStatefulSet(ssDef, cinder.ShortDuration)
 
 	var ssData appsv1.StatefulSet
@@ -611,6 +785,13 @@ func (r *CinderVolumeReconciler) reconcileNormal(ctx context.Context, instance *
 
 	instance.Status.ReadyCount = ssData.Status.ReadyReplicas
 
+	// Report the image each pod is actually using
+	podImages, err := getPodImages(ctx, helper, instance.Namespace, serviceLabels, cindervolume.ComponentName)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	instance.Status.Image.PodImages = podImages
+
 	// verify if network attachment matches expectations
 	networkReady := false
 	networkAttachmentStatus := map[string][]string{}
@@ -669,15 +850,374 @@ func (r *CinderVolumeReconciler) reconcileNormal(ctx context.Context, instance *
 	}
 	// create StatefulSet - end
 
+	// Fail over or fail back the backends when the requested target changes
+	if instance.Spec.FailoverTarget() != instance.Status.ActiveBackendID {
+		ctrlResult, err = r.reconcileFailover(ctx, instance, helper, serviceLabels, serviceAnnotations, usesLVM, containerImage)
+		if err != nil || (ctrlResult != ctrl.Result{}) {
+			return ctrlResult, err
+		}
+	} else if instance.Status.Conditions.Has(cinderv1beta1.CinderVolumeFailoverReadyCondition) {
+		instance.Status.Conditions.MarkTrue(
+			cinderv1beta1.CinderVolumeFailoverReadyCondition,
+			cinderv1beta1.CinderVolumeFailoverReadyMessage,
+			failoverTargetName(instance.Status.ActiveBackendID))
+	}
+
+	// The state of the backends is only reported by running pods, so it
+	// needs to be polled periodically
+	ctrlResult = ctrl.Result{}
+	if *instance.Spec.Replicas > 0 {
+		r.reconcileBackendsStatus(ctx, instance, helper, serviceLabels)
+		if instance.Status.LVM != nil {
+			cindervolume.UpdateLVMCapacity(instance)
+		}
+		ctrlResult = ctrl.Result{RequeueAfter: cindervolume.BackendsStatusInterval}
+	} else {
+		instance.Status.Backends = nil
+		instance.Status.Conditions.Remove(cinderv1beta1.CinderVolumeBackendsReadyCondition)
+	}
+
 	Log.Info(fmt.Sprintf("Reconciled Service '%s' successfully", instance.Name))
 	// update the overall status condition if service is ready
 	if instance.IsReady() {
 		instance.Status.Conditions.MarkTrue(condition.ReadyCondition, condition.ReadyMessage)
 	}
 	// For non ready we'll let the main defer func handle the status update using the Mirror function
+	return ctrlResult, nil
+}
+
+// reconcileFailover - Runs the job that fails over the backends to the target
+// in the Failover field, or fails them back to the primary, and records the
+// new active backend in the status once it completes
+func (r *CinderVolumeReconciler) reconcileFailover(
+	ctx context.Context,
+	instance *cinderv1beta1.CinderVolume,
+	helper *helper.Helper,
+	serviceLabels map[string]string,
+	serviceAnnotations map[string]string,
+	usesLVM bool,
+	containerImage string,
+) (ctrl.Result, error) {
+	Log := r.GetLogger(ctx)
+	target := instance.Spec.FailoverTarget()
+
+	// The failover is requested through the running cinder-volume service
+	if instance.Status.ReadyCount == 0 {
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			cinderv1beta1.CinderVolumeFailoverReadyCondition,
+			condition.RequestedReason,
+			condition.SeverityInfo,
+			cinderv1beta1.CinderVolumeFailoverReadyWaitingMessage))
+		return cinder.ResultRequeue, nil
+	}
+
+	jobDef := cindervolume.FailoverJob(instance, serviceLabels, serviceAnnotations, usesLVM, containerImage)
+	failoverJob := job.NewJob(
+		jobDef,
+		cindervolume.FailoverHash,
+		false,
+		cinder.ShortDuration,
+		instance.Status.Hash[cindervolume.FailoverHash],
+	)
+	ctrlResult, err := failoverJob.DoJob(ctx, helper)
+	if (ctrlResult != ctrl.Result{}) {
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			cinderv1beta1.CinderVolumeFailoverReadyCondition,
+			condition.RequestedReason,
+			condition.SeverityInfo,
+			cinderv1beta1.CinderVolumeFailoverReadyRunningMessage,
+			failoverTargetName(target)))
+		return ctrlResult, nil
+	}
+	if err != nil {
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			cinderv1beta1.CinderVolumeFailoverReadyCondition,
+			condition.ErrorReason,
+			condition.SeverityWarning,
+			cinderv1beta1.CinderVolumeFailoverReadyErrorMessage,
+			err.Error()))
+		return ctrl.Result{}, err
+	}
+	if failoverJob.HasChanged() {
+		instance.Status.Hash[cindervolume.FailoverHash] = failoverJob.GetHash()
+		Log.Info(fmt.Sprintf("Service '%s' - Job %s hash added - %s", instance.Name, jobDef.Name, instance.Status.Hash[cindervolume.FailoverHash]))
+	}
+
+	instance.Status.ActiveBackendID = target
+	instance.Status.Conditions.MarkTrue(
+		cinderv1beta1.CinderVolumeFailoverReadyCondition,
+		cinderv1beta1.CinderVolumeFailoverReadyMessage,
+		failoverTargetName(target))
+	Log.Info(fmt.Sprintf("Service '%s' backends running on %s", instance.Name, failoverTargetName(target)))
 	return ctrl.Result{}, nil
 }
 
+// reconcileLVM - Runs the job that creates the volume group of an LVM backend
+// on its node, or checks that it's there, and records it in the status
+func (r *CinderVolumeReconciler) reconcileLVM(
+	ctx context.Context,
+	instance *cinderv1beta1.CinderVolume,
+	helper *helper.Helper,
+	serviceLabels map[string]string,
+	serviceAnnotations map[string]string,
+	containerImage string,
+) (ctrl.Result, error) {
+	Log := r.GetLogger(ctx)
+	lvm := instance.Spec.LVM
+
+	jobDef := cindervolume.LVMInitJob(instance, serviceLabels, serviceAnnotations, containerImage)
+	lvmInitJob := job.NewJob(
+		jobDef,
+		cindervolume.LVMInitHash,
+		false,
+		cinder.ShortDuration,
+		instance.Status.Hash[cindervolume.LVMInitHash],
+	)
+	ctrlResult, err := lvmInitJob.DoJob(ctx, helper)
+	if (ctrlResult != ctrl.Result{}) {
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			cinderv1beta1.CinderVolumeLVMReadyCondition,
+			condition.RequestedReason,
+			condition.SeverityInfo,
+			cinderv1beta1.CinderVolumeLVMReadyRunningMessage,
+			lvm.VolumeGroup,
+			lvm.NodeName))
+		return ctrlResult, nil
+	}
+	if err != nil {
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			cinderv1beta1.CinderVolumeLVMReadyCondition,
+			condition.ErrorReason,
+			condition.SeverityWarning,
+			cinderv1beta1.CinderVolumeLVMReadyErrorMessage,
+			err.Error()))
+		return ctrl.Result{}, err
+	}
+	if lvmInitJob.HasChanged() {
+		instance.Status.Hash[cindervolume.LVMInitHash] = lvmInitJob.GetHash()
+		Log.Info(fmt.Sprintf("Service '%s' - Job %s hash added - %s", instance.Name, jobDef.Name, instance.Status.Hash[cindervolume.LVMInitHash]))
+	}
+
+	// Keep the capacity reported by the backend while the volume group stays
+	// the same
+	if instance.Status.LVM == nil ||
+		instance.Status.LVM.NodeName != lvm.NodeName ||
+		instance.Status.LVM.VolumeGroup != lvm.VolumeGroup {
+		instance.Status.LVM = &cinderv1beta1.CinderVolumeLVMStatus{
+			NodeName:    lvm.NodeName,
+			VolumeGroup: lvm.VolumeGroup,
+		}
+	}
+	instance.Status.Conditions.MarkTrue(
+		cinderv1beta1.CinderVolumeLVMReadyCondition,
+		cinderv1beta1.CinderVolumeLVMReadyMessage,
+		lvm.VolumeGroup,
+		lvm.NodeName)
+	return ctrl.Result{}, nil
+}
+
+// reconcileHostPrerequisites - Runs the DaemonSet that verifies the host
+// prerequisites on the nodes the service can run on, and returns the nodes
+// that failed the verification
+func (r *CinderVolumeReconciler) reconcileHostPrerequisites(
+	ctx context.Context,
+	instance *cinderv1beta1.CinderVolume,
+	helper *helper.Helper,
+	serviceLabels map[string]string,
+	topology *topologyv1.Topology,
+	containerImage string,
+) ([]string, ctrl.Result, error) {
+	Log := r.GetLogger(ctx)
+	preflightLabels := cindervolume.GetHostPreflightLabels(serviceLabels)
+
+	dsDef := cindervolume.HostPreflightDaemonSet(instance, preflightLabels, topology, containerImage)
+	ds := daemonset.NewDaemonSet(dsDef, cinder.ShortDuration)
+	ctrlResult, err := ds.CreateOrPatch(ctx, helper)
+	if err != nil {
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			cinderv1beta1.CinderVolumeHostPrerequisitesReadyCondition,
+			condition.ErrorReason,
+			condition.SeverityWarning,
+			cinderv1beta1.CinderVolumeHostPrerequisitesReadyErrorMessage,
+			err.Error()))
+		return nil, ctrlResult, err
+	}
+
+	// Wait until there is an up to date pod on every node
+	dsData := ds.GetDaemonSet()
+	if (ctrlResult != ctrl.Result{}) ||
+		dsData.Generation != dsData.Status.ObservedGeneration ||
+		dsData.Status.UpdatedNumberScheduled != dsData.Status.DesiredNumberScheduled {
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			cinderv1beta1.CinderVolumeHostPrerequisitesReadyCondition,
+			condition.RequestedReason,
+			condition.SeverityInfo,
+			cinderv1beta1.CinderVolumeHostPrerequisitesReadyRunningMessage))
+		return nil, cinder.ResultRequeue, nil
+	}
+
+	podList, err := pod.GetPodListWithLabel(ctx, helper, instance.Namespace, preflightLabels)
+	if err != nil {
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			cinderv1beta1.CinderVolumeHostPrerequisitesReadyCondition,
+			condition.ErrorReason,
+			condition.SeverityWarning,
+			cinderv1beta1.CinderVolumeHostPrerequisitesReadyErrorMessage,
+			err.Error()))
+		return nil, ctrl.Result{}, err
+	}
+
+	results := cindervolume.GetHostPreflightResults(podList.Items)
+	failedNodes := results.FailedNodes()
+
+	// The service is only kept off the nodes that failed the verification.
+	// The nodes that haven't completed it yet (eg: their pod can't be pulled
+	// or scheduled) come and go, and excluding them from the template of the
+	// StatefulSet would roll the service every time they change
+	excludedNodes := failedNodes
+
+	if len(failedNodes) > 0 {
+		failures := []string{}
+		for _, node := range failedNodes {
+			failures = append(failures, fmt.Sprintf("%s (%s)", node, results.Failed[node]))
+		}
+		Log.Info(fmt.Sprintf("Service '%s' host prerequisites failed on nodes %s", instance.Name, strings.Join(failedNodes, ", ")))
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			cinderv1beta1.CinderVolumeHostPrerequisitesReadyCondition,
+			condition.ErrorReason,
+			condition.SeverityWarning,
+			cinderv1beta1.CinderVolumeHostPrerequisitesFailedMessage,
+			strings.Join(failures, ", ")))
+		return excludedNodes, ctrl.Result{}, nil
+	}
+
+	if len(results.Pending) > 0 {
+		Log.Info(fmt.Sprintf("Service '%s' host prerequisites pending on nodes %s", instance.Name, strings.Join(results.Pending, ", ")))
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			cinderv1beta1.CinderVolumeHostPrerequisitesReadyCondition,
+			condition.RequestedReason,
+			condition.SeverityInfo,
+			cinderv1beta1.CinderVolumeHostPrerequisitesPendingMessage,
+			strings.Join(results.Pending, ", ")))
+		return excludedNodes, ctrl.Result{}, nil
+	}
+
+	instance.Status.Conditions.MarkTrue(
+		cinderv1beta1.CinderVolumeHostPrerequisitesReadyCondition,
+		cinderv1beta1.CinderVolumeHostPrerequisitesReadyMessage)
+	return excludedNodes, ctrl.Result{}, nil
+}
+
+// deleteHostPreflight - Deletes the host preflight DaemonSet when the service
+// no longer has host prerequisites
+func (r *CinderVolumeReconciler) deleteHostPreflight(
+	ctx context.Context,
+	instance *cinderv1beta1.CinderVolume,
+	helper *helper.Helper,
+) error {
+	ds, err := daemonset.GetDaemonSetWithName(
+		ctx, helper, cindervolume.GetHostPreflightName(instance), instance.Namespace)
+	if err != nil {
+		if k8s_errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	return daemonset.NewDaemonSet(ds, cinder.ShortDuration).Delete(ctx, helper)
+}
+
+// failoverTargetName - name of a failover target for the condition messages
+func failoverTargetName(target string) string {
+	if target == "" {
+		return cinderv1beta1.FailoverPrimary
+	}
+	return target
+}
+
+// reconcileBackendsStatus - Updates the status with the state of the backends
+// reported by the healthcheck sidecar of the running pods. Failing to get it
+// is not an error for the reconciliation, it's only reflected in the
+// CinderVolumeBackendsReady condition.
+func (r *CinderVolumeReconciler) reconcileBackendsStatus(
+	ctx context.Context,
+	instance *cinderv1beta1.CinderVolume,
+	helper *helper.Helper,
+	serviceLabels map[string]string,
+) {
+	Log := r.GetLogger(ctx)
+
+	podList, err := pod.GetPodListWithLabel(ctx, helper, instance.Namespace, serviceLabels)
+	if err != nil {
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			cinderv1beta1.CinderVolumeBackendsReadyCondition,
+			condition.ErrorReason,
+			condition.SeverityWarning,
+			cinderv1beta1.CinderVolumeBackendsReadyErrorMessage,
+			err.Error()))
+		return
+	}
+
+	// A pod that fails to report its backends doesn't prevent reporting the
+	// others, and its backends are not kept in the status with a state that
+	// is no longer accurate
+	backends := []cinderv1beta1.CinderVolumeBackendStatus{}
+	failures := []string{}
+	running := false
+	for _, p := range podList.Items {
+		if p.Status.Phase != corev1.PodRunning || p.Status.PodIP == "" {
+			continue
+		}
+		running = true
+		podBackends, err := cindervolume.GetBackendsStatus(ctx, p.Status.PodIP)
+		if err != nil {
+			Log.Info(fmt.Sprintf("Failed to get the backends status from pod %s: %s", p.Name, err))
+			failures = append(failures, fmt.Sprintf("%s: %s", p.Name, err))
+			continue
+		}
+		backends = append(backends, podBackends...)
+	}
+
+	// Nothing to report until there are running pods
+	if !running {
+		instance.Status.Backends = nil
+		instance.Status.Conditions.Set(condition.UnknownCondition(
+			cinderv1beta1.CinderVolumeBackendsReadyCondition,
+			condition.InitReason,
+			cinderv1beta1.CinderVolumeBackendsReadyInitMessage))
+		return
+	}
+	instance.Status.Backends = backends
+
+	if len(failures) > 0 {
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			cinderv1beta1.CinderVolumeBackendsReadyCondition,
+			condition.ErrorReason,
+			condition.SeverityWarning,
+			cinderv1beta1.CinderVolumeBackendsReadyErrorMessage,
+			strings.Join(failures, ", ")))
+		return
+	}
+
+	down := []string{}
+	for _, backend := range backends {
+		if !backend.Up {
+			down = append(down, fmt.Sprintf("%s (%s)", backend.Name, backend.Host))
+		}
+	}
+	if len(down) > 0 {
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			cinderv1beta1.CinderVolumeBackendsReadyCondition,
+			condition.ErrorReason,
+			condition.SeverityWarning,
+			cinderv1beta1.CinderVolumeBackendsDownMessage,
+			strings.Join(down, ", ")))
+		return
+	}
+	instance.Status.Conditions.MarkTrue(
+		cinderv1beta1.CinderVolumeBackendsReadyCondition,
+		cinderv1beta1.CinderVolumeBackendsReadyMessage)
+}
+
 // generateServiceConfigs - create Secret which holds the service configuration and check if it's using LVM
 func (r *CinderVolumeReconciler) generateServiceConfigs(
 	ctx context.Context,
@@ -685,6 +1225,7 @@ func (r *CinderVolumeReconciler) generateServiceConfigs(
 	instance *cinderv1beta1.CinderVolume,
 	envVars *map[string]env.Setter,
 	serviceLabels map[string]string,
+	availabilityZone string,
 ) (bool, error) {
 	//
 	// create custom Secret for cinder service-specific config input
@@ -695,6 +1236,7 @@ func (r *CinderVolumeReconciler) generateServiceConfigs(
 
 	// customData hold any customization for the service.
 	usesLVM, customServiceConfig := processCustomServiceConfig(instance.Spec.CustomServiceConfig)
+	usesLVM = usesLVM || instance.Spec.LVM != nil
 	customData := map[string]string{cinder.CustomServiceConfigFileName: customServiceConfig}
 
 	// Fetch the two service config snippets (DefaultsConfigFileName and
@@ -720,14 +1262,22 @@ func (r *CinderVolumeReconciler) generateServiceConfigs(
 	}
 	customData[cinder.CustomServiceConfigSecretsFileName] = customSecrets
 
+	templateParameters := map[string]interface{}{
+		"ReplicationDevices": cindervolume.GetReplicationDevices(instance),
+		"LVM":                cindervolume.GetLVMConfig(instance),
+		"AvailabilityZone":   availabilityZone,
+		"SchedulerFunctions": instance.Spec.SchedulerFunctions,
+	}
+
 	configTemplates := []util.Template{
 		{
-			Name:         fmt.Sprintf("%s-config-data", instance.Name),
-			Namespace:    instance.Namespace,
-			Type:         util.TemplateTypeConfig,
-			InstanceType: instance.Kind,
-			CustomData:   customData,
-			Labels:       labels,
+			Name:          fmt.Sprintf("%s-config-data", instance.Name),
+			Namespace:     instance.Namespace,
+			Type:          util.TemplateTypeConfig,
+			InstanceType:  instance.Kind,
+			CustomData:    customData,
+			ConfigOptions: templateParameters,
+			Labels:        labels,
 		},
 	}
 
@@ -32,6 +32,10 @@ const (
 	ServiceType = "cinder"
 	// ServiceTypeV3 -
 	ServiceTypeV3 = "volumev3"
+	// ServiceNameBlockStorage -
+	ServiceNameBlockStorage = "cinder"
+	// ServiceTypeBlockStorage -
+	ServiceTypeBlockStorage = "block-storage"
 	// DatabaseName -
 	DatabaseName = "cinder"
 
@@ -52,6 +56,27 @@ const (
 	CinderPublicPort int32 = 8776
 	// CinderInternalPort -
 	CinderInternalPort int32 = 8776
+	// HealthcheckPort - port of the healthcheck sidecar of the scheduler,
+	// backup and volume services
+	HealthcheckPort = 8080
+	// HealthcheckMessagingPath - path of the healthcheck sidecar that reports
+	// the message bus connectivity of the scheduler, backup and volume services
+	HealthcheckMessagingPath = "/messaging"
+	// HealthcheckTimeoutEnvVar - environment variable with the timeout of the
+	// readiness probe, which bounds the checks of the healthcheck sidecar
+	HealthcheckTimeoutEnvVar = "HEALTHCHECK_TIMEOUT"
+	// APICertsPath - directory where httpd reads the certificates of the API
+	// endpoints from, which the kubelet updates in place when they are renewed
+	APICertsPath = "/var/lib/config-data/api-certs"
+	// ClientCertMountPath - directory where the secret with the client
+	// certificate presented to Nova and Glance is mounted
+	ClientCertMountPath = "/var/lib/config-data/client-certs"
+	// ClientCertPath - directory where kolla copies the client certificate so
+	// the services can read it as the cinder user
+	ClientCertPath = "/etc/pki/tls/cinder-client"
+	// CertReloadScript - script that gracefully reloads httpd when the
+	// certificates of the API endpoints are renewed
+	CertReloadScript = "/usr/local/bin/container-scripts/cert-reload.sh"
 
 	// CinderExtraVolTypeUndefined can be used to label an extraMount which
 	// is not associated with a specific backend
@@ -32,6 +32,7 @@ func CronJob(
 	instance *cinderv1.Cinder,
 	labels map[string]string,
 	annotations map[string]string,
+	containerImage string,
 ) *batchv1.CronJob {
 	cinderUser := int64(cinderv1.CinderUserID)
 	cinderGroup := int64(cinderv1.CinderGroupID)
@@ -113,7 +114,7 @@ func CronJob(
 							Containers: []corev1.Container{
 								{
 									Name:  ServiceName + "-db-purge",
-									Image: instance.Spec.CinderAPI.ContainerImage,
+									Image: containerImage,
 									Command: []string{
 										"/bin/bash",
 									},
@@ -21,7 +21,12 @@ const (
 )
 
 // DbSyncJob func
-func DbSyncJob(instance *cinderv1beta1.Cinder, labels map[string]string, annotations map[string]string) *batchv1.Job {
+func DbSyncJob(
+	instance *cinderv1beta1.Cinder,
+	labels map[string]string,
+	annotations map[string]string,
+	containerImage string,
+) *batchv1.Job {
 	var config0644AccessMode int32 = 0644
 
 	// Unlike the individual cinder services, the DbSyncJob doesn't need a
@@ -101,7 +106,7 @@ func DbSyncJob(instance *cinderv1beta1.Cinder, labels map[string]string, annotat
 								"/bin/bash",
 							},
 							Args:  args,
-							Image: instance.Spec.CinderAPI.ContainerImage,
+							Image: containerImage,
 							SecurityContext: &corev1.SecurityContext{
 								RunAsUser: &runAsUser,
 							},
@@ -0,0 +1,265 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cinder
+
+import (
+	"sort"
+	"strings"
+
+	cinderv1 "github.com/openstack-k8s-operators/cinder-operator/api/v1beta1"
+	topologyv1 "github.com/openstack-k8s-operators/infra-operator/apis/topology/v1beta1"
+	common "github.com/openstack-k8s-operators/lib-common/modules/common"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// FibreChannelNodeLabel - label of the nodes that have FC HBAs
+	FibreChannelNodeLabel = "cinder.openstack.org/fibre-channel"
+
+	// FCDiscoveryCommand -
+	FCDiscoveryCommand = "/usr/local/bin/container-scripts/fc-discovery.sh"
+
+	// fcDiscoveryContainerName - name of the init container that discovers
+	// the WWPNs
+	fcDiscoveryContainerName = "fc-discovery"
+
+	// fcDiscoverySysfsPath - where the host sysfs is mounted
+	fcDiscoverySysfsPath = "/host/sys"
+)
+
+// FCDiscoveryResults - WWPNs discovered on the nodes
+type FCDiscoveryResults struct {
+	// Nodes - FC HBAs of the nodes that completed the discovery, sorted by
+	// node name
+	Nodes []cinderv1.FibreChannelNodeStatus
+	// Failed - reasons of the failure for every node where the discovery
+	// failed
+	Failed map[string]string
+	// Pending - nodes that haven't completed the discovery yet
+	Pending []string
+}
+
+// FailedNodes - Returns the sorted names of the nodes where the discovery
+// failed
+func (r FCDiscoveryResults) FailedNodes() []string {
+	nodes := []string{}
+	for node := range r.Failed {
+		nodes = append(nodes, node)
+	}
+	sort.Strings(nodes)
+	return nodes
+}
+
+// GetFCDiscoveryName - Returns the name of the FC discovery DaemonSet of a
+// CinderVolume or CinderBackup
+func GetFCDiscoveryName(instance client.Object) string {
+	return instance.GetName() + "-fc-discovery"
+}
+
+// GetFCDiscoveryLabels - Returns the labels of the FC discovery pods, which
+// must not match the ones of the service pods
+func GetFCDiscoveryLabels(serviceLabels map[string]string) map[string]string {
+	discoveryLabels := map[string]string{}
+	for k, v := range serviceLabels {
+		discoveryLabels[k] = v
+	}
+	discoveryLabels[common.ComponentSelector] = serviceLabels[common.ComponentSelector] + "-fc-discovery"
+	return discoveryLabels
+}
+
+// FCDiscoveryDaemonSet - DaemonSet that discovers the WWPNs of the FC HBAs
+// of all the nodes a CinderVolume or CinderBackup can run on. The discovery
+// runs in an init container, and the pod stays idle afterwards so the result
+// remains in its status.
+func FCDiscoveryDaemonSet(
+	instance client.Object,
+	containerImage string,
+	serviceAccount string,
+	nodeSelector *map[string]string,
+	topology *topologyv1.Topology,
+	labels map[string]string,
+) *appsv1.DaemonSet {
+	var scriptsVolumeDefaultMode int32 = 0755
+	hostPathDirectory := corev1.HostPathDirectory
+	cinderUser := int64(cinderv1.CinderUserID)
+
+	volumeMounts := []corev1.VolumeMount{
+		{
+			Name:      "scripts",
+			MountPath: "/usr/local/bin/container-scripts",
+			ReadOnly:  true,
+		},
+		{
+			Name:      "sys",
+			MountPath: fcDiscoverySysfsPath,
+			ReadOnly:  true,
+		},
+	}
+
+	daemonset := &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      GetFCDiscoveryName(instance),
+			Namespace: instance.GetNamespace(),
+			Labels:    labels,
+		},
+		Spec: appsv1.DaemonSetSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: labels,
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+				},
+				Spec: corev1.PodSpec{
+					ServiceAccountName: serviceAccount,
+					InitContainers: []corev1.Container{
+						{
+							Name:    fcDiscoveryContainerName,
+							Command: []string{FCDiscoveryCommand},
+							Image:   containerImage,
+							Env: []corev1.EnvVar{
+								{Name: "SYSFS", Value: fcDiscoverySysfsPath},
+							},
+							SecurityContext: &corev1.SecurityContext{
+								RunAsUser: &cinderUser,
+							},
+							TerminationMessagePolicy: corev1.TerminationMessageReadFile,
+							VolumeMounts:             volumeMounts,
+						},
+					},
+					Containers: []corev1.Container{
+						{
+							Name:    "idle",
+							Command: []string{"/bin/sleep", "infinity"},
+							Image:   containerImage,
+							SecurityContext: &corev1.SecurityContext{
+								RunAsUser: &cinderUser,
+							},
+						},
+					},
+					Volumes: []corev1.Volume{
+						{
+							Name: "scripts",
+							VolumeSource: corev1.VolumeSource{
+								Secret: &corev1.SecretVolumeSource{
+									DefaultMode: &scriptsVolumeDefaultMode,
+									SecretName:  GetOwningCinderName(instance) + "-scripts",
+								},
+							},
+						},
+						{
+							Name: "sys",
+							VolumeSource: corev1.VolumeSource{
+								HostPath: &corev1.HostPathVolumeSource{
+									Path: "/sys",
+									Type: &hostPathDirectory,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	if nodeSelector != nil {
+		daemonset.Spec.Template.Spec.NodeSelector = *nodeSelector
+	}
+
+	// Only discover the nodes the service can be scheduled on, the rest of
+	// the topology is about the placement of the service pods among them
+	if topology != nil && topology.Spec.Affinity != nil && topology.Spec.Affinity.NodeAffinity != nil {
+		daemonset.Spec.Template.Spec.Affinity = &corev1.Affinity{
+			NodeAffinity: topology.Spec.Affinity.NodeAffinity.DeepCopy(),
+		}
+	}
+
+	return daemonset
+}
+
+// GetFCDiscoveryResults - Returns the WWPNs reported by the FC discovery
+// pods. The discovery failed on a node when its init container terminated
+// with an error, even if it's being retried.
+func GetFCDiscoveryResults(pods []corev1.Pod) FCDiscoveryResults {
+	results := FCDiscoveryResults{
+		Nodes:  []cinderv1.FibreChannelNodeStatus{},
+		Failed: map[string]string{},
+	}
+	for _, p := range pods {
+		if p.Spec.NodeName == "" || !p.DeletionTimestamp.IsZero() {
+			continue
+		}
+		var terminated, lastTerminated *corev1.ContainerStateTerminated
+		for _, status := range p.Status.InitContainerStatuses {
+			if status.Name == fcDiscoveryContainerName {
+				terminated = status.State.Terminated
+				lastTerminated = status.LastTerminationState.Terminated
+			}
+		}
+		switch {
+		case terminated != nil && terminated.ExitCode != 0:
+			results.Failed[p.Spec.NodeName] = terminated.Message
+			continue
+		case terminated == nil && lastTerminated != nil && lastTerminated.ExitCode != 0:
+			results.Failed[p.Spec.NodeName] = lastTerminated.Message
+			continue
+		case terminated == nil:
+			results.Pending = append(results.Pending, p.Spec.NodeName)
+			continue
+		}
+		node := cinderv1.FibreChannelNodeStatus{NodeName: p.Spec.NodeName}
+		for _, wwpn := range strings.Split(terminated.Message, ",") {
+			if wwpn = strings.TrimSpace(wwpn); wwpn != "" {
+				node.WWPNs = append(node.WWPNs, FormatWWPN(wwpn))
+			}
+		}
+		results.Nodes = append(results.Nodes, node)
+	}
+	sort.Slice(results.Nodes, func(i, j int) bool {
+		return results.Nodes[i].NodeName < results.Nodes[j].NodeName
+	})
+	sort.Strings(results.Pending)
+	return results
+}
+
+// FormatWWPN - Returns a WWPN reported by sysfs (0x10000090fa1b2c3d) in the
+// colon separated form used for zoning (10:00:00:90:fa:1b:2c:3d)
+func FormatWWPN(wwpn string) string {
+	wwpn = strings.ToLower(strings.TrimPrefix(wwpn, "0x"))
+	if len(wwpn) != 16 {
+		return wwpn
+	}
+	octets := make([]string, 0, 8)
+	for i := 0; i < len(wwpn); i += 2 {
+		octets = append(octets, wwpn[i:i+2])
+	}
+	return strings.Join(octets, ":")
+}
+
+// RequireFibreChannelNodes - Restricts the pod spec to the nodes that have
+// FC HBAs, keeping any existing node selector
+func RequireFibreChannelNodes(podSpec *corev1.PodSpec) {
+	nodeSelector := map[string]string{}
+	for k, v := range podSpec.NodeSelector {
+		nodeSelector[k] = v
+	}
+	nodeSelector[FibreChannelNodeLabel] = "true"
+	podSpec.NodeSelector = nodeSelector
+}
@@ -0,0 +1,72 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cinder
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega" //revive:disable:dot-imports
+
+	cinderv1 "github.com/openstack-k8s-operators/cinder-operator/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func discoveryPod(nodeName string, state corev1.ContainerState, lastState corev1.ContainerState) corev1.Pod {
+	return corev1.Pod{
+		Spec: corev1.PodSpec{NodeName: nodeName},
+		Status: corev1.PodStatus{
+			InitContainerStatuses: []corev1.ContainerStatus{{
+				Name:                 fcDiscoveryContainerName,
+				State:                state,
+				LastTerminationState: lastState,
+			}},
+		},
+	}
+}
+
+func TestGetFCDiscoveryResults(t *testing.T) {
+	g := NewWithT(t)
+
+	pods := []corev1.Pod{
+		discoveryPod("node-d", corev1.ContainerState{
+			Terminated: &corev1.ContainerStateTerminated{Message: "0x10000090fa1b2c3d,0x10000090fa1b2c3e"},
+		}, corev1.ContainerState{}),
+		discoveryPod("node-c", corev1.ContainerState{
+			Terminated: &corev1.ContainerStateTerminated{ExitCode: 1, Message: "no sysfs"},
+		}, corev1.ContainerState{}),
+		// Restarting after a failure
+		discoveryPod("node-b", corev1.ContainerState{
+			Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"},
+		}, corev1.ContainerState{
+			Terminated: &corev1.ContainerStateTerminated{ExitCode: 1, Message: "no sysfs"},
+		}),
+		discoveryPod("node-a", corev1.ContainerState{
+			Running: &corev1.ContainerStateRunning{},
+		}, corev1.ContainerState{}),
+		discoveryPod("node-e", corev1.ContainerState{
+			Terminated: &corev1.ContainerStateTerminated{},
+		}, corev1.ContainerState{}),
+	}
+
+	results := GetFCDiscoveryResults(pods)
+	g.Expect(results.Nodes).To(Equal([]cinderv1.FibreChannelNodeStatus{
+		{NodeName: "node-d", WWPNs: []string{"10:00:00:90:fa:1b:2c:3d", "10:00:00:90:fa:1b:2c:3e"}},
+		{NodeName: "node-e"},
+	}))
+	g.Expect(results.Failed).To(Equal(map[string]string{"node-b": "no sysfs", "node-c": "no sysfs"}))
+	g.Expect(results.FailedNodes()).To(Equal([]string{"node-b", "node-c"}))
+	g.Expect(results.Pending).To(Equal([]string{"node-a"}))
+}
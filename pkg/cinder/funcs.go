@@ -47,3 +47,46 @@ func GetPodAffinity(componentName string) *corev1.Affinity {
 		corev1.LabelHostname,
 	)
 }
+
+// ExcludeNodes - Adds a required node affinity to the pod spec so it's not
+// scheduled on any of the given nodes, keeping any existing node affinity
+func ExcludeNodes(podSpec *corev1.PodSpec, nodeNames []string) {
+	if len(nodeNames) == 0 {
+		return
+	}
+
+	// The hostname label doesn't always match the name of the node, and a
+	// field requirement only takes a single value
+	requirements := []corev1.NodeSelectorRequirement{}
+	for _, nodeName := range nodeNames {
+		requirements = append(requirements, corev1.NodeSelectorRequirement{
+			Key:      "metadata.name",
+			Operator: corev1.NodeSelectorOpNotIn,
+			Values:   []string{nodeName},
+		})
+	}
+
+	// The affinity may come from a Topology, so it must not be modified
+	if podSpec.Affinity == nil {
+		podSpec.Affinity = &corev1.Affinity{}
+	} else {
+		podSpec.Affinity = podSpec.Affinity.DeepCopy()
+	}
+	if podSpec.Affinity.NodeAffinity == nil {
+		podSpec.Affinity.NodeAffinity = &corev1.NodeAffinity{}
+	}
+	nodeAffinity := podSpec.Affinity.NodeAffinity
+	if nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution = &corev1.NodeSelector{}
+	}
+	nodeSelector := nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+
+	// Terms are ORed, so the requirements must be in all of them
+	if len(nodeSelector.NodeSelectorTerms) == 0 {
+		nodeSelector.NodeSelectorTerms = []corev1.NodeSelectorTerm{{}}
+	}
+	for i := range nodeSelector.NodeSelectorTerms {
+		term := &nodeSelector.NodeSelectorTerms[i]
+		term.MatchFields = append(term.MatchFields, requirements...)
+	}
+}
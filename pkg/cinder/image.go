@@ -0,0 +1,132 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cinder
+
+import (
+	"fmt"
+	"strings"
+
+	cinderv1 "github.com/openstack-k8s-operators/cinder-operator/api/v1beta1"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// ImageResolveHash - hash of the last image resolve job that completed
+	ImageResolveHash = "imageresolve"
+
+	// digestSeparator - separator of the repository and the digest of a
+	// pinned image
+	digestSeparator = "@"
+)
+
+// ImageResolveJob - Job that pulls the image of a Cinder service to find the
+// digest its tag resolves to. The digest is reported by the kubelet in the
+// status of the pod.
+func ImageResolveJob(
+	instance client.Object,
+	containerImage string,
+	serviceAccount string,
+	refresh string,
+	labels map[string]string,
+) *batchv1.Job {
+	cinderUser := int64(cinderv1.CinderUserID)
+
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      instance.GetName() + "-image-resolve",
+			Namespace: instance.GetNamespace(),
+			Labels:    labels,
+		},
+		Spec: batchv1.JobSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					RestartPolicy:      corev1.RestartPolicyOnFailure,
+					ServiceAccountName: serviceAccount,
+					Containers: []corev1.Container{
+						{
+							Name:    "image-resolve",
+							Command: []string{"/bin/true"},
+							Image:   containerImage,
+							// Always pull to get the digest the tag currently
+							// resolves to in the registry
+							ImagePullPolicy: corev1.PullAlways,
+							// Changing the refresh value changes the hash of
+							// the job, so it runs again
+							Env: []corev1.EnvVar{
+								{Name: "REFRESH", Value: refresh},
+							},
+							SecurityContext: &corev1.SecurityContext{
+								RunAsUser: &cinderUser,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	return job
+}
+
+// IsPinnedImage - Returns whether an image is already pinned to a digest
+func IsPinnedImage(image string) bool {
+	return strings.Contains(image, digestSeparator)
+}
+
+// GetImageRepository - Returns the repository of an image, without its tag
+// or digest
+func GetImageRepository(image string) string {
+	image, _, _ = strings.Cut(image, digestSeparator)
+	// A colon after the last slash separates the tag, otherwise it's the
+	// port of the registry
+	if idx := strings.LastIndex(image, ":"); idx > strings.LastIndex(image, "/") {
+		image = image[:idx]
+	}
+	return image
+}
+
+// GetPinnedImage - Returns the image pinned to the digest in the image ID
+// reported by the kubelet for a container running the image
+func GetPinnedImage(image string, imageID string) (string, error) {
+	// An image ID without a repository is the ID of the local image, not the
+	// digest of the manifest in the registry
+	_, digest, found := strings.Cut(strings.TrimPrefix(imageID, "docker-pullable://"), digestSeparator)
+	if !found {
+		return "", fmt.Errorf("no digest in image ID %q of image %s", imageID, image)
+	}
+	return GetImageRepository(image) + digestSeparator + digest, nil
+}
+
+// GetPodImages - Returns the image ID the container with the given name is
+// using in each of the running pods
+func GetPodImages(pods []corev1.Pod, containerName string) map[string]string {
+	podImages := map[string]string{}
+	for _, p := range pods {
+		if p.Status.Phase != corev1.PodRunning {
+			continue
+		}
+		for _, status := range p.Status.ContainerStatuses {
+			if status.Name == containerName && status.ImageID != "" {
+				podImages[p.Name] = status.ImageID
+			}
+		}
+	}
+	return podImages
+}
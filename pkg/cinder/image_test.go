@@ -0,0 +1,125 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cinder
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega" //revive:disable:dot-imports
+)
+
+func TestGetImageRepository(t *testing.T) {
+	tests := []struct {
+		image      string
+		repository string
+	}{
+		{
+			image:      "quay.io/podified-antelope-centos9/openstack-cinder-api:current-podified",
+			repository: "quay.io/podified-antelope-centos9/openstack-cinder-api",
+		},
+		{
+			image:      "quay.io/podified-antelope-centos9/openstack-cinder-api",
+			repository: "quay.io/podified-antelope-centos9/openstack-cinder-api",
+		},
+		{
+			image:      "registry.example.com:5000/cinder/openstack-cinder-api:latest",
+			repository: "registry.example.com:5000/cinder/openstack-cinder-api",
+		},
+		{
+			image:      "registry.example.com:5000/cinder/openstack-cinder-api",
+			repository: "registry.example.com:5000/cinder/openstack-cinder-api",
+		},
+		{
+			image:      "quay.io/cinder/openstack-cinder-api@sha256:1234",
+			repository: "quay.io/cinder/openstack-cinder-api",
+		},
+		{
+			image:      "quay.io/cinder/openstack-cinder-api:latest@sha256:1234",
+			repository: "quay.io/cinder/openstack-cinder-api",
+		},
+		{
+			image:      "openstack-cinder-api:latest",
+			repository: "openstack-cinder-api",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.image, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(GetImageRepository(tt.image)).To(Equal(tt.repository))
+		})
+	}
+}
+
+func TestGetPinnedImage(t *testing.T) {
+	tests := []struct {
+		name    string
+		image   string
+		imageID string
+		pinned  string
+		err     bool
+	}{
+		{
+			name:    "registry digest",
+			image:   "quay.io/cinder/openstack-cinder-api:current-podified",
+			imageID: "quay.io/cinder/openstack-cinder-api@sha256:1234",
+			pinned:  "quay.io/cinder/openstack-cinder-api@sha256:1234",
+		},
+		{
+			name:    "docker pullable digest",
+			image:   "quay.io/cinder/openstack-cinder-api:current-podified",
+			imageID: "docker-pullable://quay.io/cinder/openstack-cinder-api@sha256:1234",
+			pinned:  "quay.io/cinder/openstack-cinder-api@sha256:1234",
+		},
+		{
+			name:    "registry with port",
+			image:   "registry.example.com:5000/cinder/openstack-cinder-api:latest",
+			imageID: "registry.example.com:5000/cinder/openstack-cinder-api@sha256:1234",
+			pinned:  "registry.example.com:5000/cinder/openstack-cinder-api@sha256:1234",
+		},
+		{
+			name:    "mirrored registry",
+			image:   "quay.io/cinder/openstack-cinder-api:latest",
+			imageID: "mirror.example.com/cinder/openstack-cinder-api@sha256:1234",
+			pinned:  "quay.io/cinder/openstack-cinder-api@sha256:1234",
+		},
+		{
+			name:    "local image ID",
+			image:   "quay.io/cinder/openstack-cinder-api:latest",
+			imageID: "sha256:1234",
+			err:     true,
+		},
+		{
+			name:    "no image ID",
+			image:   "quay.io/cinder/openstack-cinder-api:latest",
+			imageID: "",
+			err:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			pinned, err := GetPinnedImage(tt.image, tt.imageID)
+			if tt.err {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(pinned).To(Equal(tt.pinned))
+		})
+	}
+}
@@ -0,0 +1,72 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cinder
+
+import (
+	"fmt"
+	"slices"
+
+	networkv1 "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// PodInfoPath - directory where the downward API exposes the network
+	// status of the pod
+	PodInfoPath = "/var/lib/pod-info"
+	// StorageIPScript - script that starts a service with my_ip set to the
+	// address of the pod on the storage network
+	StorageIPScript = "/usr/local/bin/container-scripts/storage-ip.sh"
+)
+
+// GetStorageNetwork - Returns the namespaced name of the storage network as
+// Multus reports it in the network status of the pod, or an empty string if
+// no storage network is set or the service is not attached to it
+func GetStorageNetwork(namespace string, storageNetwork string, networkAttachments []string) string {
+	if storageNetwork == "" || !slices.Contains(networkAttachments, storageNetwork) {
+		return ""
+	}
+	return fmt.Sprintf("%s/%s", namespace, storageNetwork)
+}
+
+// GetPodInfoVolume - Exposes the network status of the pod, which Multus only
+// adds once the pod is attached to its networks
+func GetPodInfoVolume() corev1.Volume {
+	return corev1.Volume{
+		Name: "pod-info",
+		VolumeSource: corev1.VolumeSource{
+			DownwardAPI: &corev1.DownwardAPIVolumeSource{
+				Items: []corev1.DownwardAPIVolumeFile{
+					{
+						Path: "network-status",
+						FieldRef: &corev1.ObjectFieldSelector{
+							FieldPath: fmt.Sprintf("metadata.annotations['%s']", networkv1.NetworkStatusAnnot),
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// GetPodInfoVolumeMount -
+func GetPodInfoVolumeMount() corev1.VolumeMount {
+	return corev1.VolumeMount{
+		Name:      "pod-info",
+		MountPath: PodInfoPath,
+		ReadOnly:  true,
+	}
+}
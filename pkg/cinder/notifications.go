@@ -0,0 +1,50 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cinder
+
+import (
+	"strings"
+
+	cinderv1beta1 "github.com/openstack-k8s-operators/cinder-operator/api/v1beta1"
+)
+
+const (
+	// NotificationsDisabledDriver - oslo.messaging driver that drops the notifications
+	NotificationsDisabledDriver = "noop"
+	// NotificationsDefaultDriver - oslo.messaging driver used when none is set
+	NotificationsDefaultDriver = "messagingv2"
+)
+
+// GetNotificationsConfig - Returns the template parameters of the
+// [oslo_messaging_notifications] section
+func GetNotificationsConfig(notifications cinderv1beta1.Notifications) map[string]interface{} {
+	if !notifications.Enabled {
+		return map[string]interface{}{
+			"Driver": NotificationsDisabledDriver,
+			"Topics": "",
+		}
+	}
+
+	driver := notifications.Driver
+	if driver == "" {
+		driver = NotificationsDefaultDriver
+	}
+
+	return map[string]interface{}{
+		"Driver": driver,
+		"Topics": strings.Join(notifications.Topics, ","),
+	}
+}
@@ -0,0 +1,82 @@
+package cinder
+
+import (
+	"strconv"
+
+	cinderv1beta1 "github.com/openstack-k8s-operators/cinder-operator/api/v1beta1"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+)
+
+// startupProfiles - startup probe timings for each probe profile. Slow
+// backends only need more time for the driver's do_setup to complete, so the
+// liveness and readiness probes are the same for all the profiles.
+var startupProfiles = map[cinderv1beta1.ProbeProfile]cinderv1beta1.ProbeOverride{
+	// 5 minutes
+	cinderv1beta1.ProbeProfileNetApp: {
+		PeriodSeconds:    ptr.To(int32(10)),
+		FailureThreshold: ptr.To(int32(30)),
+	},
+	// 15 minutes
+	cinderv1beta1.ProbeProfilePowerMax: {
+		PeriodSeconds:    ptr.To(int32(10)),
+		FailureThreshold: ptr.To(int32(90)),
+	},
+	// 10 minutes
+	cinderv1beta1.ProbeProfileSlow: {
+		PeriodSeconds:    ptr.To(int32(10)),
+		FailureThreshold: ptr.To(int32(60)),
+	},
+}
+
+// ApplyProbeOverrides - Updates the probes built by the StatefulSet functions
+// with the timings from the profile and the user overrides. Any of the probes
+// can be nil if the service doesn't use it.
+func ApplyProbeOverrides(
+	overrides *cinderv1beta1.ProbeOverrides,
+	startup *corev1.Probe,
+	liveness *corev1.Probe,
+	readiness *corev1.Probe,
+) {
+	if overrides == nil {
+		return
+	}
+
+	if profile, ok := startupProfiles[overrides.Profile]; ok {
+		applyProbeOverride(startup, &profile)
+	}
+	applyProbeOverride(startup, overrides.Startup)
+	applyProbeOverride(liveness, overrides.Liveness)
+	applyProbeOverride(readiness, overrides.Readiness)
+}
+
+func applyProbeOverride(probe *corev1.Probe, override *cinderv1beta1.ProbeOverride) {
+	if probe == nil || override == nil {
+		return
+	}
+	if override.InitialDelaySeconds != nil {
+		probe.InitialDelaySeconds = *override.InitialDelaySeconds
+	}
+	if override.PeriodSeconds != nil {
+		probe.PeriodSeconds = *override.PeriodSeconds
+	}
+	if override.TimeoutSeconds != nil {
+		probe.TimeoutSeconds = *override.TimeoutSeconds
+	}
+	if override.FailureThreshold != nil {
+		probe.FailureThreshold = *override.FailureThreshold
+	}
+}
+
+// GetHealthcheckEnvVars - Returns the environment of the healthcheck sidecar,
+// which needs the timeout of the readiness probe to answer the /messaging
+// requests before the kubelet gives up on them.
+func GetHealthcheckEnvVars(readiness *corev1.Probe) []corev1.EnvVar {
+	return []corev1.EnvVar{
+		{
+			Name:  HealthcheckTimeoutEnvVar,
+			Value: strconv.Itoa(int(readiness.TimeoutSeconds)),
+		},
+	}
+}
@@ -0,0 +1,71 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cinder
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/openstack-k8s-operators/lib-common/modules/common/tls"
+)
+
+// GetDatabaseConnection - Returns the SQLAlchemy URL of the cinder database.
+// When the database has TLS the CA bundle is passed as a query parameter so
+// the connection is always encrypted, whatever the my.cnf contents are.
+func GetDatabaseConnection(user string, password string, hostname string, tlsEnabled bool) string {
+	connection := fmt.Sprintf("mysql+pymysql://%s:%s@%s/%s?read_default_file=/etc/my.cnf",
+		user, password, hostname, DatabaseName)
+	if tlsEnabled {
+		connection += "&ssl_ca=" + tls.DownstreamTLSCABundlePath
+	}
+	return connection
+}
+
+// IsTransportURLTLS - Returns whether a RabbitMQ transport URL connects to
+// the messaging bus over TLS, which the infra-operator flags with the ssl
+// query parameter
+func IsTransportURLTLS(transportURL string) bool {
+	u, err := url.Parse(transportURL)
+	if err != nil {
+		return false
+	}
+	enabled, err := strconv.ParseBool(u.Query().Get("ssl"))
+	return err == nil && enabled
+}
+
+// GetMessagingTLSConfig - Returns the template parameters of the TLS options
+// of the [oslo_messaging_rabbit] section, or nil if TLS isn't used
+func GetMessagingTLSConfig(tlsEnabled bool) map[string]interface{} {
+	if !tlsEnabled {
+		return nil
+	}
+	return map[string]interface{}{
+		"CaFile": tls.DownstreamTLSCABundlePath,
+	}
+}
+
+// GetClientCertConfig - Returns the template parameters of the client
+// certificate presented to Nova and Glance, or nil if none is used
+func GetClientCertConfig(secretName string) map[string]interface{} {
+	if secretName == "" {
+		return nil
+	}
+	return map[string]interface{}{
+		"CertFile": ClientCertPath + "/" + tls.CertKey,
+		"KeyFile":  ClientCertPath + "/" + tls.PrivateKey,
+	}
+}
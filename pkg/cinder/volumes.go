@@ -4,6 +4,7 @@ import (
 	cinderv1beta1 "github.com/openstack-k8s-operators/cinder-operator/api/v1beta1"
 	"github.com/openstack-k8s-operators/lib-common/modules/storage"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
 )
 
 // GetVolumes -
@@ -241,3 +242,27 @@ func GetVolumeMounts(storageSvc bool, extraVol []cinderv1beta1.CinderExtraVolMou
 	}
 	return res
 }
+
+// GetClientCertVolume - Volume with the client certificate presented to Nova
+// and Glance
+func GetClientCertVolume(secretName string) corev1.Volume {
+	return corev1.Volume{
+		Name: "client-certs",
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName:  secretName,
+				DefaultMode: ptr.To[int32](0400),
+			},
+		},
+	}
+}
+
+// GetClientCertVolumeMount - Mounts the client certificate where kolla copies
+// it from
+func GetClientCertVolumeMount() corev1.VolumeMount {
+	return corev1.VolumeMount{
+		Name:      "client-certs",
+		MountPath: ClientCertMountPath,
+		ReadOnly:  true,
+	}
+}
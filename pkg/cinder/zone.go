@@ -0,0 +1,144 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cinder
+
+import (
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+)
+
+// nodeSelectorOperators - label selector operator of each node selector
+// operator
+var nodeSelectorOperators = map[corev1.NodeSelectorOperator]selection.Operator{
+	corev1.NodeSelectorOpIn:           selection.In,
+	corev1.NodeSelectorOpNotIn:        selection.NotIn,
+	corev1.NodeSelectorOpExists:       selection.Exists,
+	corev1.NodeSelectorOpDoesNotExist: selection.DoesNotExist,
+	corev1.NodeSelectorOpGt:           selection.GreaterThan,
+	corev1.NodeSelectorOpLt:           selection.LessThan,
+}
+
+// GetSchedulableNodes - Returns the nodes a pod with the given node selector
+// and affinity can be scheduled on, looking only at their labels and name
+func GetSchedulableNodes(
+	nodes []corev1.Node,
+	nodeSelector *map[string]string,
+	affinity *corev1.Affinity,
+) ([]corev1.Node, error) {
+	selector := labels.Everything()
+	if nodeSelector != nil {
+		selector = labels.SelectorFromSet(*nodeSelector)
+	}
+
+	var terms []corev1.NodeSelectorTerm
+	if affinity != nil && affinity.NodeAffinity != nil &&
+		affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution != nil {
+		terms = affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+	}
+
+	schedulable := []corev1.Node{}
+	for _, node := range nodes {
+		if !selector.Matches(labels.Set(node.Labels)) {
+			continue
+		}
+		matches, err := matchNodeSelectorTerms(node, terms)
+		if err != nil {
+			return nil, err
+		}
+		if matches {
+			schedulable = append(schedulable, node)
+		}
+	}
+	return schedulable, nil
+}
+
+// matchNodeSelectorTerms - Returns whether the node matches any of the terms
+func matchNodeSelectorTerms(node corev1.Node, terms []corev1.NodeSelectorTerm) (bool, error) {
+	if len(terms) == 0 {
+		return true, nil
+	}
+	for _, term := range terms {
+		matches, err := matchNodeSelectorTerm(node, term)
+		if err != nil {
+			return false, err
+		}
+		if matches {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// matchNodeSelectorTerm - Returns whether the node matches all the
+// requirements of the term
+func matchNodeSelectorTerm(node corev1.Node, term corev1.NodeSelectorTerm) (bool, error) {
+	// An empty term matches no objects
+	if len(term.MatchExpressions) == 0 && len(term.MatchFields) == 0 {
+		return false, nil
+	}
+	labelSelector, err := nodeSelectorRequirementsAsSelector(term.MatchExpressions)
+	if err != nil {
+		return false, err
+	}
+	if !labelSelector.Matches(labels.Set(node.Labels)) {
+		return false, nil
+	}
+	// metadata.name is the only supported field
+	fieldSelector, err := nodeSelectorRequirementsAsSelector(term.MatchFields)
+	if err != nil {
+		return false, err
+	}
+	return fieldSelector.Matches(labels.Set{"metadata.name": node.Name}), nil
+}
+
+// nodeSelectorRequirementsAsSelector - Converts node selector requirements to
+// a label selector
+func nodeSelectorRequirementsAsSelector(reqs []corev1.NodeSelectorRequirement) (labels.Selector, error) {
+	selector := labels.NewSelector()
+	for _, req := range reqs {
+		op, ok := nodeSelectorOperators[req.Operator]
+		if !ok {
+			return nil, fmt.Errorf("invalid node selector operator %q", req.Operator)
+		}
+		r, err := labels.NewRequirement(req.Key, op, req.Values)
+		if err != nil {
+			return nil, err
+		}
+		selector = selector.Add(*r)
+	}
+	return selector, nil
+}
+
+// GetNodeZones - Returns the sorted availability zones of the nodes, from
+// their topology.kubernetes.io/zone label
+func GetNodeZones(nodes []corev1.Node) []string {
+	zones := []string{}
+	found := map[string]bool{}
+	for _, node := range nodes {
+		zone := node.Labels[corev1.LabelTopologyZone]
+		if zone == "" || found[zone] {
+			continue
+		}
+		found[zone] = true
+		zones = append(zones, zone)
+	}
+	sort.Strings(zones)
+	return zones
+}
@@ -0,0 +1,60 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cinderapi
+
+import (
+	"strings"
+
+	cinderv1beta1 "github.com/openstack-k8s-operators/cinder-operator/api/v1beta1"
+)
+
+// GetAuditConfig - Returns the template parameters of the audit middleware,
+// or nil if the API calls are not audited
+func GetAuditConfig(instance *cinderv1beta1.CinderAPI) map[string]interface{} {
+	audit := instance.Spec.Audit
+	if audit == nil {
+		return nil
+	}
+
+	logFile := ""
+	if audit.Output == cinderv1beta1.AuditOutputFile {
+		logFile = AuditLogFile
+	}
+
+	return map[string]interface{}{
+		"MapFile":       configDir + AuditMapFileName,
+		"LoggingFile":   configDir + AuditLoggingFileName,
+		"LogFile":       logFile,
+		"IgnoreReqList": strings.Join(audit.IgnoreReqList, ", "),
+	}
+}
+
+// GetAuditTemplates - Returns the audit files to add to the config-data
+// secret of the service
+func GetAuditTemplates(instance *cinderv1beta1.CinderAPI) map[string]string {
+	audit := instance.Spec.Audit
+	if audit == nil {
+		return nil
+	}
+
+	templates := map[string]string{
+		AuditMapFileName: "/cinderapi/audit/" + AuditMapFileName,
+	}
+	if audit.Output == cinderv1beta1.AuditOutputFile {
+		templates[AuditLoggingFileName] = "/cinderapi/audit/" + AuditLoggingFileName
+	}
+	return templates
+}
@@ -21,4 +21,24 @@ const (
 
 	//LogFile -
 	LogFile = "/var/log/cinder/cinder-api.log"
+
+	// AuditLogFile - file the audit records are written to when their output is file
+	AuditLogFile = "/var/log/cinder/cinder-api-audit.log"
+
+	// AuditMapFileName - map of the API paths to CADF actions and targets
+	AuditMapFileName = "api-audit-map.ini"
+	// AuditLoggingFileName - logging config that sends the audit records to AuditLogFile
+	AuditLoggingFileName = "api-logging.ini"
+
+	// PasteFileName - api-paste.ini with the audit and rate limiting filters
+	// in the pipeline
+	PasteFileName = "api-paste.ini"
+
+	// PolicyFileName - policy.yaml with the rules of the policy field
+	PolicyFileName = "policy.yaml"
+	// PolicyFile - where PolicyFileName is available to the service
+	PolicyFile = configDir + PolicyFileName
+
+	// configDir - where the config-data-custom secret is mounted
+	configDir = "/etc/cinder/cinder.conf.d/"
 )
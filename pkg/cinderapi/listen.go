@@ -0,0 +1,116 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cinderapi
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/openstack-k8s-operators/cinder-operator/pkg/cinder"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/service"
+)
+
+const (
+	// EndpointAddressesScript - script that starts httpd with the addresses
+	// the vhosts of the endpoints listen on in the environment
+	EndpointAddressesScript = "/usr/local/bin/container-scripts/endpoint-addresses.sh"
+
+	// InternalTargetPort - port of the pod the internal endpoint is served on
+	// when the endpoints are on their own networks, so the public Service,
+	// which targets the public port, doesn't reach it
+	InternalTargetPort int32 = 8777
+)
+
+// VHostListen - addresses the vhost of an endpoint serves it on, and the
+// ports it opens with Listen directives. Each port is only opened by the
+// first vhost that uses it, since httpd can't listen twice on the same one.
+type VHostListen struct {
+	Addresses []string
+	Listen    []string
+}
+
+// GetTargetPort - Returns the port of the pod the Service of an endpoint
+// targets
+func GetTargetPort(endpt service.Endpoint, endpointNetworks map[service.Endpoint]string) int32 {
+	if endpt == service.EndpointInternal {
+		if len(endpointNetworks) > 0 {
+			return InternalTargetPort
+		}
+		return cinder.CinderInternalPort
+	}
+	return cinder.CinderPublicPort
+}
+
+// GetPodPorts - Returns the ports EndpointAddressesScript sets the addresses
+// of the pod for, which are the target ports of the Services
+func GetPodPorts(endpointNetworks map[service.Endpoint]string) []string {
+	ports := []string{}
+	for _, endpt := range []service.Endpoint{service.EndpointInternal, service.EndpointPublic} {
+		port := fmt.Sprint(GetTargetPort(endpt, endpointNetworks))
+		if !slices.Contains(ports, port) {
+			ports = append(ports, port)
+		}
+	}
+	return ports
+}
+
+// GetVHostListen - Returns where the vhost of each endpoint listens. All the
+// endpoints share the wildcard address unless some of them are served on their
+// own network. Then each vhost listens on the addresses of the pod, one per IP
+// family, with the target port of its Service, where the Services and the
+// probes reach it, and on the address of its network, if it has one.
+func GetVHostListen(endpointNetworks map[service.Endpoint]string) map[service.Endpoint]VHostListen {
+	ports := map[service.Endpoint]int32{
+		service.EndpointInternal: cinder.CinderInternalPort,
+		service.EndpointPublic:   cinder.CinderPublicPort,
+	}
+
+	vhosts := map[service.Endpoint]VHostListen{}
+	opened := map[int32]bool{}
+	for _, endpt := range []service.Endpoint{service.EndpointInternal, service.EndpointPublic} {
+		port := ports[endpt]
+		targetPort := GetTargetPort(endpt, endpointNetworks)
+		listen := VHostListen{}
+		listenPorts := []int32{targetPort}
+		if len(endpointNetworks) == 0 {
+			listen.Addresses = []string{fmt.Sprintf("*:%d", port)}
+		} else {
+			listen.Addresses = []string{fmt.Sprintf("${POD_ADDRESSES_%d}", targetPort)}
+			if _, ok := endpointNetworks[endpt]; ok {
+				listen.Addresses = append(listen.Addresses,
+					fmt.Sprintf("${%s_ADDRESS}:%d", strings.ToUpper(endpt.String()), port))
+				listenPorts = append(listenPorts, port)
+			}
+		}
+		// httpd listens on the ports on all the addresses, the vhosts select
+		// the connections by the address they reach
+		for _, p := range listenPorts {
+			if !opened[p] {
+				listen.Listen = append(listen.Listen, fmt.Sprint(p))
+				opened[p] = true
+			}
+		}
+		vhosts[endpt] = listen
+	}
+	return vhosts
+}
+
+// GetEndpointNetworkEnvVar - Returns the environment variable that tells
+// EndpointAddressesScript the network an endpoint is served on
+func GetEndpointNetworkEnvVar(endpt service.Endpoint) string {
+	return strings.ToUpper(endpt.String()) + "_NETWORK"
+}
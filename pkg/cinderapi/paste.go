@@ -0,0 +1,67 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cinderapi
+
+import (
+	"fmt"
+	"strings"
+
+	cinderv1beta1 "github.com/openstack-k8s-operators/cinder-operator/api/v1beta1"
+)
+
+// GetPasteConfig - Returns the template parameters of the api-paste.ini
+// generated by the operator, or nil if the stock one can be used
+func GetPasteConfig(instance *cinderv1beta1.CinderAPI) map[string]interface{} {
+	rateLimits := GetRateLimits(instance)
+	if instance.Spec.Audit == nil && rateLimits == "" {
+		return nil
+	}
+
+	return map[string]interface{}{
+		"File":       configDir + PasteFileName,
+		"RateLimits": rateLimits,
+	}
+}
+
+// GetRateLimits - Returns the limits of the cinder rate limiting middleware
+// in its "(verb, uri, regex, value, unit);..." format
+func GetRateLimits(instance *cinderv1beta1.CinderAPI) string {
+	if instance.Spec.RateLimit == nil {
+		return ""
+	}
+
+	limits := []string{}
+	for _, limit := range instance.Spec.RateLimit.Limits {
+		limits = append(limits, fmt.Sprintf("(%s, \"*\", %s, %d, %s)",
+			limit.Verb, limit.Regex, limit.Value, limit.Unit))
+	}
+	return strings.Join(limits, ";")
+}
+
+// GetConfigTemplates - Returns the extra files to add to the config-data
+// secret of the service
+func GetConfigTemplates(instance *cinderv1beta1.CinderAPI) map[string]string {
+	templates := GetAuditTemplates(instance)
+	if GetPasteConfig(instance) == nil {
+		return templates
+	}
+
+	if templates == nil {
+		templates = map[string]string{}
+	}
+	templates[PasteFileName] = "/cinderapi/paste/" + PasteFileName
+	return templates
+}
@@ -16,6 +16,10 @@ limitations under the License.
 package cinderapi
 
 import (
+	"fmt"
+	"maps"
+	"strings"
+
 	cinderv1beta1 "github.com/openstack-k8s-operators/cinder-operator/api/v1beta1"
 	cinder "github.com/openstack-k8s-operators/cinder-operator/pkg/cinder"
 	topologyv1 "github.com/openstack-k8s-operators/infra-operator/apis/topology/v1beta1"
@@ -27,6 +31,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
 )
 
 const (
@@ -41,18 +46,23 @@ func StatefulSet(
 	labels map[string]string,
 	annotations map[string]string,
 	topology *topologyv1.Topology,
+	containerImage string,
 ) (*appsv1.StatefulSet, error) {
 	runAsUser := int64(0)
 	cinderUser := int64(cinderv1beta1.CinderUserID)
 
+	// uWSGI doesn't need root privileges, so all the containers can run as
+	// the cinder user
+	if instance.Spec.UsesUWSGI() {
+		runAsUser = cinderUser
+	}
+
 	livenessProbe := &corev1.Probe{
-		// TODO might need tuning
 		TimeoutSeconds:      5,
 		PeriodSeconds:       3,
 		InitialDelaySeconds: 5,
 	}
 	readinessProbe := &corev1.Probe{
-		// TODO might need tuning
 		TimeoutSeconds:      5,
 		PeriodSeconds:       5,
 		InitialDelaySeconds: 5,
@@ -67,8 +77,14 @@ func StatefulSet(
 		Port: intstr.IntOrString{Type: intstr.Int, IntVal: int32(cinder.CinderPublicPort)},
 	}
 	readinessProbe.HTTPGet = livenessProbe.HTTPGet
+	cinder.ApplyProbeOverrides(instance.Spec.Probes, nil, livenessProbe, readinessProbe)
 
-	if instance.Spec.TLS.API.Enabled(service.EndpointPublic) {
+	// uWSGI terminates TLS for both endpoints on the same socket
+	tlsEnabled := instance.Spec.TLS.API.Enabled(service.EndpointPublic)
+	if instance.Spec.UsesUWSGI() {
+		tlsEnabled = tlsEnabled || instance.Spec.TLS.API.Enabled(service.EndpointInternal)
+	}
+	if tlsEnabled {
 		livenessProbe.HTTPGet.Scheme = corev1.URISchemeHTTPS
 		readinessProbe.HTTPGet.Scheme = corev1.URISchemeHTTPS
 	}
@@ -78,7 +94,7 @@ func StatefulSet(
 		cinder.GetOwningCinderName(instance),
 		instance.Name,
 		instance.Spec.ExtraMounts)
-	volumeMounts := GetVolumeMounts(instance.Spec.ExtraMounts)
+	volumeMounts := GetVolumeMounts(instance.Spec.ExtraMounts, instance.Spec.APIServer)
 
 	// add CA cert if defined
 	if instance.Spec.TLS.CaBundleSecretName != "" {
@@ -86,6 +102,7 @@ func StatefulSet(
 		volumeMounts = append(volumeMounts, instance.Spec.TLS.CreateVolumeMounts(nil)...)
 	}
 
+	certsVolumeMounts := []corev1.VolumeMount{}
 	for _, endpt := range []service.Endpoint{service.EndpointInternal, service.EndpointPublic} {
 		if instance.Spec.TLS.API.Enabled(endpt) {
 			var tlsEndptCfg tls.GenericService
@@ -101,14 +118,44 @@ func StatefulSet(
 				return nil, err
 			}
 			volumes = append(volumes, svc.CreateVolume(endpt.String()))
-			volumeMounts = append(volumeMounts, svc.CreateVolumeMounts(endpt.String())...)
+			if instance.Spec.UsesUWSGI() {
+				volumeMounts = append(volumeMounts, svc.CreateVolumeMounts(endpt.String())...)
+			} else {
+				certsVolumeMounts = append(certsVolumeMounts, GetCertsVolumeMount(endpt))
+			}
 		}
 	}
+	if clientAuth := instance.Spec.InternalClientAuth; clientAuth != nil {
+		volumes = append(volumes, GetClientCAVolume(clientAuth.CaBundleSecretName))
+		certsVolumeMounts = append(certsVolumeMounts, GetClientCAVolumeMount())
+		if clientAuth.ClientCertSecretName != "" {
+			volumes = append(volumes, cinder.GetClientCertVolume(clientAuth.ClientCertSecretName))
+			volumeMounts = append(volumeMounts, cinder.GetClientCertVolumeMount())
+		}
+	}
+	volumeMounts = append(volumeMounts, certsVolumeMounts...)
 
 	envVars := map[string]env.Setter{}
 	envVars["KOLLA_CONFIG_STRATEGY"] = env.SetValue("COPY_ALWAYS")
 	envVars["CONFIG_HASH"] = env.SetValue(configHash)
 
+	// httpd listens on the addresses of the pod on the networks of the
+	// endpoints, which are only known once the pod is attached to them
+	serviceEnvVars := maps.Clone(envVars)
+	serviceVolumeMounts := volumeMounts
+	if len(instance.Spec.EndpointNetworks) > 0 {
+		args = []string{"-c", EndpointAddressesScript + " " + ServiceCommand}
+		serviceEnvVars["POD_IPS"] = env.DownwardAPI("status.podIPs")
+		serviceEnvVars["POD_PORTS"] = env.SetValue(strings.Join(GetPodPorts(instance.Spec.EndpointNetworks), " "))
+		for endpt, network := range instance.Spec.EndpointNetworks {
+			serviceEnvVars[GetEndpointNetworkEnvVar(endpt)] = env.SetValue(
+				fmt.Sprintf("%s/%s", instance.Namespace, network))
+		}
+		volumes = append(volumes, cinder.GetPodInfoVolume())
+		serviceVolumeMounts = append(
+			append([]corev1.VolumeMount{}, volumeMounts...), cinder.GetPodInfoVolumeMount())
+	}
+
 	statefulset := &appsv1.StatefulSet{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      instance.Name,
@@ -143,7 +190,7 @@ func StatefulSet(
 								"-c",
 								"/usr/bin/tail -n+1 -F " + LogFile + " 2>/dev/null",
 							},
-							Image: instance.Spec.ContainerImage,
+							Image: containerImage,
 							SecurityContext: &corev1.SecurityContext{
 								RunAsUser: &runAsUser,
 							},
@@ -157,12 +204,12 @@ func StatefulSet(
 								"/bin/bash",
 							},
 							Args:  args,
-							Image: instance.Spec.ContainerImage,
+							Image: containerImage,
 							SecurityContext: &corev1.SecurityContext{
 								RunAsUser: &cinderUser,
 							},
-							Env:            env.MergeEnvs([]corev1.EnvVar{}, envVars),
-							VolumeMounts:   volumeMounts,
+							Env:            env.MergeEnvs([]corev1.EnvVar{}, serviceEnvVars),
+							VolumeMounts:   serviceVolumeMounts,
 							Resources:      instance.Spec.Resources,
 							ReadinessProbe: readinessProbe,
 							LivenessProbe:  livenessProbe,
@@ -174,6 +221,55 @@ func StatefulSet(
 		},
 	}
 
+	// gracefully reload httpd when the certificates are renewed, which needs
+	// the process namespace to be shared to signal it from the sidecar
+	if len(certsVolumeMounts) > 0 {
+		statefulset.Spec.Template.Spec.ShareProcessNamespace = ptr.To(true)
+		statefulset.Spec.Template.Spec.Containers = append(statefulset.Spec.Template.Spec.Containers, corev1.Container{
+			Name: instance.Name + "-cert-reload",
+			Command: []string{
+				"/usr/bin/dumb-init",
+			},
+			Args: []string{
+				"--single-child",
+				"--",
+				cinder.CertReloadScript,
+				cinder.APICertsPath,
+			},
+			Image: containerImage,
+			SecurityContext: &corev1.SecurityContext{
+				RunAsUser: &runAsUser,
+			},
+			VolumeMounts: append(certsVolumeMounts, GetScriptsVolumeMount()),
+			Resources:    instance.Spec.Resources,
+		})
+	}
+
+	// stream the audit records on their own container so they can be
+	// collected separately from the service logs
+	if instance.Spec.Audit != nil && instance.Spec.Audit.Output == cinderv1beta1.AuditOutputFile {
+		statefulset.Spec.Template.Spec.Containers = append(statefulset.Spec.Template.Spec.Containers, corev1.Container{
+			Name: instance.Name + "-audit",
+			Command: []string{
+				"/usr/bin/dumb-init",
+			},
+			Args: []string{
+				"--single-child",
+				"--",
+				"/bin/sh",
+				"-c",
+				"/usr/bin/tail -n+1 -F " + AuditLogFile + " 2>/dev/null",
+			},
+			Image: containerImage,
+			SecurityContext: &corev1.SecurityContext{
+				RunAsUser: &runAsUser,
+			},
+			Env:          env.MergeEnvs([]corev1.EnvVar{}, envVars),
+			VolumeMounts: []corev1.VolumeMount{GetLogVolumeMount()},
+			Resources:    instance.Spec.Resources,
+		})
+	}
+
 	if instance.Spec.NodeSelector != nil {
 		statefulset.Spec.Template.Spec.NodeSelector = *instance.Spec.NodeSelector
 	}
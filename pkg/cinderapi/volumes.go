@@ -3,7 +3,9 @@ package cinderapi
 import (
 	cinderv1beta1 "github.com/openstack-k8s-operators/cinder-operator/api/v1beta1"
 	"github.com/openstack-k8s-operators/cinder-operator/pkg/cinder"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/service"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
 )
 
 // GetVolumes -
@@ -32,7 +34,11 @@ func GetVolumes(parentName string, name string, extraVol []cinderv1beta1.CinderE
 }
 
 // GetVolumeMounts - Cinder API VolumeMounts
-func GetVolumeMounts(extraVol []cinderv1beta1.CinderExtraVolMounts) []corev1.VolumeMount {
+func GetVolumeMounts(extraVol []cinderv1beta1.CinderExtraVolMounts, apiServer cinderv1beta1.APIServerType) []corev1.VolumeMount {
+	configData := "cinder-api-config.json"
+	if apiServer == cinderv1beta1.APIServerUWSGI {
+		configData = "cinder-api-uwsgi-config.json"
+	}
 	volumeMounts := []corev1.VolumeMount{
 		{
 			Name:      "config-data-custom",
@@ -42,7 +48,7 @@ func GetVolumeMounts(extraVol []cinderv1beta1.CinderExtraVolMounts) []corev1.Vol
 		{
 			Name:      "config-data",
 			MountPath: "/var/lib/kolla/config_files/config.json",
-			SubPath:   "cinder-api-config.json",
+			SubPath:   configData,
 			ReadOnly:  true,
 		},
 		GetLogVolumeMount(),
@@ -59,3 +65,47 @@ func GetLogVolumeMount() corev1.VolumeMount {
 		ReadOnly:  false,
 	}
 }
+
+// GetCertsVolumeMount - Mounts the whole certificates secret of an endpoint,
+// without subPath, so the kubelet updates the files when they are renewed
+func GetCertsVolumeMount(endpt service.Endpoint) corev1.VolumeMount {
+	return corev1.VolumeMount{
+		Name:      endpt.String() + "-tls-certs",
+		MountPath: cinder.APICertsPath + "/" + endpt.String(),
+		ReadOnly:  true,
+	}
+}
+
+// GetClientCAVolume - Volume with the CA bundle used to verify the client
+// certificates presented to the internal endpoint
+func GetClientCAVolume(secretName string) corev1.Volume {
+	return corev1.Volume{
+		Name: "client-ca-bundle",
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName:  secretName,
+				DefaultMode: ptr.To[int32](0444),
+			},
+		},
+	}
+}
+
+// GetClientCAVolumeMount - Mounts the CA bundle of the client certificates
+// next to the certificates of the endpoints, so httpd is also reloaded when
+// it changes
+func GetClientCAVolumeMount() corev1.VolumeMount {
+	return corev1.VolumeMount{
+		Name:      "client-ca-bundle",
+		MountPath: cinder.APICertsPath + "/client-ca",
+		ReadOnly:  true,
+	}
+}
+
+// GetScriptsVolumeMount - Cinder API scripts VolumeMount
+func GetScriptsVolumeMount() corev1.VolumeMount {
+	return corev1.VolumeMount{
+		Name:      "scripts",
+		MountPath: "/usr/local/bin/container-scripts",
+		ReadOnly:  true,
+	}
+}
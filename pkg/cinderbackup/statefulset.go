@@ -39,6 +39,7 @@ func StatefulSet(
 	labels map[string]string,
 	annotations map[string]string,
 	topology *topologyv1.Topology,
+	containerImage string,
 ) *appsv1.StatefulSet {
 	trueVar := true
 	cinderUser := int64(cinderv1.CinderUserID)
@@ -46,7 +47,6 @@ func StatefulSet(
 
 	// TODO until we determine how to properly query for these
 	livenessProbe := &corev1.Probe{
-		// TODO might need tuning
 		TimeoutSeconds:      5,
 		PeriodSeconds:       3,
 		InitialDelaySeconds: 3,
@@ -59,6 +59,14 @@ func StatefulSet(
 		InitialDelaySeconds: 5,
 	}
 
+	// Liveness only depends on the DB heartbeat, but the service is not ready
+	// if it cannot receive RPC requests
+	readinessProbe := &corev1.Probe{
+		TimeoutSeconds:      5,
+		PeriodSeconds:       10,
+		InitialDelaySeconds: 5,
+	}
+
 	args := []string{"-c", ServiceCommand}
 	var probeCommand []string
 	// Use the HTTP probe now that we have a simple server running
@@ -66,6 +74,11 @@ func StatefulSet(
 		Port: intstr.FromInt(8080),
 	}
 	startupProbe.HTTPGet = livenessProbe.HTTPGet
+	readinessProbe.HTTPGet = &corev1.HTTPGetAction{
+		Path: cinder.HealthcheckMessagingPath,
+		Port: intstr.FromInt(8080),
+	}
+	cinder.ApplyProbeOverrides(instance.Spec.Probes, startupProbe, livenessProbe, readinessProbe)
 	probeCommand = []string{
 		"/usr/local/bin/container-scripts/healthcheck.py",
 		"backup",
@@ -96,6 +109,18 @@ func StatefulSet(
 		volumeMounts = append(volumeMounts, instance.Spec.TLS.CreateVolumeMounts(nil)...)
 	}
 
+	// Start the service with my_ip set to the address of the pod on the
+	// storage network, which is only known once the pod is attached to it
+	serviceVolumeMounts := volumeMounts
+	if storageNetwork := cinder.GetStorageNetwork(
+		instance.Namespace, instance.Spec.StorageNetwork, instance.Spec.NetworkAttachments); storageNetwork != "" {
+		args = []string{"-c", cinder.StorageIPScript + " " + ServiceCommand}
+		envVars["STORAGE_NETWORK"] = env.SetValue(storageNetwork)
+		volumes = append(volumes, cinder.GetPodInfoVolume())
+		serviceVolumeMounts = append(
+			append([]corev1.VolumeMount{}, volumeMounts...), cinder.GetPodInfoVolumeMount())
+	}
+
 	statefulset := &appsv1.StatefulSet{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      instance.Name,
@@ -125,21 +150,23 @@ func StatefulSet(
 								"/bin/bash",
 							},
 							Args:  args,
-							Image: instance.Spec.ContainerImage,
+							Image: containerImage,
 							SecurityContext: &corev1.SecurityContext{
 								RunAsUser:  &cinderUser,
 								Privileged: &trueVar,
 							},
-							Env:           env.MergeEnvs([]corev1.EnvVar{}, envVars),
-							VolumeMounts:  volumeMounts,
-							Resources:     instance.Spec.Resources,
-							LivenessProbe: livenessProbe,
-							StartupProbe:  startupProbe,
+							Env:            env.MergeEnvs([]corev1.EnvVar{}, envVars),
+							VolumeMounts:   serviceVolumeMounts,
+							Resources:      instance.Spec.Resources,
+							LivenessProbe:  livenessProbe,
+							StartupProbe:   startupProbe,
+							ReadinessProbe: readinessProbe,
 						},
 						{
 							Name:    "probe",
 							Command: probeCommand,
-							Image:   instance.Spec.ContainerImage,
+							Image:   containerImage,
+							Env:     cinder.GetHealthcheckEnvVars(readinessProbe),
 							SecurityContext: &corev1.SecurityContext{
 								RunAsUser:  &cinderUser,
 								RunAsGroup: &cinderGroup,
@@ -157,6 +184,10 @@ func StatefulSet(
 		statefulset.Spec.Template.Spec.NodeSelector = *instance.Spec.NodeSelector
 	}
 
+	if instance.Spec.FibreChannel != nil && instance.Spec.FibreChannel.Required {
+		cinder.RequireFibreChannelNodes(&statefulset.Spec.Template.Spec)
+	}
+
 	if topology != nil {
 		topology.ApplyTo(&statefulset.Spec.Template)
 	} else {
@@ -0,0 +1,75 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cinderscheduler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	cinder "github.com/openstack-k8s-operators/cinder-operator/pkg/cinder"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// poolsResponse - document returned by the healthcheck sidecar
+type poolsResponse struct {
+	Hosts []string `json:"hosts"`
+}
+
+// GetSchedulerHosts - Queries the healthcheck sidecar running in the pod with
+// the given IP for the volume back-ends the scheduler of the pod has received
+// the capabilities of
+func GetSchedulerHosts(ctx context.Context, podIP string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(5)*time.Second)
+	defer cancel()
+
+	url := fmt.Sprintf("http://%s%s", net.JoinHostPort(podIP, strconv.Itoa(cinder.HealthcheckPort)), PoolsPath)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s from %s", resp.Status, url)
+	}
+
+	pools := poolsResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(&pools); err != nil {
+		return nil, fmt.Errorf("invalid pools from %s: %w", url, err)
+	}
+	return pools.Hosts, nil
+}
+
+// GetCapabilitiesCondition - Returns the capabilities readiness gate
+// condition of a pod, or nil if it has not been set yet
+func GetCapabilitiesCondition(p *corev1.Pod) *corev1.PodCondition {
+	for idx := range p.Status.Conditions {
+		if p.Status.Conditions[idx].Type == CapabilitiesReadinessGate {
+			return &p.Status.Conditions[idx]
+		}
+	}
+	return nil
+}
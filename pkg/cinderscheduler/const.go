@@ -18,4 +18,12 @@ package cinderscheduler
 const (
 	// ComponentName -
 	ComponentName = "cinder-scheduler"
+
+	// PoolsPath - path of the healthcheck sidecar that reports the back-ends
+	// the scheduler has received the capabilities of
+	PoolsPath = "/pools"
+
+	// CapabilitiesReadinessGate - pod condition the operator sets once the
+	// scheduler has the capabilities of all the volume back-ends
+	CapabilitiesReadinessGate = "cinder.openstack.org/capabilities-ready"
 )
@@ -0,0 +1,57 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cinderscheduler
+
+import (
+	"strconv"
+	"strings"
+
+	cinderv1beta1 "github.com/openstack-k8s-operators/cinder-operator/api/v1beta1"
+)
+
+const (
+	// StochasticWeightHandler - weight handler that chooses the back-end
+	// randomly, weighted by the weighers
+	StochasticWeightHandler = "cinder.scheduler.weights.stochastic.stochasticHostWeightHandler"
+)
+
+// GetSchedulerPolicyConfig - Returns the template parameters of the scheduler
+// policy, or nil if the scheduler uses the default policy
+func GetSchedulerPolicyConfig(instance *cinderv1beta1.CinderScheduler) map[string]interface{} {
+	policy := instance.Spec.SchedulerPolicy
+	if policy == nil {
+		return nil
+	}
+
+	weightHandler := ""
+	if policy.Stochastic {
+		weightHandler = StochasticWeightHandler
+	}
+	driverInitWaitTime := ""
+	if policy.DriverInitWaitTime != nil {
+		driverInitWaitTime = strconv.Itoa(int(*policy.DriverInitWaitTime))
+	}
+
+	return map[string]interface{}{
+		"Filters":                           strings.Join(policy.Filters, ","),
+		"Weighers":                          strings.Join(policy.Weighers, ","),
+		"CapacityWeightMultiplier":          policy.CapacityWeightMultiplier,
+		"AllocatedCapacityWeightMultiplier": policy.AllocatedCapacityWeightMultiplier,
+		"VolumeNumberMultiplier":            policy.VolumeNumberMultiplier,
+		"WeightHandler":                     weightHandler,
+		"DriverInitWaitTime":                driverInitWaitTime,
+	}
+}
@@ -39,13 +39,13 @@ func StatefulSet(
 	labels map[string]string,
 	annotations map[string]string,
 	topology *topologyv1.Topology,
+	containerImage string,
 ) *appsv1.StatefulSet {
 	cinderUser := int64(cinderv1.CinderUserID)
 	cinderGroup := int64(cinderv1.CinderGroupID)
 
 	// TODO until we determine how to properly query for these
 	livenessProbe := &corev1.Probe{
-		// TODO might need tuning
 		TimeoutSeconds:      5,
 		PeriodSeconds:       3,
 		InitialDelaySeconds: 3,
@@ -58,12 +58,25 @@ func StatefulSet(
 		InitialDelaySeconds: 5,
 	}
 
+	// Liveness only depends on the DB heartbeat, but the service is not ready
+	// if it cannot receive RPC requests
+	readinessProbe := &corev1.Probe{
+		TimeoutSeconds:      5,
+		PeriodSeconds:       10,
+		InitialDelaySeconds: 5,
+	}
+
 	args := []string{"-c", ServiceCommand}
 	var probeCommand []string
 	livenessProbe.HTTPGet = &corev1.HTTPGetAction{
 		Port: intstr.FromInt(8080),
 	}
 	startupProbe.HTTPGet = livenessProbe.HTTPGet
+	readinessProbe.HTTPGet = &corev1.HTTPGetAction{
+		Path: cinder.HealthcheckMessagingPath,
+		Port: intstr.FromInt(8080),
+	}
+	cinder.ApplyProbeOverrides(instance.Spec.Probes, startupProbe, livenessProbe, readinessProbe)
 	probeCommand = []string{
 		"/usr/local/bin/container-scripts/healthcheck.py",
 		"scheduler",
@@ -111,20 +124,22 @@ func StatefulSet(
 								"/bin/bash",
 							},
 							Args:  args,
-							Image: instance.Spec.ContainerImage,
+							Image: containerImage,
 							SecurityContext: &corev1.SecurityContext{
 								RunAsUser: &cinderUser,
 							},
-							Env:           env.MergeEnvs([]corev1.EnvVar{}, envVars),
-							VolumeMounts:  volumeMounts,
-							Resources:     instance.Spec.Resources,
-							LivenessProbe: livenessProbe,
-							StartupProbe:  startupProbe,
+							Env:            env.MergeEnvs([]corev1.EnvVar{}, envVars),
+							VolumeMounts:   volumeMounts,
+							Resources:      instance.Spec.Resources,
+							LivenessProbe:  livenessProbe,
+							StartupProbe:   startupProbe,
+							ReadinessProbe: readinessProbe,
 						},
 						{
 							Name:    "probe",
 							Command: probeCommand,
-							Image:   instance.Spec.ContainerImage,
+							Image:   containerImage,
+							Env:     cinder.GetHealthcheckEnvVars(readinessProbe),
 							SecurityContext: &corev1.SecurityContext{
 								RunAsUser:  &cinderUser,
 								RunAsGroup: &cinderGroup,
@@ -138,6 +153,16 @@ func StatefulSet(
 		},
 	}
 
+	// The operator marks the pods ready once they have the capabilities of
+	// the back-ends, so the rolling update waits for each updated pod to have
+	// them before updating the next one. A pod that is not ready still takes
+	// requests from the message bus.
+	if instance.Spec.CapabilitiesWarmup {
+		statefulset.Spec.Template.Spec.ReadinessGates = []corev1.PodReadinessGate{
+			{ConditionType: CapabilitiesReadinessGate},
+		}
+	}
+
 	if instance.Spec.NodeSelector != nil {
 		statefulset.Spec.Template.Spec.NodeSelector = *instance.Spec.NodeSelector
 	}
@@ -0,0 +1,79 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cindervolume
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	cinderv1 "github.com/openstack-k8s-operators/cinder-operator/api/v1beta1"
+	cinder "github.com/openstack-k8s-operators/cinder-operator/pkg/cinder"
+)
+
+// backendsStatusResponse - document returned by the healthcheck sidecar
+type backendsStatusResponse struct {
+	Backends []cinderv1.CinderVolumeBackendStatus `json:"backends"`
+	Error    string                               `json:"error,omitempty"`
+}
+
+// GetBackendsStatus - Queries the healthcheck sidecar running in the pod with
+// the given IP for the state of the backends of the cinder-volume service
+func GetBackendsStatus(ctx context.Context, podIP string) ([]cinderv1.CinderVolumeBackendStatus, error) {
+	url := fmt.Sprintf("http://%s%s", net.JoinHostPort(podIP, strconv.Itoa(cinder.HealthcheckPort)), BackendsStatusPath)
+	return getBackendsStatus(ctx, url)
+}
+
+func getBackendsStatus(ctx context.Context, url string) ([]cinderv1.CinderVolumeBackendStatus, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(5)*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s from %s", resp.Status, url)
+	}
+
+	status := backendsStatusResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		return nil, fmt.Errorf("invalid backends status from %s: %w", url, err)
+	}
+
+	// Capabilities and pools come from the scheduler, so failing to get them
+	// is reported on every backend instead of failing the whole request
+	if status.Error != "" {
+		for idx := range status.Backends {
+			if status.Backends[idx].Error == "" {
+				status.Backends[idx].Error = status.Error
+			}
+		}
+	}
+
+	return status.Backends, nil
+}
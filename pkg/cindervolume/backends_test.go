@@ -0,0 +1,103 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cindervolume
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/onsi/gomega" //revive:disable:dot-imports
+)
+
+func TestGetBackendsStatus(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		body     string
+		backends int
+		errors   []string
+		err      string
+	}{
+		{
+			name:   "backends up",
+			status: http.StatusOK,
+			body: `{"backends": [
+				{"name": "ceph", "host": "hostgroup@ceph", "up": true,
+				 "lastHeartbeat": "2024-01-02T03:04:05Z",
+				 "capabilities": {"vendorName": "Open Source", "storageProtocol": "ceph"},
+				 "pools": [{"name": "ceph", "totalCapacityGB": "100", "freeCapacityGB": "50"}]},
+				{"name": "lvm", "host": "hostgroup@lvm", "up": false, "error": "Service not found"}]}`,
+			backends: 2,
+			errors:   []string{"", "Service not found"},
+		},
+		{
+			name:     "scheduler error reported on every backend",
+			status:   http.StatusOK,
+			body:     `{"error": "Failed to get the pools: timeout", "backends": [{"name": "ceph", "host": "hostgroup@ceph", "up": true}]}`,
+			backends: 1,
+			errors:   []string{"Failed to get the pools: timeout"},
+		},
+		{
+			name:   "not a volume service",
+			status: http.StatusNotFound,
+			body:   `Only available for volume services`,
+			err:    "unexpected status 404 Not Found",
+		},
+		{
+			name:   "invalid document",
+			status: http.StatusOK,
+			body:   `{"backends": `,
+			err:    "invalid backends status",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				g.Expect(r.URL.Path).To(Equal(BackendsStatusPath))
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			backends, err := getBackendsStatus(context.TODO(), server.URL+BackendsStatusPath)
+			if tt.err != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tt.err)))
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(backends).To(HaveLen(tt.backends))
+			for idx, backendErr := range tt.errors {
+				g.Expect(backends[idx].Error).To(Equal(backendErr))
+			}
+		})
+	}
+}
+
+func TestGetBackendsStatusUnreachable(t *testing.T) {
+	g := NewWithT(t)
+
+	server := httptest.NewServer(http.NotFoundHandler())
+	url := server.URL + BackendsStatusPath
+	server.Close()
+
+	_, err := getBackendsStatus(context.TODO(), url)
+	g.Expect(err).To(HaveOccurred())
+}
@@ -15,7 +15,18 @@ limitations under the License.
 
 package cindervolume
 
+import (
+	"time"
+)
+
 const (
 	// ComponentName -
 	ComponentName = "cinder-volume"
+
+	// BackendsStatusPath - path of the healthcheck sidecar that reports the
+	// state of the backends
+	BackendsStatusPath = "/backends"
+
+	// BackendsStatusInterval - how often the state of the backends is polled
+	BackendsStatusInterval = time.Duration(60) * time.Second
 )
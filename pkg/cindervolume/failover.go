@@ -0,0 +1,140 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cindervolume
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	cinderv1 "github.com/openstack-k8s-operators/cinder-operator/api/v1beta1"
+	cinder "github.com/openstack-k8s-operators/cinder-operator/pkg/cinder"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// FailoverHash - hash of the last failover job that completed
+	FailoverHash = "failover"
+
+	// FailoverCommand -
+	FailoverCommand = "/usr/local/bin/container-scripts/failover.py"
+)
+
+// GetReplicationDevices - Returns the value of the replication_device
+// options for the replication targets of the service, in the form
+// backend_id:<id>,<key>:<value>,...
+func GetReplicationDevices(instance *cinderv1.CinderVolume) []string {
+	devices := []string{}
+	if instance.Spec.Replication == nil {
+		return devices
+	}
+
+	for _, device := range instance.Spec.Replication.Devices {
+		keys := make([]string, 0, len(device.Options))
+		for key := range device.Options {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		options := []string{"backend_id:" + device.BackendID}
+		for _, key := range keys {
+			options = append(options, fmt.Sprintf("%s:%s", key, device.Options[key]))
+		}
+		devices = append(devices, strings.Join(options, ","))
+	}
+	return devices
+}
+
+// FailoverJob - Job that fails over the backends of the service to the
+// replication target in the Failover field, or fails them back to the primary
+func FailoverJob(
+	instance *cinderv1.CinderVolume,
+	labels map[string]string,
+	annotations map[string]string,
+	usesLVM bool,
+	containerImage string,
+) *batchv1.Job {
+	cinderUser := int64(cinderv1.CinderUserID)
+	cinderGroup := int64(cinderv1.CinderGroupID)
+
+	target := instance.Spec.FailoverTarget()
+	if target == "" {
+		target = cinderv1.FailoverPrimary
+	}
+
+	volumes := GetVolumes(
+		cinder.GetOwningCinderName(instance),
+		instance.Name,
+		instance.Spec.ExtraMounts,
+		instance.BackendName(),
+	)
+	volumeMounts := GetVolumeMounts(
+		instance.Spec.ExtraMounts,
+		usesLVM,
+		instance.BackendName(),
+	)
+
+	// Add the CA bundle
+	if instance.Spec.TLS.CaBundleSecretName != "" {
+		volumes = append(volumes, instance.Spec.TLS.CreateVolume())
+		volumeMounts = append(volumeMounts, instance.Spec.TLS.CreateVolumeMounts(nil)...)
+	}
+
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      instance.Name + "-failover",
+			Namespace: instance.Namespace,
+			Labels:    labels,
+		},
+		Spec: batchv1.JobSpec{
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: annotations,
+				},
+				Spec: corev1.PodSpec{
+					RestartPolicy:      corev1.RestartPolicyOnFailure,
+					ServiceAccountName: instance.Spec.ServiceAccount,
+					Containers: []corev1.Container{
+						{
+							Name: instance.Name + "-failover",
+							Command: []string{
+								FailoverCommand,
+								target,
+								"/etc/cinder/cinder.conf.d",
+							},
+							Image: containerImage,
+							SecurityContext: &corev1.SecurityContext{
+								RunAsUser:  &cinderUser,
+								RunAsGroup: &cinderGroup,
+							},
+							VolumeMounts: volumeMounts,
+						},
+					},
+					Volumes: volumes,
+				},
+			},
+		},
+	}
+
+	if instance.Spec.NodeSelector != nil {
+		job.Spec.Template.Spec.NodeSelector = *instance.Spec.NodeSelector
+	}
+
+	return job
+}
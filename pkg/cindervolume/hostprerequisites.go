@@ -0,0 +1,209 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cindervolume
+
+import (
+	"sort"
+	"strconv"
+
+	cinderv1 "github.com/openstack-k8s-operators/cinder-operator/api/v1beta1"
+	cinder "github.com/openstack-k8s-operators/cinder-operator/pkg/cinder"
+	topologyv1 "github.com/openstack-k8s-operators/infra-operator/apis/topology/v1beta1"
+	common "github.com/openstack-k8s-operators/lib-common/modules/common"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// HostPreflightComponentName - component of the pods that verify the host
+	// prerequisites
+	HostPreflightComponentName = ComponentName + "-host-preflight"
+
+	// HostPreflightCommand -
+	HostPreflightCommand = "/usr/local/bin/container-scripts/host-preflight.sh"
+
+	// HostPreflightInterval - seconds between verifications on a node that
+	// passed them
+	HostPreflightInterval = 300
+
+	// HostPreflightOKFile - file present while the verification passes
+	HostPreflightOKFile = "/tmp/host-preflight-ok"
+)
+
+// HostPreflightResults - results of the host prerequisites verification
+type HostPreflightResults struct {
+	// Failed - reasons of the failure for every node that failed
+	Failed map[string]string
+	// Pending - nodes that haven't completed the verification yet
+	Pending []string
+}
+
+// FailedNodes - Returns the sorted names of the nodes that failed
+func (r HostPreflightResults) FailedNodes() []string {
+	nodes := []string{}
+	for node := range r.Failed {
+		nodes = append(nodes, node)
+	}
+	sort.Strings(nodes)
+	return nodes
+}
+
+// GetHostPreflightName - Returns the name of the host preflight DaemonSet
+func GetHostPreflightName(instance *cinderv1.CinderVolume) string {
+	return instance.Name + "-host-preflight"
+}
+
+// GetHostPreflightLabels - Returns the labels of the host preflight pods,
+// which must not match the ones of the service pods
+func GetHostPreflightLabels(serviceLabels map[string]string) map[string]string {
+	preflightLabels := map[string]string{}
+	for k, v := range serviceLabels {
+		preflightLabels[k] = v
+	}
+	preflightLabels[common.ComponentSelector] = HostPreflightComponentName
+	return preflightLabels
+}
+
+// HostPreflightDaemonSet - DaemonSet that verifies the host prerequisites on
+// all the nodes the service can run on
+func HostPreflightDaemonSet(
+	instance *cinderv1.CinderVolume,
+	labels map[string]string,
+	topology *topologyv1.Topology,
+	containerImage string,
+) *appsv1.DaemonSet {
+	var scriptsVolumeDefaultMode int32 = 0755
+	trueVar := true
+	runAsUser := int64(0)
+
+	command := []string{HostPreflightCommand, strconv.Itoa(HostPreflightInterval)}
+	for _, prerequisite := range instance.Spec.HostPrerequisites {
+		command = append(command, string(prerequisite))
+	}
+
+	readinessProbe := &corev1.Probe{
+		ProbeHandler: corev1.ProbeHandler{
+			Exec: &corev1.ExecAction{
+				Command: []string{"test", "-f", HostPreflightOKFile},
+			},
+		},
+		PeriodSeconds: 5,
+	}
+
+	daemonset := &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      GetHostPreflightName(instance),
+			Namespace: instance.Namespace,
+			Labels:    labels,
+		},
+		Spec: appsv1.DaemonSetSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: labels,
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+				},
+				Spec: corev1.PodSpec{
+					ServiceAccountName: instance.Spec.ServiceAccount,
+					// The verification runs on the host using nsenter
+					HostPID: true,
+					Containers: []corev1.Container{
+						{
+							Name:    HostPreflightComponentName,
+							Command: command,
+							Image:   containerImage,
+							SecurityContext: &corev1.SecurityContext{
+								RunAsUser:  &runAsUser,
+								Privileged: &trueVar,
+							},
+							ReadinessProbe:           readinessProbe,
+							TerminationMessagePolicy: corev1.TerminationMessageReadFile,
+							VolumeMounts: []corev1.VolumeMount{
+								{
+									Name:      "scripts",
+									MountPath: "/usr/local/bin/container-scripts",
+									ReadOnly:  true,
+								},
+							},
+						},
+					},
+					Volumes: []corev1.Volume{
+						{
+							Name: "scripts",
+							VolumeSource: corev1.VolumeSource{
+								Secret: &corev1.SecretVolumeSource{
+									DefaultMode: &scriptsVolumeDefaultMode,
+									SecretName:  cinder.GetOwningCinderName(instance) + "-scripts",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	if instance.Spec.NodeSelector != nil {
+		daemonset.Spec.Template.Spec.NodeSelector = *instance.Spec.NodeSelector
+	}
+
+	// Only verify the nodes the service can be scheduled on. The spread
+	// constraints and pod affinities of the topology are about the placement
+	// of the service pods among them, so only its node affinity applies here.
+	if topology != nil && topology.Spec.Affinity != nil && topology.Spec.Affinity.NodeAffinity != nil {
+		daemonset.Spec.Template.Spec.Affinity = &corev1.Affinity{
+			NodeAffinity: topology.Spec.Affinity.NodeAffinity.DeepCopy(),
+		}
+	}
+
+	return daemonset
+}
+
+// GetHostPreflightResults - Returns the results of the verification from the
+// state of the host preflight pods. A node passed the verification while its
+// pod is ready, and failed it when the container terminated with an error.
+func GetHostPreflightResults(pods []corev1.Pod) HostPreflightResults {
+	results := HostPreflightResults{Failed: map[string]string{}}
+	for _, p := range pods {
+		if p.Spec.NodeName == "" || !p.DeletionTimestamp.IsZero() {
+			continue
+		}
+		var status *corev1.ContainerStatus
+		for i := range p.Status.ContainerStatuses {
+			if p.Status.ContainerStatuses[i].Name == HostPreflightComponentName {
+				status = &p.Status.ContainerStatuses[i]
+			}
+		}
+		switch {
+		case status == nil:
+			results.Pending = append(results.Pending, p.Spec.NodeName)
+		case status.Ready:
+			continue
+		case status.State.Terminated != nil && status.State.Terminated.ExitCode != 0:
+			results.Failed[p.Spec.NodeName] = status.State.Terminated.Message
+		case status.LastTerminationState.Terminated != nil && status.LastTerminationState.Terminated.ExitCode != 0:
+			// Restarted after a failure, it's failed until it passes again
+			results.Failed[p.Spec.NodeName] = status.LastTerminationState.Terminated.Message
+		default:
+			results.Pending = append(results.Pending, p.Spec.NodeName)
+		}
+	}
+	sort.Strings(results.Pending)
+	return results
+}
@@ -0,0 +1,188 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cindervolume
+
+import (
+	"strconv"
+
+	cinderv1 "github.com/openstack-k8s-operators/cinder-operator/api/v1beta1"
+	cinder "github.com/openstack-k8s-operators/cinder-operator/pkg/cinder"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// LVMInitHash - hash of the last LVM init job that completed
+	LVMInitHash = "lvminit"
+
+	// LVMInitCommand -
+	LVMInitCommand = "/usr/local/bin/container-scripts/lvm-init.sh"
+
+	// LVMDriver - cinder driver used by the LVM backends
+	LVMDriver = "cinder.volume.drivers.lvm.LVMVolumeDriver"
+)
+
+// GetLVMConfig - Returns the template parameters used to render the backend
+// section of an LVM backend
+func GetLVMConfig(instance *cinderv1.CinderVolume) map[string]interface{} {
+	lvm := instance.Spec.LVM
+	if lvm == nil {
+		return nil
+	}
+
+	targetProtocol := "iscsi"
+	if lvm.TargetHelper == cinderv1.LVMTargetHelperNVMET {
+		targetProtocol = "nvmet_tcp"
+	}
+
+	return map[string]interface{}{
+		"BackendName":     instance.BackendName(),
+		"Driver":          LVMDriver,
+		"VolumeGroup":     lvm.VolumeGroup,
+		"TargetHelper":    string(lvm.TargetHelper),
+		"TargetProtocol":  targetProtocol,
+		"TargetIPAddress": lvm.TargetIPAddress,
+	}
+}
+
+// GetLVMNodeSelector - Returns the node selector that pins the service to the
+// node that has the volume group, merged with the one in the spec
+func GetLVMNodeSelector(instance *cinderv1.CinderVolume) map[string]string {
+	nodeSelector := map[string]string{}
+	if instance.Spec.NodeSelector != nil {
+		for k, v := range *instance.Spec.NodeSelector {
+			nodeSelector[k] = v
+		}
+	}
+	nodeSelector[corev1.LabelHostname] = instance.Spec.LVM.NodeName
+	return nodeSelector
+}
+
+// LVMInitJob - Job that creates or validates the volume group of an LVM
+// backend on its node
+func LVMInitJob(
+	instance *cinderv1.CinderVolume,
+	labels map[string]string,
+	annotations map[string]string,
+	containerImage string,
+) *batchv1.Job {
+	var scriptsVolumeDefaultMode int32 = 0755
+	trueVar := true
+	runAsUser := int64(0)
+
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      instance.Name + "-lvm-init",
+			Namespace: instance.Namespace,
+			Labels:    labels,
+		},
+		Spec: batchv1.JobSpec{
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: annotations,
+				},
+				Spec: corev1.PodSpec{
+					RestartPolicy:      corev1.RestartPolicyOnFailure,
+					ServiceAccountName: instance.Spec.ServiceAccount,
+					// The volume group is created on the host using nsenter
+					HostPID:      true,
+					NodeSelector: GetLVMNodeSelector(instance),
+					Containers: []corev1.Container{
+						{
+							Name:    instance.Name + "-lvm-init",
+							Command: getLVMInitCommand(instance.Spec.LVM),
+							Image:   containerImage,
+							SecurityContext: &corev1.SecurityContext{
+								RunAsUser:  &runAsUser,
+								Privileged: &trueVar,
+							},
+							VolumeMounts: []corev1.VolumeMount{
+								{
+									Name:      "scripts",
+									MountPath: "/usr/local/bin/container-scripts",
+									ReadOnly:  true,
+								},
+							},
+						},
+					},
+					Volumes: []corev1.Volume{
+						{
+							Name: "scripts",
+							VolumeSource: corev1.VolumeSource{
+								Secret: &corev1.SecretVolumeSource{
+									DefaultMode: &scriptsVolumeDefaultMode,
+									SecretName:  cinder.GetOwningCinderName(instance) + "-scripts",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	return job
+}
+
+// LVMLoopInitContainer - Init container of the volume service that attaches
+// the loop device of the volume group again after a reboot of the node
+func LVMLoopInitContainer(instance *cinderv1.CinderVolume, containerImage string) corev1.Container {
+	trueVar := true
+	runAsUser := int64(0)
+
+	return corev1.Container{
+		Name:    "lvm-loop",
+		Command: getLVMInitCommand(instance.Spec.LVM),
+		Image:   containerImage,
+		SecurityContext: &corev1.SecurityContext{
+			RunAsUser:  &runAsUser,
+			Privileged: &trueVar,
+		},
+		VolumeMounts: []corev1.VolumeMount{
+			{
+				Name:      "scripts",
+				MountPath: "/usr/local/bin/container-scripts",
+				ReadOnly:  true,
+			},
+		},
+	}
+}
+
+// getLVMInitCommand - Returns the command that creates or validates the volume
+// group of an LVM backend
+func getLVMInitCommand(lvm *cinderv1.CinderVolumeLVM) []string {
+	loopFileSize := ""
+	if lvm.LoopFileSize != nil {
+		loopFileSize = strconv.FormatInt(lvm.LoopFileSize.Value(), 10)
+	}
+	return append([]string{LVMInitCommand, lvm.VolumeGroup, loopFileSize}, lvm.Devices...)
+}
+
+// UpdateLVMCapacity - Updates the capacity of the volume group in the status
+// with the one of the pool reported by the LVM backend
+func UpdateLVMCapacity(instance *cinderv1.CinderVolume) {
+	for _, backend := range instance.Status.Backends {
+		if backend.Name != instance.BackendName() || len(backend.Pools) == 0 {
+			continue
+		}
+		// The LVM driver reports the volume group as a single pool
+		instance.Status.LVM.TotalCapacityGB = backend.Pools[0].TotalCapacityGB
+		instance.Status.LVM.FreeCapacityGB = backend.Pools[0].FreeCapacityGB
+		return
+	}
+}
@@ -0,0 +1,102 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cindervolume
+
+import (
+	"path/filepath"
+	"strings"
+
+	cinderv1 "github.com/openstack-k8s-operators/cinder-operator/api/v1beta1"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// DriverPluginsVolumeName - volume shared by the plugin init containers
+	// and the service
+	DriverPluginsVolumeName = "driver-plugins"
+
+	// DriverPluginsPath - where the plugins are copied to
+	DriverPluginsPath = "/var/lib/cinder-plugins"
+
+	// DriverPluginDefaultPath - directory of the plugin image with the Python
+	// packages when the plugin doesn't set one
+	DriverPluginDefaultPath = "/plugin"
+)
+
+// GetDriverPluginsInitContainers - Returns the init containers that copy the
+// Python packages of the driver plugins into the shared volume
+func GetDriverPluginsInitContainers(instance *cinderv1.CinderVolume) []corev1.Container {
+	cinderUser := int64(cinderv1.CinderUserID)
+
+	var containers []corev1.Container
+	for _, plugin := range instance.Spec.DriverPlugins {
+		pluginPath := strings.TrimSuffix(plugin.Path, "/")
+		if pluginPath == "" {
+			pluginPath = DriverPluginDefaultPath
+		}
+		containers = append(containers, corev1.Container{
+			Name:  "plugin-" + plugin.Name,
+			Image: plugin.ContainerImage,
+			Command: []string{
+				"cp", "-a",
+				pluginPath + "/.",
+				filepath.Join(DriverPluginsPath, plugin.Name),
+			},
+			SecurityContext: &corev1.SecurityContext{
+				RunAsUser: &cinderUser,
+			},
+			VolumeMounts: []corev1.VolumeMount{
+				{
+					Name:      DriverPluginsVolumeName,
+					MountPath: DriverPluginsPath,
+				},
+			},
+		})
+	}
+	return containers
+}
+
+// GetDriverPluginsPythonPath - Returns the PYTHONPATH that makes the Python
+// packages of the driver plugins available to the service
+func GetDriverPluginsPythonPath(instance *cinderv1.CinderVolume) string {
+	paths := []string{}
+	for _, plugin := range instance.Spec.DriverPlugins {
+		paths = append(paths, filepath.Join(DriverPluginsPath, plugin.Name))
+	}
+	return strings.Join(paths, ":")
+}
+
+// GetDriverPluginsVolume - Returns the volume shared by the plugin init
+// containers and the service
+func GetDriverPluginsVolume() corev1.Volume {
+	return corev1.Volume{
+		Name: DriverPluginsVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{},
+		},
+	}
+}
+
+// GetDriverPluginsVolumeMount - Returns the read only mount of the plugins
+// for the service
+func GetDriverPluginsVolumeMount() corev1.VolumeMount {
+	return corev1.VolumeMount{
+		Name:      DriverPluginsVolumeName,
+		MountPath: DriverPluginsPath,
+		ReadOnly:  true,
+	}
+}
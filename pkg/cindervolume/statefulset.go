@@ -40,6 +40,8 @@ func StatefulSet(
 	annotations map[string]string,
 	usesLVM bool,
 	topology *topologyv1.Topology,
+	excludedNodes []string,
+	containerImage string,
 ) *appsv1.StatefulSet {
 	trueVar := true
 	cinderUser := int64(cinderv1.CinderUserID)
@@ -47,7 +49,6 @@ func StatefulSet(
 
 	// TODO until we determine how to properly query for these
 	livenessProbe := &corev1.Probe{
-		// TODO might need tuning
 		TimeoutSeconds:      5,
 		PeriodSeconds:       3,
 		InitialDelaySeconds: 3,
@@ -60,6 +61,14 @@ func StatefulSet(
 		InitialDelaySeconds: 5,
 	}
 
+	// Liveness only depends on the DB heartbeat, but the service is not ready
+	// if it cannot receive RPC requests
+	readinessProbe := &corev1.Probe{
+		TimeoutSeconds:      5,
+		PeriodSeconds:       10,
+		InitialDelaySeconds: 5,
+	}
+
 	args := []string{"-c", ServiceCommand}
 	var probeCommand []string
 	// Use the HTTP probe now that we have a simple server running
@@ -67,6 +76,11 @@ func StatefulSet(
 		Port: intstr.FromInt(8080),
 	}
 	startupProbe.HTTPGet = livenessProbe.HTTPGet
+	readinessProbe.HTTPGet = &corev1.HTTPGetAction{
+		Path: cinder.HealthcheckMessagingPath,
+		Port: intstr.FromInt(8080),
+	}
+	cinder.ApplyProbeOverrides(instance.Spec.Probes, startupProbe, livenessProbe, readinessProbe)
 	probeCommand = []string{
 		"/usr/local/bin/container-scripts/healthcheck.py",
 		"volume",
@@ -85,6 +99,11 @@ func StatefulSet(
 	envVars["MALLOC_MMAP_THRESHOLD_"] = env.SetValue("131072")
 	envVars["MALLOC_TRIM_THRESHOLD_"] = env.SetValue("262144")
 
+	// Make the packages of the driver plugins available to the service
+	if len(instance.Spec.DriverPlugins) > 0 {
+		envVars["PYTHONPATH"] = env.SetValue(GetDriverPluginsPythonPath(instance))
+	}
+
 	volumes := GetVolumes(
 		cinder.GetOwningCinderName(instance),
 		instance.Name,
@@ -103,6 +122,31 @@ func StatefulSet(
 		volumeMounts = append(volumeMounts, instance.Spec.TLS.CreateVolumeMounts(nil)...)
 	}
 
+	// Add the client certificate presented to Nova and Glance
+	if instance.Spec.ClientCertSecretName != "" {
+		volumes = append(volumes, cinder.GetClientCertVolume(instance.Spec.ClientCertSecretName))
+		volumeMounts = append(volumeMounts, cinder.GetClientCertVolumeMount())
+	}
+
+	// Only the service container uses the driver plugins
+	serviceVolumeMounts := volumeMounts
+	if len(instance.Spec.DriverPlugins) > 0 {
+		volumes = append(volumes, GetDriverPluginsVolume())
+		serviceVolumeMounts = append(
+			append([]corev1.VolumeMount{}, volumeMounts...), GetDriverPluginsVolumeMount())
+	}
+
+	// Start the service with my_ip set to the address of the pod on the
+	// storage network, which is only known once the pod is attached to it
+	if storageNetwork := cinder.GetStorageNetwork(
+		instance.Namespace, instance.Spec.StorageNetwork, instance.Spec.NetworkAttachments); storageNetwork != "" {
+		args = []string{"-c", cinder.StorageIPScript + " " + ServiceCommand}
+		envVars["STORAGE_NETWORK"] = env.SetValue(storageNetwork)
+		volumes = append(volumes, cinder.GetPodInfoVolume())
+		serviceVolumeMounts = append(
+			append([]corev1.VolumeMount{}, serviceVolumeMounts...), cinder.GetPodInfoVolumeMount())
+	}
+
 	statefulset := &appsv1.StatefulSet{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      instance.Name,
@@ -124,7 +168,8 @@ func StatefulSet(
 					// Some commands need to be run on the host using nsenter
 					// (eg: iscsi commands) so we need to share the PID
 					// namespace with the host.
-					HostPID: true,
+					HostPID:        true,
+					InitContainers: GetDriverPluginsInitContainers(instance),
 					Containers: []corev1.Container{
 						{
 							Name: ComponentName,
@@ -132,21 +177,23 @@ func StatefulSet(
 								"/bin/bash",
 							},
 							Args:  args,
-							Image: instance.Spec.ContainerImage,
+							Image: containerImage,
 							SecurityContext: &corev1.SecurityContext{
 								RunAsUser:  &cinderUser,
 								Privileged: &trueVar,
 							},
-							Env:           env.MergeEnvs([]corev1.EnvVar{}, envVars),
-							VolumeMounts:  volumeMounts,
-							Resources:     instance.Spec.Resources,
-							LivenessProbe: livenessProbe,
-							StartupProbe:  startupProbe,
+							Env:            env.MergeEnvs([]corev1.EnvVar{}, envVars),
+							VolumeMounts:   serviceVolumeMounts,
+							Resources:      instance.Spec.Resources,
+							LivenessProbe:  livenessProbe,
+							StartupProbe:   startupProbe,
+							ReadinessProbe: readinessProbe,
 						},
 						{
 							Name:    "probe",
 							Command: probeCommand,
-							Image:   instance.Spec.ContainerImage,
+							Image:   containerImage,
+							Env:     cinder.GetHealthcheckEnvVars(readinessProbe),
 							SecurityContext: &corev1.SecurityContext{
 								RunAsUser:  &cinderUser,
 								RunAsGroup: &cinderGroup,
@@ -160,10 +207,23 @@ func StatefulSet(
 		},
 	}
 
-	if instance.Spec.NodeSelector != nil {
+	if instance.Spec.LVM != nil {
+		// The volume group is only available on its node
+		statefulset.Spec.Template.Spec.NodeSelector = GetLVMNodeSelector(instance)
+		// The loop device of the volume group doesn't survive a reboot
+		if instance.Spec.LVM.LoopFileSize != nil {
+			statefulset.Spec.Template.Spec.InitContainers = append(
+				statefulset.Spec.Template.Spec.InitContainers,
+				LVMLoopInitContainer(instance, containerImage))
+		}
+	} else if instance.Spec.NodeSelector != nil {
 		statefulset.Spec.Template.Spec.NodeSelector = *instance.Spec.NodeSelector
 	}
 
+	if instance.Spec.FibreChannel != nil && instance.Spec.FibreChannel.Required {
+		cinder.RequireFibreChannelNodes(&statefulset.Spec.Template.Spec)
+	}
+
 	if topology != nil {
 		topology.ApplyTo(&statefulset.Spec.Template)
 	} else {
@@ -173,5 +233,8 @@ func StatefulSet(
 		statefulset.Spec.Template.Spec.Affinity = cinder.GetPodAffinity(ComponentName)
 	}
 
+	// Keep the service off the nodes that failed the host prerequisites
+	cinder.ExcludeNodes(&statefulset.Spec.Template.Spec, excludedNodes)
+
 	return statefulset
 }
@@ -0,0 +1,48 @@
+#!/bin/bash
+#
+# Copyright 2024 Red Hat Inc.
+#
+# Licensed under the Apache License, Version 2.0 (the "License"); you may
+# not use this file except in compliance with the License. You may obtain
+# a copy of the License at
+#
+#      http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+# WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+# License for the specific language governing permissions and limitations
+# under the License.
+
+# Gracefully reload httpd when the certificates of the API endpoints, or the CA
+# of the client certificates, change, so renewed certificates are used without
+# restarting the pod. The kubelet updates the files of the secret volumes in
+# place, and this runs in a sidecar that shares the process namespace of the pod
+# to be able to signal httpd.
+set -u
+
+CERTS_DIR=${1:-/var/lib/config-data/api-certs}
+INTERVAL=${2:-60}
+
+certs_hash() {
+    cat "${CERTS_DIR}"/*/* 2>/dev/null | md5sum
+}
+
+current=$(certs_hash)
+while true; do
+    sleep "${INTERVAL}"
+    new=$(certs_hash)
+    if [[ "${new}" == "${current}" ]]; then
+        continue
+    fi
+
+    # SIGUSR1 is the graceful restart of httpd: the parent process re-reads the
+    # configuration and the certificates, and the children finish their
+    # in-flight requests before being replaced.
+    echo "Certificates in ${CERTS_DIR} changed, gracefully reloading httpd"
+    if pkill -USR1 -o -x httpd; then
+        current=${new}
+    else
+        echo "Couldn't signal httpd, retrying in ${INTERVAL} seconds"
+    fi
+done
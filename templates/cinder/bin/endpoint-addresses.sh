@@ -0,0 +1,89 @@
+#!/bin/bash
+#
+# Copyright 2024 Red Hat Inc.
+#
+# Licensed under the Apache License, Version 2.0 (the "License"); you may
+# not use this file except in compliance with the License. You may obtain
+# a copy of the License at
+#
+#      http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+# WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+# License for the specific language governing permissions and limitations
+# under the License.
+
+# Run the given command with the addresses the httpd vhosts of the API
+# endpoints listen on:
+# - POD_ADDRESSES_<port>: the addresses of the pod in POD_IPS, one per IP
+#   family, with each of the ports in POD_PORTS. The Services and the probes
+#   reach the pod there.
+# - INTERNAL_ADDRESS and PUBLIC_ADDRESS: the address of the pod on the network
+#   in INTERNAL_NETWORK and PUBLIC_NETWORK respectively, when they are set.
+#
+# The addresses on the networks are read from the network status Multus adds to
+# the pod, exposed through the downward API. IPv6 addresses are enclosed in
+# brackets, as the Listen and VirtualHost directives expect.
+
+NETWORK_STATUS=${NETWORK_STATUS:-/var/lib/pod-info/network-status}
+TIMEOUT=${NETWORK_TIMEOUT:-60}
+
+get_ip() {
+    python3 - "$1" "${NETWORK_STATUS}" <<'PYEOF'
+import json
+import sys
+
+network, path = sys.argv[1:]
+try:
+    with open(path) as f:
+        networks = json.load(f)
+except (OSError, ValueError):
+    sys.exit(1)
+for net in networks:
+    if net.get('name') == network and net.get('ips'):
+        print(net['ips'][0])
+        sys.exit(0)
+sys.exit(1)
+PYEOF
+}
+
+to_address() {
+    if [[ "$1" == *:* ]]; then
+        echo "[$1]"
+    else
+        echo "$1"
+    fi
+}
+
+# The downward API joins the addresses of a dual-stack pod with commas
+for port in ${POD_PORTS}; do
+    addresses=""
+    for ip in ${POD_IPS//,/ }; do
+        addresses="${addresses:+${addresses} }$(to_address "${ip}"):${port}"
+    done
+    export POD_ADDRESSES_${port}="${addresses}"
+done
+
+for endpoint in INTERNAL PUBLIC; do
+    network_var=${endpoint}_NETWORK
+    network=${!network_var}
+    [ -z "${network}" ] && continue
+
+    # The kubelet refreshes the file once Multus has reported the networks of
+    # the pod, which may happen after the container starts
+    ip=""
+    for ((i = 0; i < TIMEOUT; i++)); do
+        ip=$(get_ip "${network}") && break
+        sleep 1
+    done
+    if [ -z "${ip}" ]; then
+        echo "The pod has no address on the ${network} network"
+        exit 1
+    fi
+
+    echo "Serving the ${endpoint,,} endpoint on ${ip} from the ${network} network"
+    export ${endpoint}_ADDRESS=$(to_address "${ip}")
+done
+
+exec "$@"
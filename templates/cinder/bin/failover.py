@@ -0,0 +1,122 @@
+#!/usr/bin/env python3
+#
+# Copyright 2022 Red Hat Inc.
+#
+# Licensed under the Apache License, Version 2.0 (the "License"); you may
+# not use this file except in compliance with the License. You may obtain
+# a copy of the License at
+#
+#      http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+# WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+# License for the specific language governing permissions and limitations
+# under the License.
+
+# Fail over the backends of a cinder-volume service to one of their replication
+# targets, or fail them back to the primary storage, and wait until the
+# operation completes.
+#
+# The failover is requested through cinder's volume API code, like the
+# failover-host REST API does, but accessing the DB and the message bus directly
+# using cinder's configuration options, so it doesn't depend on the Cinder-API
+# service or on keystone credentials.
+#
+# Requires the backend_id of the replication target as the first argument, or
+# "primary" to fail back, and optionally a second argument with the location of
+# the configuration directory (defaults to /etc/cinder/cinder.conf.d)
+
+import sys
+import time
+
+from oslo_config import cfg
+
+from cinder import context
+from cinder import objects
+from cinder.objects import fields
+from cinder import rpc
+from cinder.volume import api as volume_api
+from cinder.volume import configuration as vol_conf
+
+
+CONF = cfg.CONF
+BINARY = 'cinder-volume'
+PRIMARY = 'primary'
+# Backend id that cinder uses to fail back
+DEFAULT_BACKEND_ID = 'default'
+TIMEOUT = 600
+INTERVAL = 5
+
+
+def get_backends():
+    """Return the host and cluster of each enabled backend."""
+    backend_opts = [cfg.StrOpt('backend_host')]
+    backends = []
+    for backend in CONF.enabled_backends:
+        conf = vol_conf.BackendGroupConfiguration(backend_opts, backend)
+        host = "%s@%s" % (conf.backend_host or CONF.host, backend)
+        cluster = CONF.cluster and CONF.cluster.strip()
+        cluster = (cluster or None) and f'{cluster}@{backend}'
+        backends.append((host, cluster))
+    return backends
+
+
+def is_active(service, secondary_id):
+    if secondary_id == DEFAULT_BACKEND_ID:
+        return service.active_backend_id in (None, DEFAULT_BACKEND_ID)
+    return service.active_backend_id == secondary_id
+
+
+def failover(ctxt, secondary_id):
+    api = volume_api.API()
+    pending = []
+    for host, cluster in get_backends():
+        service = objects.Service.get_by_args(ctxt, host, BINARY)
+        if is_active(service, secondary_id):
+            print(f'{host} already running on {secondary_id}')
+            continue
+        print(f'Failing over {cluster or host} to {secondary_id}')
+        if cluster:
+            api.failover(ctxt, None, cluster, secondary_id)
+        else:
+            api.failover(ctxt, host, None, secondary_id)
+        pending.append(host)
+
+    deadline = time.time() + TIMEOUT
+    while pending:
+        if time.time() > deadline:
+            print(f'Timed out waiting for the failover of {pending}')
+            return 1
+        time.sleep(INTERVAL)
+        for host in list(pending):
+            service = objects.Service.get_by_args(ctxt, host, BINARY)
+            status = service.replication_status
+            if status == fields.ReplicationStatus.FAILOVER_ERROR:
+                print(f'Failover of {host} failed')
+                return 1
+            if (status != fields.ReplicationStatus.FAILING_OVER and
+                    is_active(service, secondary_id)):
+                print(f'{host} running on {secondary_id} ({status})')
+                pending.remove(host)
+    return 0
+
+
+if __name__ == "__main__":
+    if not 2 <= len(sys.argv) <= 3:
+        print('Failover requires the backend_id of the replication target '
+              f'or "{PRIMARY}" as argument, and optionally the location of '
+              'the config.d directory.')
+        sys.exit(1)
+    target = sys.argv[1]
+    secondary_id = DEFAULT_BACKEND_ID if target == PRIMARY else target
+
+    cfg_dir = (sys.argv[2] if len(sys.argv) == 3 else
+               '/etc/cinder/cinder.conf.d')
+
+    objects.register_all()
+    CONF.register_opt(cfg.StrOpt('cluster', default=None))
+    CONF(['--config-dir', cfg_dir], project='cinder')
+    rpc.init(CONF)
+
+    sys.exit(failover(context.get_admin_context(), secondary_id))
@@ -0,0 +1,38 @@
+#!/bin/bash
+#
+# Copyright 2024 Red Hat Inc.
+#
+# Licensed under the Apache License, Version 2.0 (the "License"); you may
+# not use this file except in compliance with the License. You may obtain
+# a copy of the License at
+#
+#      http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+# WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+# License for the specific language governing permissions and limitations
+# under the License.
+
+# Discover the WWPNs of the Fibre Channel HBAs of the node where this runs,
+# reading them from the host sysfs mounted in SYSFS.
+#
+# Usage: fc-discovery.sh
+#
+# The WWPNs are written, separated by commas, to the termination log so they
+# are reported in the status of the container.
+
+set -euo pipefail
+
+SYSFS=${SYSFS:-/host/sys}
+TERMINATION_LOG=/dev/termination-log
+
+WWPNS=()
+for port_name in "${SYSFS}"/class/fc_host/host*/port_name; do
+    [ -f "${port_name}" ] || continue
+    WWPNS+=("$(cat "${port_name}")")
+done
+
+echo "Found ${#WWPNS[@]} FC ports: ${WWPNS[*]}"
+MESSAGE=$(printf '%s,' "${WWPNS[@]}")
+echo -n "${MESSAGE%,}" > "${TERMINATION_LOG}"
@@ -17,9 +17,9 @@
 # Trivial HTTP server to check health of scheduler, backup and volume services.
 # Cinder-API hast its own health check endpoint and does not need this.
 #
-# The only check this server currently does is using the heartbeat in the
-# database service table, accessing the DB directly here using cinder's
-# configuration options.
+# The main check this server does is using the heartbeat in the database
+# service table, accessing the DB directly here using cinder's configuration
+# options.
 #
 # The benefit of accessing the DB directly is that it doesn't depend on the
 # Cinder-API service being up and we can also differentiate between the
@@ -30,11 +30,33 @@
 # recommended to use a different pod for each backend to avoid one backend
 # affecting others.
 #
+# A service that has lost its message bus connection still does the DB
+# heartbeats, so the /messaging path checks that the service's RPC server is
+# reachable by sending a message to its own topic, and returns a JSON document
+# with the result for each service. It returns 200 if all services replied and
+# 503 otherwise. The services are pinged in parallel and the whole check is
+# bounded by the timeout of the readiness probe, which the operator passes in
+# the HEALTHCHECK_TIMEOUT environment variable, so a pod with many backends
+# still replies before the kubelet gives up on the request.
+#
+# For volume services the /backends path returns a JSON document with the state
+# of each enabled backend (up/down, frozen, replication status, last heartbeat)
+# and the capabilities and pools that the scheduler has for it. The operator
+# polls this path to report the backends in the CinderVolume status.
+#
+# For scheduler services the /pools path returns a JSON document with the hosts
+# of the backends this scheduler has received the capabilities of. The operator
+# polls this path to only report the scheduler as ready once it has the
+# capabilities of all the backends.
+#
 # Requires the name of the service as the first argument (volume, backup,
 # scheduler) and optionally a second argument with the location of the
 # configuration directory (defaults to /etc/cinder/cinder.conf.d)
 
+from concurrent import futures
 from http import server
+import json
+import os
 import signal
 import socket
 import sys
@@ -42,18 +64,35 @@ import time
 import threading
 
 from oslo_config import cfg
+import oslo_messaging as messaging
 
 from cinder import context
+from cinder import rpc
+from cinder.scheduler import rpcapi as scheduler_rpcapi
 from cinder.volume import configuration as vol_conf
 from cinder import objects
 
 
 SERVER_PORT = 8080
+MESSAGING_PATH = '/messaging'
+BACKENDS_PATH = '/backends'
+POOLS_PATH = '/pools'
+# Must be lower than the timeout of the readiness probe, so leave a second to
+# the kubelet to get the reply, or half of the timeout for shorter probes
+HEALTHCHECK_TIMEOUT = int(os.environ.get('HEALTHCHECK_TIMEOUT', 4))
+MESSAGING_TIMEOUT = max(HEALTHCHECK_TIMEOUT - 1, HEALTHCHECK_TIMEOUT / 2)
 CONF = cfg.CONF
 BINARIES = ('volume', 'backup', 'scheduler')
 
-class HTTPServerV6(server.HTTPServer):
-  address_family = socket.AF_INET6
+class HTTPServerV6(server.ThreadingHTTPServer):
+    """Server listening on all the IPv6 and IPv4 addresses of the pod."""
+    address_family = socket.AF_INET6
+
+    def server_bind(self):
+        # Accept IPv4 connections too, regardless of the sysctl default, so
+        # the probes work on IPv4, IPv6 and dual-stack clusters alike
+        self.socket.setsockopt(socket.IPPROTO_IPV6, socket.IPV6_V6ONLY, 0)
+        super().server_bind()
 
 class HeartbeatServer(server.BaseHTTPRequestHandler):
     @classmethod
@@ -61,10 +100,12 @@ class HeartbeatServer(server.BaseHTTPRequestHandler):
         """Calculate and initialize constants"""
         cls.binary = 'cinder-' + binary
         cls.ctxt = context.get_admin_context()
+        cls.backends = []
 
         if binary != 'volume':
             services_filters = [{'host': CONF.host}]
         else:
+            cls.backends = list(CONF.enabled_backends)
             backend_opts = [
                 cfg.StrOpt('backend_host'),
                 cfg.StrOpt('backend_availability_zone', default=None),
@@ -92,6 +133,12 @@ class HeartbeatServer(server.BaseHTTPRequestHandler):
         # of dict/filters, and for others is a single element with the filters.
         cls.services_filters = services_filters
 
+        # Services listen on their topic using their host as the server
+        cls.topic = cls.binary
+        cls.rpc_servers = [service_filters['host']
+                           for service_filters in services_filters]
+        cls.transport = messaging.get_rpc_transport(CONF)
+
     @staticmethod
     def check_service(services, **filters):
         # Check services DB connectivity by looking at their DB heartbeat
@@ -109,10 +156,168 @@ class HeartbeatServer(server.BaseHTTPRequestHandler):
         if not ok:
             raise Exception('Service error', 'Service is not UP')
 
-        # TODO: Add a RabbitMQ connectivity check, for example using the
-        #       logging get feature
+    @classmethod
+    def ping(cls, rpc_server):
+        """Check RPC reachability of a service through the message bus.
+
+        Calls a method that doesn't exist on the service's own topic, so any
+        reply from the service (NoSuchMethod, UnsupportedVersion...) means
+        the message went through the broker and was consumed by the service,
+        while a timeout means that the service is not reachable.
+        """
+        get_client = getattr(messaging, 'get_rpc_client', messaging.RPCClient)
+        result = {'topic': cls.topic, 'server': rpc_server, 'status': 'ok'}
+        target = messaging.Target(topic=cls.topic, server=rpc_server)
+        client = get_client(cls.transport, target)
+        try:
+            client.prepare(timeout=MESSAGING_TIMEOUT).call(
+                {}, 'healthcheck_ping')
+        except messaging.MessagingTimeout:
+            result.update(status='error', detail='No reply from the service')
+        except messaging.RemoteError:
+            pass
+        except Exception as exc:
+            # Remote exceptions from allowed modules are re-raised using
+            # a dynamically created class
+            if not type(exc).__name__.endswith('_Remote'):
+                result.update(status='error', detail=str(exc))
+        return result
+
+    @classmethod
+    def check_messaging(cls):
+        """Ping all the services in parallel within MESSAGING_TIMEOUT."""
+        executor = futures.ThreadPoolExecutor(
+            max_workers=max(len(cls.rpc_servers), 1))
+        pings = [executor.submit(cls.ping, rpc_server)
+                 for rpc_server in cls.rpc_servers]
+        futures.wait(pings, timeout=MESSAGING_TIMEOUT)
+        # Don't wait for the pings that are still running, they will time out
+        # on their own
+        executor.shutdown(wait=False)
+
+        results = []
+        for rpc_server, ping in zip(cls.rpc_servers, pings):
+            if ping.done():
+                results.append(ping.result())
+            else:
+                results.append({'topic': cls.topic, 'server': rpc_server,
+                                'status': 'error',
+                                'detail': 'No reply from the service'})
+        return results
+
+    @staticmethod
+    def get_backend_status(backend, services, pools, host):
+        status = {'name': backend, 'host': host, 'up': False}
+        service = next((service for service in services
+                        if service.host == host), None)
+        if service is None:
+            status['error'] = 'Service not found'
+            return status
+
+        status.update(up=service.is_up,
+                      disabled=service.disabled,
+                      frozen=service.frozen,
+                      replicationStatus=service.replication_status)
+        if service.updated_at:
+            status['lastHeartbeat'] = service.updated_at.strftime(
+                '%Y-%m-%dT%H:%M:%SZ')
+
+        # Pool names are in the form host@backend#pool
+        backend_pools = [pool for pool in pools
+                         if pool['name'].partition('#')[0] == host]
+        if backend_pools:
+            caps = backend_pools[0]['capabilities']
+            status['capabilities'] = {
+                'vendorName': str(caps.get('vendor_name', '')),
+                'driverVersion': str(caps.get('driver_version', '')),
+                'storageProtocol': str(caps.get('storage_protocol', '')),
+            }
+        status['pools'] = [
+            {'name': pool['name'].partition('#')[2],
+             'totalCapacityGB': str(pool['capabilities'].get(
+                 'total_capacity_gb', 'unknown')),
+             'freeCapacityGB': str(pool['capabilities'].get(
+                 'free_capacity_gb', 'unknown'))}
+            for pool in backend_pools
+        ]
+        return status
+
+    def send_backends_status(self):
+        if not self.backends:
+            return self.send_error(404, 'Not found',
+                                   'Only available for volume services')
+        try:
+            services = objects.ServiceList.get_all_by_binary(self.ctxt,
+                                                             self.binary)
+        except Exception as exc:
+            return self.send_error(500,
+                                   'DB access error',
+                                   f'Failed to connect to the database: {exc}')
+
+        # The scheduler is the one that has the capabilities of all the pools
+        result = {}
+        try:
+            client = scheduler_rpcapi.SchedulerAPI().client.prepare(
+                timeout=MESSAGING_TIMEOUT)
+            pools = client.call(self.ctxt, 'get_pools', filters=None)
+        except Exception as exc:
+            pools = []
+            result['error'] = f'Failed to get the pools: {exc}'
+
+        result['backends'] = [
+            self.get_backend_status(backend, services, pools,
+                                    service_filters['host'])
+            for backend, service_filters in zip(self.backends,
+                                                self.services_filters)
+        ]
+
+        self.send_response(200)
+        self.send_header("Content-type", "application/json")
+        self.end_headers()
+        self.wfile.write(json.dumps(result).encode('utf-8'))
+
+    def send_pools(self):
+        if self.binary != 'cinder-scheduler':
+            return self.send_error(404, 'Not found',
+                                   'Only available for scheduler services')
+
+        # Ask this scheduler instead of any of them, since each one has its
+        # own cache of capabilities
+        client = scheduler_rpcapi.SchedulerAPI().client.prepare(
+            server=CONF.host, timeout=MESSAGING_TIMEOUT)
+        try:
+            pools = client.call(self.ctxt, 'get_pools', filters=None)
+        except Exception as exc:
+            return self.send_error(503, 'Scheduler error',
+                                   f'Failed to get the pools: {exc}')
+
+        # Pool names are in the form host@backend#pool
+        hosts = sorted({pool['name'].partition('#')[0] for pool in pools})
+
+        self.send_response(200)
+        self.send_header("Content-type", "application/json")
+        self.end_headers()
+        self.wfile.write(json.dumps({'hosts': hosts}).encode('utf-8'))
+
+    def send_messaging_status(self):
+        results = self.check_messaging()
+        ok = all(result['status'] == 'ok' for result in results)
+        body = json.dumps({'status': 'ok' if ok else 'error',
+                           'services': results})
+
+        self.send_response(200 if ok else 503)
+        self.send_header("Content-type", "application/json")
+        self.end_headers()
+        self.wfile.write(body.encode('utf-8'))
 
     def do_GET(self):
+        if self.path == MESSAGING_PATH:
+            return self.send_messaging_status()
+        if self.path == BACKENDS_PATH:
+            return self.send_backends_status()
+        if self.path == POOLS_PATH:
+            return self.send_pools()
+
         try:
             services = objects.ServiceList.get_all_by_binary(self.ctxt,
                                                              self.binary)
@@ -163,15 +368,17 @@ if __name__ == "__main__":
     # Initialize Oslo Config
     CONF.register_opt(cfg.StrOpt('cluster', default=None))
     CONF([ '--config-dir', cfg_dir], project='cinder')
+    rpc.init(CONF)
 
     HeartbeatServer.initialize_class(binary)
 
     hostname = socket.gethostname()
-    ipv6_address = socket.getaddrinfo(hostname, None, socket.AF_INET6)
-    if ipv6_address:
-        webServer = HTTPServerV6(("::",SERVER_PORT), HeartbeatServer)
-    else:
-        webServer = server.HTTPServer(("0.0.0.0", SERVER_PORT), HeartbeatServer)
+    # Only fall back to IPv4 when the node has IPv6 disabled
+    try:
+        webServer = HTTPServerV6(("::", SERVER_PORT), HeartbeatServer)
+    except OSError:
+        webServer = server.ThreadingHTTPServer(("0.0.0.0", SERVER_PORT),
+                                               HeartbeatServer)
     stop = get_stopper(webServer)
 
     # Need to run the server on a different thread because its shutdown method
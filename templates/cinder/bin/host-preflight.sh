@@ -0,0 +1,99 @@
+#!/bin/bash
+#
+# Copyright 2024 Red Hat Inc.
+#
+# Licensed under the Apache License, Version 2.0 (the "License"); you may
+# not use this file except in compliance with the License. You may obtain
+# a copy of the License at
+#
+#      http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+# WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+# License for the specific language governing permissions and limitations
+# under the License.
+
+# Verify that the node where this runs has the host services and configuration
+# the cinder transports need. All the checks run on the host using nsenter, so
+# the pod must share the host PID namespace and be privileged.
+#
+# Usage: host-preflight.sh <interval> <prerequisite> [prerequisite ...]
+#
+# The checks are repeated every interval seconds while they pass, and the file
+# in OK_FILE is present so it can be used as a readiness probe. When a check
+# fails the reasons are written to the termination log and the script exits,
+# so they are reported in the status of the container.
+
+set -uo pipefail
+
+INTERVAL=$1
+shift
+PREREQUISITES=("$@")
+OK_FILE=/tmp/host-preflight-ok
+TERMINATION_LOG=/dev/termination-log
+
+function on_host {
+    nsenter -a -t 1 -- "$@"
+}
+
+function service_active {
+    on_host systemctl is-active --quiet "$1"
+}
+
+function check_iscsi {
+    if ! service_active iscsid.service && ! service_active iscsid.socket; then
+        echo "iscsid is not running"
+    fi
+    if ! on_host grep -qs '^InitiatorName=' /etc/iscsi/initiatorname.iscsi; then
+        echo "/etc/iscsi/initiatorname.iscsi has no InitiatorName"
+    fi
+}
+
+function check_multipath {
+    if ! service_active multipathd.service; then
+        echo "multipathd is not running"
+    fi
+    if ! on_host test -f /etc/multipath.conf; then
+        echo "/etc/multipath.conf is missing"
+        return
+    fi
+    local find_multipaths
+    find_multipaths=$(on_host awk '$1 == "find_multipaths" {gsub(/"/, "", $2); print $2}' /etc/multipath.conf | tail -n1)
+    if [[ ! "${find_multipaths}" =~ ^(yes|on)$ ]]; then
+        echo "find_multipaths is not enabled in /etc/multipath.conf"
+    fi
+}
+
+function check_nvme {
+    if ! on_host test -d /sys/module/nvme_fabrics; then
+        echo "nvme-fabrics module is not loaded"
+    fi
+    if ! on_host test -s /etc/nvme/hostnqn; then
+        echo "/etc/nvme/hostnqn is missing"
+    fi
+    if ! on_host test -s /etc/nvme/hostid; then
+        echo "/etc/nvme/hostid is missing"
+    fi
+}
+
+rm -f "${OK_FILE}"
+while true; do
+    FAILURES=()
+    for prerequisite in "${PREREQUISITES[@]}"; do
+        while read -r failure; do
+            [ -n "${failure}" ] && FAILURES+=("${failure}")
+        done < <("check_${prerequisite}")
+    done
+
+    if [ ${#FAILURES[@]} -gt 0 ]; then
+        rm -f "${OK_FILE}"
+        printf '%s\n' "${FAILURES[@]}"
+        MESSAGE=$(printf '%s, ' "${FAILURES[@]}")
+        echo -n "${MESSAGE%, }" > "${TERMINATION_LOG}"
+        exit 1
+    fi
+
+    touch "${OK_FILE}"
+    sleep "${INTERVAL}"
+done
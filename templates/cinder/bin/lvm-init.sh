@@ -0,0 +1,70 @@
+#!/bin/bash
+#
+# Copyright 2024 Red Hat Inc.
+#
+# Licensed under the Apache License, Version 2.0 (the "License"); you may
+# not use this file except in compliance with the License. You may obtain
+# a copy of the License at
+#
+#      http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+# WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+# License for the specific language governing permissions and limitations
+# under the License.
+
+# Create or validate the LVM volume group of a cinder LVM backend on the node
+# where this runs. All the commands run on the host using nsenter, so the pod
+# must share the host PID namespace and be privileged.
+#
+# Usage: lvm-init.sh <volume group> <loop file size> [device ...]
+#
+# When the loop file size is not empty a file in /var/lib/cinder is used as a
+# loop device to create the volume group, otherwise the devices are used.
+
+set -euo pipefail
+
+VG=$1
+LOOP_FILE_SIZE=$2
+shift 2
+DEVICES=("$@")
+
+function on_host {
+    nsenter -a -t 1 -- "$@"
+}
+
+# The loop devices don't survive a reboot of the node, so the loop file is
+# attached again before looking for the volume group. This also runs as an init
+# container of the volume service for that reason.
+if [ -n "${LOOP_FILE_SIZE}" ]; then
+    LOOP_FILE="/var/lib/cinder/${VG}.img"
+    on_host mkdir -p /var/lib/cinder
+    if ! on_host test -f "${LOOP_FILE}"; then
+        echo "Creating ${LOOP_FILE_SIZE} loop file ${LOOP_FILE}"
+        on_host truncate -s "${LOOP_FILE_SIZE}" "${LOOP_FILE}"
+    fi
+    LOOP_DEVICE=$(on_host losetup -j "${LOOP_FILE}" | cut -d: -f1)
+    if [ -z "${LOOP_DEVICE}" ]; then
+        LOOP_DEVICE=$(on_host losetup --show -f "${LOOP_FILE}")
+        echo "Attached ${LOOP_FILE} to ${LOOP_DEVICE}"
+        on_host pvscan --cache "${LOOP_DEVICE}" || true
+    fi
+    DEVICES=("${LOOP_DEVICE}")
+fi
+
+if on_host vgs "${VG}" &>/dev/null; then
+    echo "Volume group ${VG} already exists"
+    on_host vgchange -ay "${VG}"
+    on_host vgs --units g "${VG}"
+    exit 0
+fi
+
+if [ ${#DEVICES[@]} -eq 0 ]; then
+    echo "No devices to create volume group ${VG}"
+    exit 1
+fi
+
+echo "Creating volume group ${VG} on ${DEVICES[*]}"
+on_host vgcreate "${VG}" "${DEVICES[@]}"
+on_host vgs --units g "${VG}"
@@ -0,0 +1,61 @@
+#!/bin/bash
+#
+# Copyright 2024 Red Hat Inc.
+#
+# Licensed under the Apache License, Version 2.0 (the "License"); you may
+# not use this file except in compliance with the License. You may obtain
+# a copy of the License at
+#
+#      http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+# WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+# License for the specific language governing permissions and limitations
+# under the License.
+
+# Run the given command with my_ip set to the address of the pod on the
+# STORAGE_NETWORK network, which also becomes the default target_ip_address of
+# the backends. The address is read from the network status Multus adds to the
+# pod, exposed through the downward API, and passed to the service through the
+# oslo.config environment variable so the config files don't need to change.
+#
+# IPv6 addresses are passed as they are, without brackets, as my_ip expects.
+
+NETWORK_STATUS=${NETWORK_STATUS:-/var/lib/pod-info/network-status}
+TIMEOUT=${STORAGE_NETWORK_TIMEOUT:-60}
+
+get_ip() {
+    python3 - "${STORAGE_NETWORK}" "${NETWORK_STATUS}" <<'PYEOF'
+import json
+import sys
+
+network, path = sys.argv[1:]
+try:
+    with open(path) as f:
+        networks = json.load(f)
+except (OSError, ValueError):
+    sys.exit(1)
+for net in networks:
+    if net.get('name') == network and net.get('ips'):
+        print(net['ips'][0])
+        sys.exit(0)
+sys.exit(1)
+PYEOF
+}
+
+# The kubelet refreshes the file once Multus has reported the networks of the
+# pod, which may happen after the container starts
+for ((i = 0; i < TIMEOUT; i++)); do
+    MY_IP=$(get_ip) && break
+    sleep 1
+done
+
+if [ -z "${MY_IP}" ]; then
+    echo "The pod has no address on the ${STORAGE_NETWORK} network"
+    exit 1
+fi
+
+echo "Using ${MY_IP} from the ${STORAGE_NETWORK} network as my_ip"
+export OS_DEFAULT__MY_IP=${MY_IP}
+exec "$@"
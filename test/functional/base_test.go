@@ -15,11 +15,21 @@ package functional
 
 import (
 	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
 
 	"golang.org/x/exp/maps"
 
-	. "github.com/onsi/gomega" //revive:disable:dot-imports
+	. "github.com/onsi/ginkgo/v2" //revive:disable:dot-imports
+	. "github.com/onsi/gomega"    //revive:disable:dot-imports
 	cinderv1 "github.com/openstack-k8s-operators/cinder-operator/api/v1beta1"
+	"github.com/openstack-k8s-operators/cinder-operator/pkg/cinder"
+	"github.com/openstack-k8s-operators/cinder-operator/pkg/cinderscheduler"
+	"github.com/openstack-k8s-operators/cinder-operator/pkg/cindervolume"
+	memcachedv1 "github.com/openstack-k8s-operators/infra-operator/apis/memcached/v1beta1"
+	common "github.com/openstack-k8s-operators/lib-common/modules/common"
 	condition "github.com/openstack-k8s-operators/lib-common/modules/common/condition"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -27,6 +37,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
@@ -163,6 +174,74 @@ func CreateCinder(name types.NamespacedName, spec map[string]interface{}) client
 	return CreateUnstructured(raw)
 }
 
+// setupCinderDeps - creates a Cinder with the given spec, a KeystoneAPI, and
+// simulates the rest of the dependencies Cinder needs to deploy its services
+func setupCinderDeps(spec map[string]interface{}) {
+	keystoneAPIName := keystone.CreateKeystoneAPI(cinderTest.Instance.Namespace)
+	DeferCleanup(keystone.DeleteKeystoneAPI, keystoneAPIName)
+	setupCinderDepsWithoutKeystone(spec)
+}
+
+// setupCinderDepsWithoutKeystone - creates a Cinder with the given spec and
+// simulates the message bus, the database, memcached and the db-sync job
+func setupCinderDepsWithoutKeystone(spec map[string]interface{}) {
+	createCinderDeps(spec)
+	th.SimulateJobSuccess(cinderTest.CinderDBSync)
+}
+
+// createCinderDeps - creates a Cinder with the given spec and simulates the
+// message bus, the database and memcached, but not the db-sync job
+func createCinderDeps(spec map[string]interface{}) {
+	DeferCleanup(th.DeleteInstance, CreateCinder(cinderTest.Instance, spec))
+	DeferCleanup(k8sClient.Delete, ctx, CreateCinderMessageBusSecret(cinderTest.Instance.Namespace, cinderTest.RabbitmqSecretName))
+	DeferCleanup(
+		mariadb.DeleteDBService,
+		mariadb.CreateDBService(
+			cinderTest.Instance.Namespace,
+			GetCinder(cinderTest.Instance).Spec.DatabaseInstance,
+			corev1.ServiceSpec{
+				Ports: []corev1.ServicePort{{Port: 3306}},
+			},
+		),
+	)
+	infra.SimulateTransportURLReady(cinderTest.CinderTransportURL)
+	memcachedSpec := memcachedv1.MemcachedSpec{
+		MemcachedSpecCore: memcachedv1.MemcachedSpecCore{
+			Replicas: ptr.To(int32(3)),
+		},
+	}
+	DeferCleanup(infra.DeleteMemcached, infra.CreateMemcached(namespace, cinderTest.MemcachedInstance, memcachedSpec))
+	infra.SimulateMemcachedReady(cinderTest.CinderMemcached)
+	mariadb.SimulateMariaDBAccountCompleted(cinderTest.Database)
+	mariadb.SimulateMariaDBDatabaseCompleted(cinderTest.Database)
+}
+
+// SimulateImageResolved - simulates the pod of an image resolve job reporting
+// the given image ID, and the job completing
+func SimulateImageResolved(name types.NamespacedName, imageID string) {
+	job := th.GetJob(name)
+	resolvePod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name.Name + "-pod",
+			Namespace: name.Namespace,
+			Labels: map[string]string{
+				batchv1.JobNameLabel: name.Name,
+			},
+		},
+		Spec: *job.Spec.Template.Spec.DeepCopy(),
+	}
+	Expect(k8sClient.Create(ctx, resolvePod)).Should(Succeed())
+	DeferCleanup(th.DeleteInstance, resolvePod)
+
+	resolvePod.Status.Phase = corev1.PodSucceeded
+	resolvePod.Status.ContainerStatuses = []corev1.ContainerStatus{{
+		Name:    job.Spec.Template.Spec.Containers[0].Name,
+		ImageID: imageID,
+	}}
+	Expect(k8sClient.Status().Update(ctx, resolvePod)).Should(Succeed())
+	th.SimulateJobSuccess(name)
+}
+
 func CinderConditionGetter(name types.NamespacedName) condition.Conditions {
 	instance := GetCinder(name)
 	return instance.Status.Conditions
@@ -273,6 +352,11 @@ func CinderSchedulerConditionGetter(name types.NamespacedName) condition.Conditi
 	return instance.Status.Conditions
 }
 
+func CinderVolumeConditionGetter(name types.NamespacedName) condition.Conditions {
+	instance := GetCinderVolume(name)
+	return instance.Status.Conditions
+}
+
 func CinderAPINotExists(name types.NamespacedName) {
 	Consistently(func(g Gomega) {
 		instance := &cinderv1.CinderAPI{}
@@ -392,3 +476,76 @@ func GetSampleTopologySpec(label string) (map[string]interface{}, []corev1.Topol
 	}
 	return topologySpec, topologySpecObj
 }
+
+// CreateRunningVolumePod - creates a pod of the given CinderVolume backend and
+// reports it as running with the given IP, since envtest doesn't run the pods
+// of the StatefulSets
+func CreateRunningVolumePod(name types.NamespacedName, backend string, podIP string) *corev1.Pod {
+	return createRunningPod(name, map[string]string{
+		common.AppSelector:       cinder.ServiceName,
+		common.ComponentSelector: cindervolume.ComponentName,
+		cinderv1.Backend:         backend,
+	}, podIP)
+}
+
+// CreateRunningSchedulerPod - creates a pod of the CinderScheduler and reports
+// it as running with the given IP
+func CreateRunningSchedulerPod(name types.NamespacedName, podIP string) *corev1.Pod {
+	return createRunningPod(name, map[string]string{
+		common.AppSelector:       cinder.ServiceName,
+		common.ComponentSelector: cinderscheduler.ComponentName,
+	}, podIP)
+}
+
+func createRunningPod(name types.NamespacedName, podLabels map[string]string, podIP string) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name.Name,
+			Namespace: name.Namespace,
+			Labels:    podLabels,
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{
+				Name:  "probe",
+				Image: cinderv1.CinderVolumeContainerImage,
+			}},
+		},
+	}
+	Expect(k8sClient.Create(ctx, pod)).Should(Succeed())
+	DeferCleanup(th.DeleteInstance, pod)
+
+	pod.Status.Phase = corev1.PodRunning
+	pod.Status.PodIP = podIP
+	pod.Status.PodIPs = []corev1.PodIP{{IP: podIP}}
+	Expect(k8sClient.Status().Update(ctx, pod)).Should(Succeed())
+	return pod
+}
+
+// StartHealthcheckServer - serves the given documents, by path, on the
+// healthcheck sidecar port of the given IP, skipping the test when the port
+// is not available
+func StartHealthcheckServer(ip string, bodies map[string]string) {
+	listener, err := net.Listen("tcp", net.JoinHostPort(ip, strconv.Itoa(cinder.HealthcheckPort)))
+	if err != nil {
+		Skip(fmt.Sprintf("healthcheck port not available: %s", err))
+	}
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := bodies[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+	}))
+	server.Listener.Close()
+	server.Listener = listener
+	server.Start()
+	DeferCleanup(server.Close)
+}
+
+// StartBackendsStatusServer - serves the given document on the /backends path
+// of the healthcheck sidecar port of the given IP
+func StartBackendsStatusServer(ip string, body string) {
+	StartHealthcheckServer(ip, map[string]string{cindervolume.BackendsStatusPath: body})
+}
@@ -19,6 +19,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"strings"
 
 	. "github.com/onsi/ginkgo/v2" //revive:disable:dot-imports
 	. "github.com/onsi/gomega"    //revive:disable:dot-imports
@@ -27,14 +28,17 @@ import (
 	//revive:disable-next-line:dot-imports
 	. "github.com/openstack-k8s-operators/lib-common/modules/common/test/helpers"
 
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/utils/ptr"
 
 	cinderv1 "github.com/openstack-k8s-operators/cinder-operator/api/v1beta1"
 	"github.com/openstack-k8s-operators/cinder-operator/pkg/cinder"
+	"github.com/openstack-k8s-operators/cinder-operator/pkg/cinderapi"
 	memcachedv1 "github.com/openstack-k8s-operators/infra-operator/apis/memcached/v1beta1"
 	topologyv1 "github.com/openstack-k8s-operators/infra-operator/apis/topology/v1beta1"
 	condition "github.com/openstack-k8s-operators/lib-common/modules/common/condition"
@@ -248,6 +252,93 @@ var _ = Describe("Cinder controller", func() {
 			}, timeout, interval).ShouldNot(BeNil())
 		})
 	})
+	When("Cinder CR is created with a TLS transport URL", func() {
+		BeforeEach(func() {
+			DeferCleanup(th.DeleteInstance, CreateCinder(cinderTest.Instance, GetDefaultCinderSpec()))
+			DeferCleanup(k8sClient.Delete, ctx, th.CreateSecret(
+				types.NamespacedName{Namespace: cinderTest.Instance.Namespace, Name: cinderTest.RabbitmqSecretName},
+				map[string][]byte{
+					"transport_url": []byte("rabbit://" + cinderTest.RabbitmqSecretName + "/fake?ssl=1"),
+				},
+			))
+			DeferCleanup(mariadb.DeleteDBService, mariadb.CreateDBService(
+				cinderTest.Instance.Namespace,
+				GetCinder(cinderName).Spec.DatabaseInstance,
+				corev1.ServiceSpec{
+					Ports: []corev1.ServicePort{{Port: 3306}},
+				},
+			),
+			)
+			infra.SimulateTransportURLReady(cinderTest.CinderTransportURL)
+			DeferCleanup(infra.DeleteMemcached, infra.CreateMemcached(namespace, "memcached", memcachedSpec))
+			infra.SimulateMemcachedReady(cinderTest.CinderMemcached)
+			mariadb.SimulateMariaDBAccountCompleted(cinderTest.Database)
+			mariadb.SimulateMariaDBDatabaseCompleted(cinderTest.Database)
+		})
+		It("reports that there is no CA bundle to verify the messaging bus", func() {
+			keystoneAPI := keystone.CreateKeystoneAPI(cinderTest.Instance.Namespace)
+			DeferCleanup(keystone.DeleteKeystoneAPI, keystoneAPI)
+			th.ExpectConditionWithDetails(
+				cinderTest.Instance,
+				ConditionGetterFunc(CinderConditionGetter),
+				condition.ServiceConfigReadyCondition,
+				corev1.ConditionFalse,
+				condition.ErrorReason,
+				"Service config create error occurred the database or the messaging bus use TLS "+
+					"and no CA bundle is set to verify them",
+			)
+			th.AssertSecretDoesNotExist(cinderTest.CinderConfigSecret)
+			Eventually(func(g Gomega) {
+				g.Expect(GetCinder(cinderTest.Instance).Status.TLS.Messaging).To(BeTrue())
+			}, timeout, interval).Should(Succeed())
+		})
+	})
+	When("Cinder CR is created with a TLS transport URL and a CA bundle", func() {
+		BeforeEach(func() {
+			spec := GetDefaultCinderSpec()
+			spec["cinderAPI"] = GetDefaultCinderAPISpec()
+			spec["cinderAPI"].(map[string]interface{})["tls"] = map[string]interface{}{
+				"caBundleSecretName": CABundleSecretName,
+			}
+			DeferCleanup(k8sClient.Delete, ctx, th.CreateCABundleSecret(cinderTest.CABundleSecret))
+			DeferCleanup(th.DeleteInstance, CreateCinder(cinderTest.Instance, spec))
+			DeferCleanup(k8sClient.Delete, ctx, th.CreateSecret(
+				types.NamespacedName{Namespace: cinderTest.Instance.Namespace, Name: cinderTest.RabbitmqSecretName},
+				map[string][]byte{
+					"transport_url": []byte("rabbit://" + cinderTest.RabbitmqSecretName + "/fake?ssl=1"),
+				},
+			))
+			DeferCleanup(mariadb.DeleteDBService, mariadb.CreateDBService(
+				cinderTest.Instance.Namespace,
+				GetCinder(cinderName).Spec.DatabaseInstance,
+				corev1.ServiceSpec{
+					Ports: []corev1.ServicePort{{Port: 3306}},
+				},
+			),
+			)
+			infra.SimulateTransportURLReady(cinderTest.CinderTransportURL)
+			DeferCleanup(infra.DeleteMemcached, infra.CreateMemcached(namespace, "memcached", memcachedSpec))
+			infra.SimulateMemcachedReady(cinderTest.CinderMemcached)
+			mariadb.SimulateMariaDBAccountCompleted(cinderTest.Database)
+			mariadb.SimulateMariaDBDatabaseCompleted(cinderTest.Database)
+		})
+		It("enables TLS on the messaging bus", func() {
+			keystoneAPI := keystone.CreateKeystoneAPI(cinderTest.Instance.Namespace)
+			DeferCleanup(keystone.DeleteKeystoneAPI, keystoneAPI)
+			cf := th.GetSecret(cinderTest.CinderConfigSecret)
+			defaults := string(cf.Data[cinder.DefaultsConfigFileName])
+			Expect(defaults).To(ContainSubstring(
+				"[oslo_messaging_rabbit]\nheartbeat_timeout_threshold=60\nssl = true\n" +
+					"ssl_ca_file = /etc/pki/ca-trust/extracted/pem/tls-ca-bundle.pem"))
+			Expect(defaults).ToNot(ContainSubstring("ssl_ca="))
+
+			Eventually(func(g Gomega) {
+				tlsStatus := GetCinder(cinderTest.Instance).Status.TLS
+				g.Expect(tlsStatus.Database).To(BeFalse())
+				g.Expect(tlsStatus.Messaging).To(BeTrue())
+			}, timeout, interval).Should(Succeed())
+		})
+	})
 	When("Cinder CR is created without container images defined", func() {
 		BeforeEach(func() {
 			// CinderEmptySpec is used to provide a standard Cinder CR where no
@@ -529,6 +620,15 @@ var _ = Describe("Cinder controller", func() {
 			conf := cf.Data[cinder.MyCnfFileName]
 			Expect(conf).To(
 				ContainSubstring("[client]\nssl-ca=/etc/pki/ca-trust/extracted/pem/tls-ca-bundle.pem\nssl=1"))
+			defaults := string(cf.Data[cinder.DefaultsConfigFileName])
+			Expect(defaults).To(
+				ContainSubstring("?read_default_file=/etc/my.cnf&ssl_ca=/etc/pki/ca-trust/extracted/pem/tls-ca-bundle.pem"))
+			Expect(defaults).ToNot(ContainSubstring("ssl = true"))
+			Eventually(func(g Gomega) {
+				tlsStatus := GetCinder(cinderTest.Instance).Status.TLS
+				g.Expect(tlsStatus.Database).To(BeTrue())
+				g.Expect(tlsStatus.Messaging).To(BeFalse())
+			}, timeout, interval).Should(Succeed())
 			Eventually(func() corev1.Secret {
 				return th.GetSecret(cinderTest.CinderConfigScripts)
 			}, timeout, interval).ShouldNot(BeNil())
@@ -554,23 +654,50 @@ var _ = Describe("Cinder controller", func() {
 			// Check the resulting deployment fields
 			Expect(int(*d.Spec.Replicas)).To(Equal(1))
 			Expect(d.Spec.Template.Spec.Volumes).To(HaveLen(9))
-			Expect(d.Spec.Template.Spec.Containers).To(HaveLen(2))
+			Expect(d.Spec.Template.Spec.Containers).To(HaveLen(3))
 
 			// cert deployment volumes
 			th.AssertVolumeExists(cinderTest.CABundleSecret.Name, d.Spec.Template.Spec.Volumes)
 			th.AssertVolumeExists(cinderTest.InternalCertSecret.Name, d.Spec.Template.Spec.Volumes)
 			th.AssertVolumeExists(cinderTest.PublicCertSecret.Name, d.Spec.Template.Spec.Volumes)
 
-			// cert volumeMounts
+			// cert volumeMounts, without subPath so renewed certs are updated
+			// in place
 			container := d.Spec.Template.Spec.Containers[1]
-			th.AssertVolumeMountExists(cinderTest.InternalCertSecret.Name, "tls.key", container.VolumeMounts)
-			th.AssertVolumeMountExists(cinderTest.InternalCertSecret.Name, "tls.crt", container.VolumeMounts)
-			th.AssertVolumeMountExists(cinderTest.PublicCertSecret.Name, "tls.key", container.VolumeMounts)
-			th.AssertVolumeMountExists(cinderTest.PublicCertSecret.Name, "tls.crt", container.VolumeMounts)
+			Expect(container.VolumeMounts).To(ContainElement(corev1.VolumeMount{
+				Name:      cinderTest.InternalCertSecret.Name,
+				MountPath: cinder.APICertsPath + "/internal",
+				ReadOnly:  true,
+			}))
+			Expect(container.VolumeMounts).To(ContainElement(corev1.VolumeMount{
+				Name:      cinderTest.PublicCertSecret.Name,
+				MountPath: cinder.APICertsPath + "/public",
+				ReadOnly:  true,
+			}))
 			th.AssertVolumeMountExists(cinderTest.CABundleSecret.Name, "tls-ca-bundle.pem", container.VolumeMounts)
 
 			Expect(container.ReadinessProbe.HTTPGet.Scheme).To(Equal(corev1.URISchemeHTTPS))
 			Expect(container.LivenessProbe.HTTPGet.Scheme).To(Equal(corev1.URISchemeHTTPS))
+
+			// httpd is reloaded by a sidecar when the certs are renewed, so
+			// they are not part of the hash that restarts the pods
+			Expect(d.Spec.Template.Spec.ShareProcessNamespace).To(Equal(ptr.To(true)))
+			reload := d.Spec.Template.Spec.Containers[2]
+			Expect(reload.Name).To(Equal(cinderTest.CinderAPI.Name + "-cert-reload"))
+			Expect(reload.Args).To(ContainElement(cinder.CertReloadScript))
+
+			wsgi := string(th.GetSecret(cinderTest.CinderConfigSecret).Data["10-cinder_wsgi.conf"])
+			Expect(wsgi).To(ContainSubstring(cinder.APICertsPath + "/internal/tls.crt"))
+			Expect(wsgi).To(ContainSubstring(cinder.APICertsPath + "/public/tls.key"))
+
+			// renewing a cert doesn't change the pod template
+			configHash := GetEnvVarValue(container.Env, "CONFIG_HASH", "")
+			Expect(configHash).NotTo(BeEmpty())
+			th.UpdateSecret(cinderTest.InternalCertSecret, "tls.crt", []byte("RenewedCertData"))
+			Consistently(func(g Gomega) {
+				container := th.GetStatefulSet(cinderTest.CinderAPI).Spec.Template.Spec.Containers[1]
+				g.Expect(GetEnvVarValue(container.Env, "CONFIG_HASH", "")).To(Equal(configHash))
+			}, timeout, interval).Should(Succeed())
 		})
 		It("Creates CinderScheduler", func() {
 			DeferCleanup(k8sClient.Delete, ctx, th.CreateCABundleSecret(cinderTest.CABundleSecret))
@@ -593,6 +720,8 @@ var _ = Describe("Cinder controller", func() {
 			Expect(int(*ss.Spec.Replicas)).To(Equal(1))
 			Expect(ss.Spec.Template.Spec.Volumes).To(HaveLen(6))
 			Expect(ss.Spec.Template.Spec.Containers).To(HaveLen(2))
+			// The capabilities warmup is disabled by default
+			Expect(ss.Spec.Template.Spec.ReadinessGates).To(BeEmpty())
 
 			// cert deployment volumes
 			th.AssertVolumeExists(cinderTest.CABundleSecret.Name, ss.Spec.Template.Spec.Volumes)
@@ -1185,124 +1314,2397 @@ var _ = Describe("Cinder controller", func() {
 			th.AssertVolumeMountExists(CinderCephExtraMountsSecretName, "", container.VolumeMounts)
 		})
 	})
-	// Run MariaDBAccount suite tests.  these are pre-packaged ginkgo tests
-	// that exercise standard account create / update patterns that should be
-	// common to all controllers that ensure MariaDBAccount CRs.
-	mariadbSuite := &mariadb_test.MariaDBTestHarness{
-		PopulateHarness: func(harness *mariadb_test.MariaDBTestHarness) {
-			harness.Setup(
-				"Cinder",
-				cinderTest.Instance.Namespace,
-				cinder.DatabaseName,
-				"openstack.org/cinder",
-				mariadb,
-				timeout,
-				interval,
-			)
-		},
-		// Generate a fully running Cinder service given an accountName
-		// needs to make it all the way to the end where the mariadb finalizers
-		// are removed from unused accounts since that's part of what we are testing
-		SetupCR: func(accountName types.NamespacedName) {
-			spec := GetTLSCinderSpec()
-			spec["databaseAccount"] = accountName.Name
+	When("Cinder CR instance is built with the uwsgi API server", func() {
+		BeforeEach(func() {
+			spec := GetDefaultCinderSpec()
+			apiSpec := GetDefaultCinderAPISpec()
+			apiSpec["apiServer"] = "uwsgi"
+			spec["cinderAPI"] = apiSpec
+			setupCinderDeps(spec)
+			keystone.SimulateKeystoneServiceReady(cinderTest.CinderKeystoneService)
+			keystone.SimulateKeystoneEndpointReady(cinderTest.CinderKeystoneEndpoint)
+		})
 
-			DeferCleanup(th.DeleteInstance, CreateCinder(cinderTest.Instance, spec))
-			DeferCleanup(k8sClient.Delete, ctx, CreateCinderMessageBusSecret(cinderTest.Instance.Namespace, cinderTest.RabbitmqSecretName))
-			DeferCleanup(th.DeleteInstance, CreateCinderAPI(cinderTest.Instance, GetDefaultCinderAPISpec()))
-			DeferCleanup(th.DeleteInstance, CreateCinderScheduler(cinderTest.Instance, GetDefaultCinderSchedulerSpec()))
-			DeferCleanup(th.DeleteInstance, CreateCinderVolume(cinderTest.Instance, GetDefaultCinderVolumeSpec()))
-			DeferCleanup(
-				mariadb.DeleteDBService,
-				mariadb.CreateDBService(
-					cinderTest.Instance.Namespace,
-					GetCinder(cinderName).Spec.DatabaseInstance,
-					corev1.ServiceSpec{
-						Ports: []corev1.ServicePort{{Port: 3306}},
+		It("runs the API in uwsgi as the cinder user", func() {
+			configData := th.GetSecret(cinderTest.CinderConfigSecret)
+			Expect(configData.Data).To(HaveKey("cinder-api-uwsgi-config.json"))
+			kollaConfig := string(configData.Data["cinder-api-uwsgi-config.json"])
+			Expect(kollaConfig).To(ContainSubstring(`"command": "/usr/sbin/uwsgi --ini /etc/cinder/cinder-api-uwsgi.ini"`))
+			ini := string(configData.Data["cinder-api-uwsgi.ini"])
+			Expect(ini).To(ContainSubstring("wsgi-file = /var/www/cgi-bin/cinder/cinder-wsgi"))
+			Expect(ini).To(ContainSubstring("harakiri = 60"))
+			Expect(ini).To(ContainSubstring("http-socket = :8776"))
+			Expect(ini).ToNot(ContainSubstring("https = "))
+
+			ss := th.GetStatefulSet(cinderTest.CinderAPI)
+			cinderUser := int64(cinderv1.CinderUserID)
+			logContainer := ss.Spec.Template.Spec.Containers[0]
+			Expect(logContainer.SecurityContext.RunAsUser).To(Equal(&cinderUser))
+			container := ss.Spec.Template.Spec.Containers[1]
+			Expect(container.SecurityContext.RunAsUser).To(Equal(&cinderUser))
+			Expect(container.VolumeMounts).To(ContainElement(And(
+				HaveField("Name", "config-data"),
+				HaveField("SubPath", "cinder-api-uwsgi-config.json"))))
+		})
+	})
+	When("Cinder CR instance is built with probe overrides", func() {
+		BeforeEach(func() {
+			rawSpec := map[string]interface{}{
+				"secret":              SecretName,
+				"databaseInstance":    "openstack",
+				"rabbitMqClusterName": "rabbitmq",
+				"cinderAPI": map[string]interface{}{
+					"containerImage": cinderv1.CinderAPIContainerImage,
+					"probes": map[string]interface{}{
+						"readiness": map[string]interface{}{
+							"periodSeconds": 15,
+						},
 					},
-				),
-			)
-			infra.SimulateTransportURLReady(cinderTest.CinderTransportURL)
-			DeferCleanup(infra.DeleteMemcached, infra.CreateMemcached(namespace, cinderTest.MemcachedInstance, memcachedSpec))
-			infra.SimulateMemcachedReady(cinderTest.CinderMemcached)
-			DeferCleanup(keystone.DeleteKeystoneAPI, keystone.CreateKeystoneAPI(cinderTest.Instance.Namespace))
-			mariadb.SimulateMariaDBAccountCompleted(accountName)
-			mariadb.SimulateMariaDBDatabaseCompleted(cinderTest.Database)
-			th.SimulateJobSuccess(cinderTest.CinderDBSync)
+				},
+				"cinderScheduler": map[string]interface{}{
+					"containerImage": cinderv1.CinderSchedulerContainerImage,
+					"probes": map[string]interface{}{
+						"startup": map[string]interface{}{
+							"failureThreshold": 20,
+						},
+					},
+				},
+				"cinderVolumes": map[string]interface{}{
+					"volume1": map[string]interface{}{
+						"containerImage": cinderv1.CinderVolumeContainerImage,
+						"probes": map[string]interface{}{
+							"profile": "powermax",
+							"liveness": map[string]interface{}{
+								"timeoutSeconds": 10,
+							},
+							"readiness": map[string]interface{}{
+								"timeoutSeconds": 8,
+							},
+						},
+					},
+				},
+			}
 
-			DeferCleanup(k8sClient.Delete, ctx, th.CreateCABundleSecret(cinderTest.CABundleSecret))
-			DeferCleanup(k8sClient.Delete, ctx, th.CreateCertSecret(cinderTest.InternalCertSecret))
-			DeferCleanup(k8sClient.Delete, ctx, th.CreateCertSecret(cinderTest.PublicCertSecret))
-			keystone.SimulateKeystoneServiceReady(cinderTest.CinderKeystoneService)
+			setupCinderDeps(rawSpec)
 			keystone.SimulateKeystoneEndpointReady(cinderTest.CinderKeystoneEndpoint)
+		})
 
-			th.AssertServiceExists(cinderTest.CinderServicePublic)
-			th.AssertServiceExists(cinderTest.CinderServiceInternal)
+		It("applies the probe overrides to the resulting StatefulSets", func() {
+			th.SimulateStatefulSetReplicaReady(cinderTest.CinderAPI)
+			th.SimulateStatefulSetReplicaReady(cinderTest.CinderScheduler)
+			volume := cinderTest.CinderVolumes[0]
+			th.SimulateStatefulSetReplicaReady(volume)
 
-			// check keystone endpoints
-			keystoneEndpoint := keystone.GetKeystoneEndpoint(cinderTest.CinderKeystoneEndpoint)
-			endpoints := keystoneEndpoint.Spec.Endpoints
-			Expect(endpoints).To(HaveKeyWithValue("public", "https://cinder-public."+namespace+".svc:8776/v3"))
-			Expect(endpoints).To(HaveKeyWithValue("internal", "https://cinder-internal."+namespace+".svc:8776/v3"))
+			// API readiness probe only changes the period
+			container := th.GetStatefulSet(cinderTest.CinderAPI).Spec.Template.Spec.Containers[1]
+			Expect(container.ReadinessProbe.PeriodSeconds).To(Equal(int32(15)))
+			Expect(container.ReadinessProbe.TimeoutSeconds).To(Equal(int32(5)))
+
+			// Scheduler keeps the default profile
+			container = th.GetStatefulSet(cinderTest.CinderScheduler).Spec.Template.Spec.Containers[0]
+			Expect(container.StartupProbe.FailureThreshold).To(Equal(int32(20)))
+			Expect(container.StartupProbe.PeriodSeconds).To(Equal(int32(5)))
+
+			// Volume uses the powermax profile for the startup probe
+			container = th.GetStatefulSet(volume).Spec.Template.Spec.Containers[0]
+			Expect(container.StartupProbe.FailureThreshold).To(Equal(int32(90)))
+			Expect(container.StartupProbe.PeriodSeconds).To(Equal(int32(10)))
+			Expect(container.LivenessProbe.TimeoutSeconds).To(Equal(int32(10)))
+			// Readiness checks the message bus with a longer timeout
+			Expect(container.ReadinessProbe.HTTPGet.Path).To(Equal("/messaging"))
+			Expect(container.ReadinessProbe.PeriodSeconds).To(Equal(int32(10)))
+			Expect(container.ReadinessProbe.TimeoutSeconds).To(Equal(int32(8)))
+		})
 
-		},
-		// Change the account name in the service to a new name
-		UpdateAccount: func(newAccountName types.NamespacedName) {
+		It("bounds the message bus checks by the readiness probe timeout", func() {
+			th.SimulateStatefulSetReplicaReady(cinderTest.CinderAPI)
+			th.SimulateStatefulSetReplicaReady(cinderTest.CinderScheduler)
+			volume := cinderTest.CinderVolumes[0]
+			th.SimulateStatefulSetReplicaReady(volume)
 
-			Eventually(func(g Gomega) {
-				cinder := GetCinder(cinderName)
-				cinder.Spec.DatabaseAccount = newAccountName.Name
-				g.Expect(th.K8sClient.Update(ctx, cinder)).Should(Succeed())
-			}, timeout, interval).Should(Succeed())
+			// Scheduler uses the default readiness probe timeout
+			probe := th.GetStatefulSet(cinderTest.CinderScheduler).Spec.Template.Spec.Containers[1]
+			Expect(probe.Name).To(Equal("probe"))
+			Expect(GetEnvVarValue(probe.Env, "HEALTHCHECK_TIMEOUT", "")).To(Equal("5"))
 
-		},
-		// delete the instance to exercise finalizer removal
-		DeleteCR: func() {
-			th.DeleteInstance(GetCinder(cinderName))
-		},
-	}
+			// Volume follows the override
+			probe = th.GetStatefulSet(volume).Spec.Template.Spec.Containers[1]
+			Expect(probe.Name).To(Equal("probe"))
+			Expect(GetEnvVarValue(probe.Env, "HEALTHCHECK_TIMEOUT", "")).To(Equal("8"))
+		})
 
-	mariadbSuite.RunBasicSuite()
+		It("waits for running pods to report the backends status", func() {
+			th.SimulateStatefulSetReplicaReady(cinderTest.CinderAPI)
+			th.SimulateStatefulSetReplicaReady(cinderTest.CinderScheduler)
+			volume := cinderTest.CinderVolumes[0]
+			th.SimulateStatefulSetReplicaReady(volume)
 
-})
+			th.ExpectCondition(
+				volume,
+				ConditionGetterFunc(CinderVolumeConditionGetter),
+				cinderv1.CinderVolumeBackendsReadyCondition,
+				corev1.ConditionUnknown,
+			)
+			Expect(GetCinderVolume(volume).Status.Backends).To(BeEmpty())
+		})
 
-var _ = Describe("Cinder Webhook", func() {
+		It("reports the backends of the running pods", func() {
+			StartBackendsStatusServer("127.0.0.1", `{"backends": [
+				{"name": "volume1", "host": "hostgroup@volume1", "up": true,
+				 "pools": [{"name": "volume1", "totalCapacityGB": "100", "freeCapacityGB": "50"}]}]}`)
+			volume := cinderTest.CinderVolumes[0]
+			CreateRunningVolumePod(
+				types.NamespacedName{Namespace: namespace, Name: volume.Name + "-0"}, "volume1", "127.0.0.1")
+			th.SimulateStatefulSetReplicaReady(cinderTest.CinderAPI)
+			th.SimulateStatefulSetReplicaReady(cinderTest.CinderScheduler)
+			th.SimulateStatefulSetReplicaReady(volume)
 
-	BeforeEach(func() {
-		err := os.Setenv("OPERATOR_TEMPLATES", "../../templates")
-		Expect(err).NotTo(HaveOccurred())
+			th.ExpectCondition(
+				volume,
+				ConditionGetterFunc(CinderVolumeConditionGetter),
+				cinderv1.CinderVolumeBackendsReadyCondition,
+				corev1.ConditionTrue,
+			)
+			backends := GetCinderVolume(volume).Status.Backends
+			Expect(backends).To(HaveLen(1))
+			Expect(backends[0].Host).To(Equal("hostgroup@volume1"))
+			Expect(backends[0].Pools).To(ConsistOf(HaveField("FreeCapacityGB", "50")))
+
+			// The Cinder CR reports the backends of all its CinderVolumes
+			Eventually(func(g Gomega) {
+				backends := GetCinder(cinderTest.Instance).Status.CinderVolumesBackends
+				g.Expect(backends).To(HaveKeyWithValue("volume1", HaveLen(1)))
+			}, timeout, interval).Should(Succeed())
+		})
+
+		It("reports the backends of the pods that reply when others fail", func() {
+			StartBackendsStatusServer("127.0.0.1", `{"backends": [
+				{"name": "volume1", "host": "hostgroup@volume1", "up": true}]}`)
+			volume := cinderTest.CinderVolumes[0]
+			CreateRunningVolumePod(
+				types.NamespacedName{Namespace: namespace, Name: volume.Name + "-0"}, "volume1", "127.0.0.1")
+			// Nothing listens on the healthcheck port of this address
+			CreateRunningVolumePod(
+				types.NamespacedName{Namespace: namespace, Name: volume.Name + "-1"}, "volume1", "127.0.0.2")
+			th.SimulateStatefulSetReplicaReady(cinderTest.CinderAPI)
+			th.SimulateStatefulSetReplicaReady(cinderTest.CinderScheduler)
+			th.SimulateStatefulSetReplicaReady(volume)
+
+			th.ExpectCondition(
+				volume,
+				ConditionGetterFunc(CinderVolumeConditionGetter),
+				cinderv1.CinderVolumeBackendsReadyCondition,
+				corev1.ConditionFalse,
+			)
+			conditions := CinderVolumeConditionGetter(volume)
+			Expect(conditions.Get(cinderv1.CinderVolumeBackendsReadyCondition).Message).To(
+				ContainSubstring(volume.Name + "-1: "))
+			Expect(conditions.Get(cinderv1.CinderVolumeBackendsReadyCondition).Message).ToNot(
+				ContainSubstring(volume.Name + "-0: "))
+			Expect(GetCinderVolume(volume).Status.Backends).To(ConsistOf(
+				HaveField("Host", "hostgroup@volume1")))
+		})
 	})
+	When("Cinder CR instance is built with the capabilities warmup", func() {
+		BeforeEach(func() {
+			rawSpec := map[string]interface{}{
+				"secret":              SecretName,
+				"databaseInstance":    "openstack",
+				"rabbitMqClusterName": "rabbitmq",
+				"cinderAPI": map[string]interface{}{
+					"containerImage": cinderv1.CinderAPIContainerImage,
+				},
+				"cinderScheduler": map[string]interface{}{
+					"containerImage":     cinderv1.CinderSchedulerContainerImage,
+					"capabilitiesWarmup": true,
+				},
+				"cinderVolumes": map[string]interface{}{
+					"volume1": map[string]interface{}{
+						"containerImage": cinderv1.CinderVolumeContainerImage,
+					},
+				},
+			}
 
-	It("rejects with wrong CinderAPI service override endpoint type", func() {
-		spec := GetDefaultCinderSpec()
-		apiSpec := GetDefaultCinderAPISpec()
-		apiSpec["override"] = map[string]interface{}{
-			"service": map[string]interface{}{
-				"internal": map[string]interface{}{},
-				"wrooong":  map[string]interface{}{},
-			},
-		}
-		spec["cinderAPI"] = apiSpec
+			setupCinderDeps(rawSpec)
+			keystone.SimulateKeystoneEndpointReady(cinderTest.CinderKeystoneEndpoint)
+		})
 
-		raw := map[string]interface{}{
-			"apiVersion": "cinder.openstack.org/v1beta1",
-			"kind":       "Cinder",
-			"metadata": map[string]interface{}{
-				"name":      cinderTest.Instance.Name,
-				"namespace": cinderTest.Instance.Namespace,
-			},
-			"spec": spec,
-		}
+		It("gates the readiness of the scheduler pods", func() {
+			ss := th.GetStatefulSet(cinderTest.CinderScheduler)
+			Expect(ss.Spec.Template.Spec.ReadinessGates).To(ConsistOf(
+				corev1.PodReadinessGate{ConditionType: "cinder.openstack.org/capabilities-ready"}))
+			// The rolling update of the statefulset is left as is
+			Expect(ss.Spec.UpdateStrategy.RollingUpdate).To(BeNil())
+		})
 
-		unstructuredObj := &unstructured.Unstructured{Object: raw}
-		_, err := controllerutil.CreateOrPatch(
-			th.Ctx, th.K8sClient, unstructuredObj, func() error { return nil })
-		Expect(err).To(HaveOccurred())
-		Expect(err.Error()).To(
-			ContainSubstring(
-				"invalid: spec.cinderAPI.override.service[wrooong]: " +
-					"Invalid value: \"wrooong\": invalid endpoint type: wrooong"),
-		)
+		It("marks the scheduler pods ready once they have the capabilities of the back-ends", func() {
+			StartHealthcheckServer("127.0.0.1", map[string]string{
+				"/backends": `{"backends": [{"name": "volume1", "host": "hostgroup@volume1", "up": true}]}`,
+				"/pools":    `{"hosts": ["hostgroup@volume1"]}`,
+			})
+			volume := cinderTest.CinderVolumes[0]
+			CreateRunningVolumePod(
+				types.NamespacedName{Namespace: namespace, Name: volume.Name + "-0"}, "volume1", "127.0.0.1")
+			scheduler := CreateRunningSchedulerPod(
+				types.NamespacedName{Namespace: namespace, Name: cinderTest.CinderScheduler.Name + "-0"}, "127.0.0.1")
+			th.SimulateStatefulSetReplicaReady(cinderTest.CinderAPI)
+			th.SimulateStatefulSetReplicaReady(cinderTest.CinderScheduler)
+			th.SimulateStatefulSetReplicaReady(volume)
+
+			th.ExpectCondition(
+				cinderTest.CinderScheduler,
+				ConditionGetterFunc(CinderSchedulerConditionGetter),
+				cinderv1.CinderSchedulerCapabilitiesReadyCondition,
+				corev1.ConditionTrue,
+			)
+			Eventually(func(g Gomega) {
+				p := th.GetPod(types.NamespacedName{Namespace: scheduler.Namespace, Name: scheduler.Name})
+				g.Expect(p.Status.Conditions).To(ContainElement(And(
+					HaveField("Type", corev1.PodConditionType("cinder.openstack.org/capabilities-ready")),
+					HaveField("Status", corev1.ConditionTrue),
+				)))
+			}, timeout, interval).Should(Succeed())
+		})
+
+		It("keeps the scheduler pods waiting for the missing capabilities", func() {
+			StartHealthcheckServer("127.0.0.1", map[string]string{
+				"/backends": `{"backends": [{"name": "volume1", "host": "hostgroup@volume1", "up": true}]}`,
+				"/pools":    `{"hosts": []}`,
+			})
+			volume := cinderTest.CinderVolumes[0]
+			CreateRunningVolumePod(
+				types.NamespacedName{Namespace: namespace, Name: volume.Name + "-0"}, "volume1", "127.0.0.1")
+			scheduler := CreateRunningSchedulerPod(
+				types.NamespacedName{Namespace: namespace, Name: cinderTest.CinderScheduler.Name + "-0"}, "127.0.0.1")
+			th.SimulateStatefulSetReplicaReady(cinderTest.CinderAPI)
+			th.SimulateStatefulSetReplicaReady(cinderTest.CinderScheduler)
+			th.SimulateStatefulSetReplicaReady(volume)
+
+			th.ExpectConditionWithDetails(
+				cinderTest.CinderScheduler,
+				ConditionGetterFunc(CinderSchedulerConditionGetter),
+				cinderv1.CinderSchedulerCapabilitiesReadyCondition,
+				corev1.ConditionFalse,
+				condition.RequestedReason,
+				fmt.Sprintf("Waiting for the capabilities of the back-ends on: %s", scheduler.Name),
+			)
+			Eventually(func(g Gomega) {
+				p := th.GetPod(types.NamespacedName{Namespace: scheduler.Namespace, Name: scheduler.Name})
+				g.Expect(p.Status.Conditions).To(ContainElement(And(
+					HaveField("Type", corev1.PodConditionType("cinder.openstack.org/capabilities-ready")),
+					HaveField("Status", corev1.ConditionFalse),
+					HaveField("Message", "Missing capabilities of hostgroup@volume1"),
+				)))
+			}, timeout, interval).Should(Succeed())
+		})
+
+		It("keeps the scheduler pods waiting for the back-ends that are down", func() {
+			StartHealthcheckServer("127.0.0.1", map[string]string{
+				"/backends": `{"backends": [{"name": "volume1", "host": "hostgroup@volume1", "up": false}]}`,
+				"/pools":    `{"hosts": []}`,
+			})
+			volume := cinderTest.CinderVolumes[0]
+			CreateRunningVolumePod(
+				types.NamespacedName{Namespace: namespace, Name: volume.Name + "-0"}, "volume1", "127.0.0.1")
+			scheduler := CreateRunningSchedulerPod(
+				types.NamespacedName{Namespace: namespace, Name: cinderTest.CinderScheduler.Name + "-0"}, "127.0.0.1")
+			th.SimulateStatefulSetReplicaReady(cinderTest.CinderAPI)
+			th.SimulateStatefulSetReplicaReady(cinderTest.CinderScheduler)
+			th.SimulateStatefulSetReplicaReady(volume)
+
+			Eventually(func(g Gomega) {
+				p := th.GetPod(types.NamespacedName{Namespace: scheduler.Namespace, Name: scheduler.Name})
+				g.Expect(p.Status.Conditions).To(ContainElement(And(
+					HaveField("Type", corev1.PodConditionType("cinder.openstack.org/capabilities-ready")),
+					HaveField("Status", corev1.ConditionFalse),
+					HaveField("Message", "Missing capabilities of hostgroup@volume1"),
+				)))
+			}, timeout, interval).Should(Succeed())
+		})
+
+		It("keeps the scheduler pods waiting for the volume services without back-ends", func() {
+			StartHealthcheckServer("127.0.0.1", map[string]string{
+				"/pools": `{"hosts": []}`,
+			})
+			scheduler := CreateRunningSchedulerPod(
+				types.NamespacedName{Namespace: namespace, Name: cinderTest.CinderScheduler.Name + "-0"}, "127.0.0.1")
+			th.SimulateStatefulSetReplicaReady(cinderTest.CinderAPI)
+			th.SimulateStatefulSetReplicaReady(cinderTest.CinderScheduler)
+
+			Eventually(func(g Gomega) {
+				p := th.GetPod(types.NamespacedName{Namespace: scheduler.Namespace, Name: scheduler.Name})
+				g.Expect(p.Status.Conditions).To(ContainElement(And(
+					HaveField("Type", corev1.PodConditionType("cinder.openstack.org/capabilities-ready")),
+					HaveField("Status", corev1.ConditionFalse),
+					HaveField("Message", "Missing capabilities of "+cinderTest.CinderVolumes[0].Name),
+				)))
+			}, timeout, interval).Should(Succeed())
+		})
+	})
+	When("Cinder CR instance is built with replication", func() {
+		BeforeEach(func() {
+			rawSpec := map[string]interface{}{
+				"secret":              SecretName,
+				"databaseInstance":    "openstack",
+				"rabbitMqClusterName": "rabbitmq",
+				"cinderAPI": map[string]interface{}{
+					"containerImage": cinderv1.CinderAPIContainerImage,
+				},
+				"cinderScheduler": map[string]interface{}{
+					"containerImage": cinderv1.CinderSchedulerContainerImage,
+				},
+				"cinderVolumes": map[string]interface{}{
+					"volume1": map[string]interface{}{
+						"containerImage": cinderv1.CinderVolumeContainerImage,
+						"replication": map[string]interface{}{
+							"devices": []interface{}{
+								map[string]interface{}{
+									"backendID": "secondary",
+									"options": map[string]interface{}{
+										"san_ip":    "192.168.1.2",
+										"san_login": "admin",
+									},
+								},
+							},
+						},
+					},
+				},
+			}
+
+			setupCinderDeps(rawSpec)
+			keystone.SimulateKeystoneEndpointReady(cinderTest.CinderKeystoneEndpoint)
+			th.SimulateStatefulSetReplicaReady(cinderTest.CinderAPI)
+			th.SimulateStatefulSetReplicaReady(cinderTest.CinderScheduler)
+			th.SimulateStatefulSetReplicaReady(cinderTest.CinderVolumes[0])
+		})
+
+		It("renders the replication devices and runs on the primary", func() {
+			volume := cinderTest.CinderVolumes[0]
+			cf := th.GetSecret(types.NamespacedName{
+				Namespace: volume.Namespace,
+				Name:      volume.Name + "-config-data",
+			})
+			Expect(string(cf.Data[cinder.ServiceConfigFileName])).To(ContainSubstring(
+				"replication_device = backend_id:secondary,san_ip:192.168.1.2,san_login:admin"))
+
+			Expect(GetCinderVolume(volume).Status.ActiveBackendID).To(BeEmpty())
+			th.AssertJobDoesNotExist(types.NamespacedName{
+				Namespace: volume.Namespace,
+				Name:      volume.Name + "-failover",
+			})
+		})
+
+		It("runs the failover job and records the active backend", func() {
+			volume := cinderTest.CinderVolumes[0]
+			failoverJob := types.NamespacedName{
+				Namespace: volume.Namespace,
+				Name:      volume.Name + "-failover",
+			}
+
+			Eventually(func(g Gomega) {
+				cinder := GetCinder(cinderTest.Instance)
+				volumeSpec := cinder.Spec.CinderVolumes["volume1"]
+				volumeSpec.Failover = "secondary"
+				cinder.Spec.CinderVolumes["volume1"] = volumeSpec
+				g.Expect(k8sClient.Update(ctx, cinder)).To(Succeed())
+			}, timeout, interval).Should(Succeed())
+
+			// The backends stay on the primary until the job completes
+			th.ExpectConditionWithDetails(
+				volume,
+				ConditionGetterFunc(CinderVolumeConditionGetter),
+				cinderv1.CinderVolumeFailoverReadyCondition,
+				corev1.ConditionFalse,
+				condition.RequestedReason,
+				"CinderVolume failover to secondary in progress",
+			)
+			Expect(GetCinderVolume(volume).Status.ActiveBackendID).To(BeEmpty())
+
+			job := th.GetJob(failoverJob)
+			container := job.Spec.Template.Spec.Containers[0]
+			Expect(container.Command).To(Equal([]string{
+				"/usr/local/bin/container-scripts/failover.py",
+				"secondary",
+				"/etc/cinder/cinder.conf.d",
+			}))
+			Expect(container.Image).To(Equal(cinderv1.CinderVolumeContainerImage))
+
+			th.SimulateJobSuccess(failoverJob)
+			th.ExpectConditionWithDetails(
+				volume,
+				ConditionGetterFunc(CinderVolumeConditionGetter),
+				cinderv1.CinderVolumeFailoverReadyCondition,
+				corev1.ConditionTrue,
+				condition.ReadyReason,
+				"CinderVolume backends running on secondary",
+			)
+			Expect(GetCinderVolume(volume).Status.ActiveBackendID).To(Equal("secondary"))
+
+			// Failing back runs the job again with the primary as target
+			Eventually(func(g Gomega) {
+				cinder := GetCinder(cinderTest.Instance)
+				volumeSpec := cinder.Spec.CinderVolumes["volume1"]
+				volumeSpec.Failover = cinderv1.FailoverPrimary
+				cinder.Spec.CinderVolumes["volume1"] = volumeSpec
+				g.Expect(k8sClient.Update(ctx, cinder)).To(Succeed())
+			}, timeout, interval).Should(Succeed())
+
+			Eventually(func(g Gomega) {
+				command := th.GetJob(failoverJob).Spec.Template.Spec.Containers[0].Command
+				g.Expect(command).To(ContainElement(cinderv1.FailoverPrimary))
+			}, timeout, interval).Should(Succeed())
+			th.SimulateJobSuccess(failoverJob)
+			th.ExpectConditionWithDetails(
+				volume,
+				ConditionGetterFunc(CinderVolumeConditionGetter),
+				cinderv1.CinderVolumeFailoverReadyCondition,
+				corev1.ConditionTrue,
+				condition.ReadyReason,
+				"CinderVolume backends running on primary",
+			)
+			Expect(GetCinderVolume(volume).Status.ActiveBackendID).To(BeEmpty())
+		})
+	})
+	When("Cinder CR instance is built with an LVM backend", func() {
+		BeforeEach(func() {
+			rawSpec := map[string]interface{}{
+				"secret":              SecretName,
+				"databaseInstance":    "openstack",
+				"rabbitMqClusterName": "rabbitmq",
+				"cinderAPI": map[string]interface{}{
+					"containerImage": cinderv1.CinderAPIContainerImage,
+				},
+				"cinderScheduler": map[string]interface{}{
+					"containerImage": cinderv1.CinderSchedulerContainerImage,
+				},
+				"cinderVolumes": map[string]interface{}{
+					"volume1": map[string]interface{}{
+						"containerImage": cinderv1.CinderVolumeContainerImage,
+						"nodeSelector": map[string]interface{}{
+							"foo": "bar",
+						},
+						"lvm": map[string]interface{}{
+							"nodeName":        "worker-0",
+							"loopFileSize":    "10Gi",
+							"targetIPAddress": "192.168.122.10",
+						},
+					},
+				},
+			}
+
+			setupCinderDeps(rawSpec)
+			keystone.SimulateKeystoneEndpointReady(cinderTest.CinderKeystoneEndpoint)
+			th.SimulateStatefulSetReplicaReady(cinderTest.CinderAPI)
+			th.SimulateStatefulSetReplicaReady(cinderTest.CinderScheduler)
+		})
+
+		It("renders the LVM backend configuration", func() {
+			volume := cinderTest.CinderVolumes[0]
+			cf := th.GetSecret(types.NamespacedName{
+				Namespace: volume.Namespace,
+				Name:      volume.Name + "-config-data",
+			})
+			conf := string(cf.Data[cinder.ServiceConfigFileName])
+			Expect(conf).To(ContainSubstring("enabled_backends = volume1"))
+			Expect(conf).To(ContainSubstring("[volume1]"))
+			Expect(conf).To(ContainSubstring("volume_driver = cinder.volume.drivers.lvm.LVMVolumeDriver"))
+			Expect(conf).To(ContainSubstring("volume_group = cinder-volumes"))
+			Expect(conf).To(ContainSubstring("target_helper = lioadm"))
+			Expect(conf).To(ContainSubstring("target_protocol = iscsi"))
+			Expect(conf).To(ContainSubstring("target_ip_address = 192.168.122.10"))
+		})
+
+		It("creates the volume group on its node before deploying the service", func() {
+			volume := cinderTest.CinderVolumes[0]
+			lvmJob := types.NamespacedName{
+				Namespace: volume.Namespace,
+				Name:      volume.Name + "-lvm-init",
+			}
+			expectedCommand := []string{
+				"/usr/local/bin/container-scripts/lvm-init.sh",
+				"cinder-volumes",
+				"10737418240",
+			}
+			expectedNodeSelector := map[string]string{
+				"foo":                    "bar",
+				"kubernetes.io/hostname": "worker-0",
+			}
+
+			job := th.GetJob(lvmJob)
+			Expect(job.Spec.Template.Spec.HostPID).To(BeTrue())
+			Expect(job.Spec.Template.Spec.NodeSelector).To(Equal(expectedNodeSelector))
+			Expect(job.Spec.Template.Spec.Containers[0].Command).To(Equal(expectedCommand))
+			th.ExpectCondition(
+				volume,
+				ConditionGetterFunc(CinderVolumeConditionGetter),
+				cinderv1.CinderVolumeLVMReadyCondition,
+				corev1.ConditionFalse,
+			)
+			th.AssertStatefulSetDoesNotExist(volume)
+
+			th.SimulateJobSuccess(lvmJob)
+			th.ExpectConditionWithDetails(
+				volume,
+				ConditionGetterFunc(CinderVolumeConditionGetter),
+				cinderv1.CinderVolumeLVMReadyCondition,
+				corev1.ConditionTrue,
+				condition.ReadyReason,
+				"CinderVolume LVM volume group cinder-volumes ready on node worker-0",
+			)
+			Expect(GetCinderVolume(volume).Status.LVM.NodeName).To(Equal("worker-0"))
+
+			// The service runs on the node of the volume group and attaches
+			// the loop device again when the node reboots
+			ss := th.GetStatefulSet(volume)
+			Expect(ss.Spec.Template.Spec.NodeSelector).To(Equal(expectedNodeSelector))
+			Expect(ss.Spec.Template.Spec.InitContainers).To(ContainElement(And(
+				HaveField("Name", "lvm-loop"),
+				HaveField("Command", expectedCommand))))
+		})
+	})
+	When("Cinder CR instance is built with host prerequisites", func() {
+		BeforeEach(func() {
+			rawSpec := map[string]interface{}{
+				"secret":              SecretName,
+				"databaseInstance":    "openstack",
+				"rabbitMqClusterName": "rabbitmq",
+				"cinderAPI": map[string]interface{}{
+					"containerImage": cinderv1.CinderAPIContainerImage,
+				},
+				"cinderScheduler": map[string]interface{}{
+					"containerImage": cinderv1.CinderSchedulerContainerImage,
+				},
+				"cinderVolumes": map[string]interface{}{
+					"volume1": map[string]interface{}{
+						"containerImage":    cinderv1.CinderVolumeContainerImage,
+						"hostPrerequisites": []interface{}{"iscsi", "multipath"},
+						"topologyRef": map[string]interface{}{
+							"name": cinderTest.CinderTopologies[0].Name,
+						},
+					},
+				},
+			}
+
+			topologySpec, _ := GetSampleTopologySpec(cinderTest.CinderTopologies[0].Name)
+			topologySpec["affinity"] = map[string]interface{}{
+				"nodeAffinity": map[string]interface{}{
+					"requiredDuringSchedulingIgnoredDuringExecution": map[string]interface{}{
+						"nodeSelectorTerms": []interface{}{
+							map[string]interface{}{
+								"matchExpressions": []interface{}{
+									map[string]interface{}{
+										"key":      "storage",
+										"operator": "In",
+										"values":   []interface{}{"true"},
+									},
+								},
+							},
+						},
+					},
+				},
+			}
+			infra.CreateTopology(cinderTest.CinderTopologies[0], topologySpec)
+			setupCinderDeps(rawSpec)
+			keystone.SimulateKeystoneEndpointReady(cinderTest.CinderKeystoneEndpoint)
+		})
+
+		It("verifies the nodes before deploying the volume service", func() {
+			volume := cinderTest.CinderVolumes[0]
+			th.ExpectCondition(
+				volume,
+				ConditionGetterFunc(CinderVolumeConditionGetter),
+				cinderv1.CinderVolumeHostPrerequisitesReadyCondition,
+				corev1.ConditionFalse,
+			)
+
+			ds := &appsv1.DaemonSet{}
+			Eventually(func(g Gomega) {
+				g.Expect(k8sClient.Get(ctx, types.NamespacedName{
+					Namespace: volume.Namespace,
+					Name:      volume.Name + "-host-preflight",
+				}, ds)).Should(Succeed())
+			}, timeout, interval).Should(Succeed())
+			container := ds.Spec.Template.Spec.Containers[0]
+			Expect(container.Command).To(Equal([]string{
+				"/usr/local/bin/container-scripts/host-preflight.sh", "300", "iscsi", "multipath"}))
+			Expect(ds.Spec.Template.Spec.HostPID).To(BeTrue())
+			// Only the nodes the topology allows are verified
+			Expect(ds.Spec.Template.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms).To(
+				ConsistOf(HaveField("MatchExpressions", ConsistOf(HaveField("Key", "storage")))))
+			Expect(ds.Spec.Template.Spec.TopologySpreadConstraints).To(BeEmpty())
+
+			// The service waits until there is a pod on every node
+			th.AssertStatefulSetDoesNotExist(volume)
+		})
+
+		It("deploys the service on the verified nodes while others are pending", func() {
+			volume := cinderTest.CinderVolumes[0]
+			dsName := types.NamespacedName{
+				Namespace: volume.Namespace,
+				Name:      volume.Name + "-host-preflight",
+			}
+			ds := &appsv1.DaemonSet{}
+			Eventually(func(g Gomega) {
+				g.Expect(k8sClient.Get(ctx, dsName, ds)).Should(Succeed())
+			}, timeout, interval).Should(Succeed())
+
+			// node-a passed the verification and node-b's pod hasn't started
+			for _, node := range []string{"node-a", "node-b"} {
+				preflightPod := &corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      dsName.Name + "-" + node,
+						Namespace: dsName.Namespace,
+						Labels:    ds.Spec.Template.Labels,
+					},
+					Spec: corev1.PodSpec{
+						NodeName:   node,
+						Containers: ds.Spec.Template.Spec.Containers,
+					},
+				}
+				Expect(k8sClient.Create(ctx, preflightPod)).Should(Succeed())
+				DeferCleanup(th.DeleteInstance, preflightPod)
+				if node == "node-a" {
+					preflightPod.Status.ContainerStatuses = []corev1.ContainerStatus{{
+						Name:  ds.Spec.Template.Spec.Containers[0].Name,
+						Ready: true,
+					}}
+					Expect(k8sClient.Status().Update(ctx, preflightPod)).Should(Succeed())
+				}
+			}
+			Eventually(func(g Gomega) {
+				g.Expect(k8sClient.Get(ctx, dsName, ds)).Should(Succeed())
+				ds.Status.ObservedGeneration = ds.Generation
+				ds.Status.DesiredNumberScheduled = 2
+				ds.Status.UpdatedNumberScheduled = 2
+				g.Expect(k8sClient.Status().Update(ctx, ds)).Should(Succeed())
+			}, timeout, interval).Should(Succeed())
+
+			th.ExpectConditionWithDetails(
+				volume,
+				ConditionGetterFunc(CinderVolumeConditionGetter),
+				cinderv1.CinderVolumeHostPrerequisitesReadyCondition,
+				corev1.ConditionFalse,
+				condition.RequestedReason,
+				"CinderVolume host prerequisites not verified yet on nodes: node-b",
+			)
+
+			// The pending node isn't excluded, so its result doesn't roll the
+			// service
+			ss := th.GetStatefulSet(volume)
+			terms := ss.Spec.Template.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+			Expect(terms).To(HaveLen(1))
+			Expect(terms[0].MatchFields).To(BeEmpty())
+		})
+
+		It("keeps the service off the nodes that failed the verification", func() {
+			volume := cinderTest.CinderVolumes[0]
+			dsName := types.NamespacedName{
+				Namespace: volume.Namespace,
+				Name:      volume.Name + "-host-preflight",
+			}
+			ds := &appsv1.DaemonSet{}
+			Eventually(func(g Gomega) {
+				g.Expect(k8sClient.Get(ctx, dsName, ds)).Should(Succeed())
+			}, timeout, interval).Should(Succeed())
+
+			// node-a passed the verification and node-b failed it
+			for _, node := range []string{"node-a", "node-b"} {
+				preflightPod := &corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      dsName.Name + "-" + node,
+						Namespace: dsName.Namespace,
+						Labels:    ds.Spec.Template.Labels,
+					},
+					Spec: corev1.PodSpec{
+						NodeName:   node,
+						Containers: ds.Spec.Template.Spec.Containers,
+					},
+				}
+				Expect(k8sClient.Create(ctx, preflightPod)).Should(Succeed())
+				DeferCleanup(th.DeleteInstance, preflightPod)
+				status := corev1.ContainerStatus{
+					Name:  ds.Spec.Template.Spec.Containers[0].Name,
+					Ready: node == "node-a",
+				}
+				if node == "node-b" {
+					status.LastTerminationState.Terminated = &corev1.ContainerStateTerminated{
+						ExitCode: 1,
+						Message:  "multipathd is not running",
+					}
+				}
+				preflightPod.Status.ContainerStatuses = []corev1.ContainerStatus{status}
+				Expect(k8sClient.Status().Update(ctx, preflightPod)).Should(Succeed())
+			}
+			Eventually(func(g Gomega) {
+				g.Expect(k8sClient.Get(ctx, dsName, ds)).Should(Succeed())
+				ds.Status.ObservedGeneration = ds.Generation
+				ds.Status.DesiredNumberScheduled = 2
+				ds.Status.UpdatedNumberScheduled = 2
+				g.Expect(k8sClient.Status().Update(ctx, ds)).Should(Succeed())
+			}, timeout, interval).Should(Succeed())
+
+			// The node is matched by name, its hostname label may differ
+			ss := th.GetStatefulSet(volume)
+			terms := ss.Spec.Template.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+			Expect(terms).To(HaveLen(1))
+			Expect(terms[0].MatchFields).To(ConsistOf(corev1.NodeSelectorRequirement{
+				Key:      "metadata.name",
+				Operator: corev1.NodeSelectorOpNotIn,
+				Values:   []string{"node-b"},
+			}))
+			// The affinity of the topology is kept
+			Expect(terms[0].MatchExpressions).To(ConsistOf(HaveField("Key", "storage")))
+		})
+	})
+	When("Cinder CR instance is built with image pinning", func() {
+		BeforeEach(func() {
+			rawSpec := map[string]interface{}{
+				"secret":              SecretName,
+				"databaseInstance":    "openstack",
+				"rabbitMqClusterName": "rabbitmq",
+				"imagePinning": map[string]interface{}{
+					"refresh": "1",
+				},
+				"cinderAPI": map[string]interface{}{
+					"containerImage": cinderv1.CinderAPIContainerImage,
+				},
+				"cinderScheduler": map[string]interface{}{
+					"containerImage": cinderv1.CinderSchedulerContainerImage,
+				},
+			}
+
+			keystoneAPIName := keystone.CreateKeystoneAPI(cinderTest.Instance.Namespace)
+			DeferCleanup(keystone.DeleteKeystoneAPI, keystoneAPIName)
+			createCinderDeps(rawSpec)
+		})
+
+		It("runs the db sync and the db purge with the resolved image", func() {
+			resolveJob := th.GetJob(types.NamespacedName{
+				Namespace: cinderTest.Instance.Namespace,
+				Name:      cinderTest.Instance.Name + "-image-resolve",
+			})
+			Expect(resolveJob.Spec.Template.Spec.Containers[0].Image).To(Equal(cinderv1.CinderAPIContainerImage))
+			th.ExpectCondition(
+				cinderTest.Instance,
+				ConditionGetterFunc(CinderConditionGetter),
+				cinderv1.ImageResolvedCondition,
+				corev1.ConditionFalse,
+			)
+			th.AssertJobDoesNotExist(cinderTest.CinderDBSync)
+
+			pinnedImage := cinder.GetImageRepository(cinderv1.CinderAPIContainerImage) + "@sha256:1234"
+			SimulateImageResolved(
+				types.NamespacedName{
+					Namespace: cinderTest.Instance.Namespace,
+					Name:      cinderTest.Instance.Name + "-image-resolve",
+				},
+				"quay.io/podified-antelope-centos9/openstack-cinder-api@sha256:1234")
+			th.ExpectCondition(
+				cinderTest.Instance,
+				ConditionGetterFunc(CinderConditionGetter),
+				cinderv1.ImageResolvedCondition,
+				corev1.ConditionTrue,
+			)
+
+			Expect(th.GetJob(cinderTest.CinderDBSync).Spec.Template.Spec.Containers[0].Image).To(Equal(pinnedImage))
+			th.SimulateJobSuccess(cinderTest.CinderDBSync)
+			Eventually(func(g Gomega) {
+				cronJob := GetCronJob(cinderTest.CinderDBPurge)
+				g.Expect(cronJob.Spec.JobTemplate.Spec.Template.Spec.Containers[0].Image).To(Equal(pinnedImage))
+			}, timeout, interval).Should(Succeed())
+		})
+
+		It("resolves the image before deploying the service", func() {
+			SimulateImageResolved(
+				types.NamespacedName{
+					Namespace: cinderTest.Instance.Namespace,
+					Name:      cinderTest.Instance.Name + "-image-resolve",
+				},
+				"quay.io/podified-antelope-centos9/openstack-cinder-api@sha256:1234")
+			th.SimulateJobSuccess(cinderTest.CinderDBSync)
+
+			th.ExpectCondition(
+				cinderTest.CinderAPI,
+				ConditionGetterFunc(CinderAPIConditionGetter),
+				cinderv1.ImageResolvedCondition,
+				corev1.ConditionFalse,
+			)
+			resolveJob := th.GetJob(types.NamespacedName{
+				Namespace: cinderTest.CinderAPI.Namespace,
+				Name:      cinderTest.CinderAPI.Name + "-image-resolve",
+			})
+			container := resolveJob.Spec.Template.Spec.Containers[0]
+			Expect(container.Image).To(Equal(cinderv1.CinderAPIContainerImage))
+			Expect(container.ImagePullPolicy).To(Equal(corev1.PullAlways))
+
+			// The service is not deployed with the tag
+			Consistently(func(g Gomega) {
+				ss := &appsv1.StatefulSet{}
+				err := k8sClient.Get(ctx, cinderTest.CinderAPI, ss)
+				g.Expect(k8s_errors.IsNotFound(err)).To(BeTrue())
+			}, timeout, interval).Should(Succeed())
+		})
+	})
+	When("Cinder CR instance is built with driver plugins", func() {
+		BeforeEach(func() {
+			rawSpec := map[string]interface{}{
+				"secret":              SecretName,
+				"databaseInstance":    "openstack",
+				"rabbitMqClusterName": "rabbitmq",
+				"cinderAPI": map[string]interface{}{
+					"containerImage": cinderv1.CinderAPIContainerImage,
+				},
+				"cinderScheduler": map[string]interface{}{
+					"containerImage": cinderv1.CinderSchedulerContainerImage,
+				},
+				"cinderVolumes": map[string]interface{}{
+					"volume1": map[string]interface{}{
+						"containerImage": cinderv1.CinderVolumeContainerImage,
+						"driverPlugins": []interface{}{
+							map[string]interface{}{
+								"name":           "purestorage",
+								"containerImage": "quay.io/example/purestorage-plugin:latest",
+							},
+						},
+					},
+				},
+			}
+
+			setupCinderDeps(rawSpec)
+			keystone.SimulateKeystoneEndpointReady(cinderTest.CinderKeystoneEndpoint)
+		})
+
+		It("adds the driver plugins to the volume service", func() {
+			th.SimulateStatefulSetReplicaReady(cinderTest.CinderAPI)
+			th.SimulateStatefulSetReplicaReady(cinderTest.CinderScheduler)
+			volume := cinderTest.CinderVolumes[0]
+			ss := th.GetStatefulSet(volume)
+
+			Expect(ss.Spec.Template.Spec.InitContainers).To(HaveLen(1))
+			initContainer := ss.Spec.Template.Spec.InitContainers[0]
+			Expect(initContainer.Name).To(Equal("plugin-purestorage"))
+			Expect(initContainer.Image).To(Equal("quay.io/example/purestorage-plugin:latest"))
+			Expect(initContainer.Command).To(Equal([]string{
+				"cp", "-a", "/plugin/.", "/var/lib/cinder-plugins/purestorage"}))
+
+			container := ss.Spec.Template.Spec.Containers[0]
+			Expect(container.Env).To(ContainElement(corev1.EnvVar{
+				Name: "PYTHONPATH", Value: "/var/lib/cinder-plugins/purestorage"}))
+			th.AssertVolumeMountExists("driver-plugins", "", container.VolumeMounts)
+			// The probe container doesn't need them
+			probe := ss.Spec.Template.Spec.Containers[1]
+			Expect(probe.VolumeMounts).To(HaveLen(len(container.VolumeMounts) - 1))
+		})
+	})
+	When("Cinder CR instance is built with Fibre Channel discovery", func() {
+		BeforeEach(func() {
+			rawSpec := map[string]interface{}{
+				"secret":              SecretName,
+				"databaseInstance":    "openstack",
+				"rabbitMqClusterName": "rabbitmq",
+				"cinderAPI": map[string]interface{}{
+					"containerImage": cinderv1.CinderAPIContainerImage,
+				},
+				"cinderScheduler": map[string]interface{}{
+					"containerImage": cinderv1.CinderSchedulerContainerImage,
+				},
+				"cinderVolumes": map[string]interface{}{
+					"volume1": map[string]interface{}{
+						"containerImage": cinderv1.CinderVolumeContainerImage,
+						"fibreChannel": map[string]interface{}{
+							"required": true,
+						},
+					},
+				},
+			}
+
+			setupCinderDeps(rawSpec)
+			keystone.SimulateKeystoneEndpointReady(cinderTest.CinderKeystoneEndpoint)
+		})
+
+		It("discovers the FC HBAs before deploying the volume service", func() {
+			volume := cinderTest.CinderVolumes[0]
+			th.ExpectCondition(
+				volume,
+				ConditionGetterFunc(CinderVolumeConditionGetter),
+				cinderv1.FibreChannelReadyCondition,
+				corev1.ConditionFalse,
+			)
+
+			ds := &appsv1.DaemonSet{}
+			Eventually(func(g Gomega) {
+				g.Expect(k8sClient.Get(ctx, types.NamespacedName{
+					Namespace: volume.Namespace,
+					Name:      volume.Name + "-fc-discovery",
+				}, ds)).Should(Succeed())
+			}, timeout, interval).Should(Succeed())
+			container := ds.Spec.Template.Spec.InitContainers[0]
+			Expect(container.Command).To(Equal([]string{
+				"/usr/local/bin/container-scripts/fc-discovery.sh"}))
+			Expect(ds.Spec.Template.Spec.Volumes[1].HostPath.Path).To(Equal("/sys"))
+
+			// The service waits until there is a discovery pod on every node
+			th.AssertStatefulSetDoesNotExist(volume)
+		})
+
+		It("reports the nodes where the discovery failed and deploys the service", func() {
+			volume := cinderTest.CinderVolumes[0]
+			dsName := types.NamespacedName{
+				Namespace: volume.Namespace,
+				Name:      volume.Name + "-fc-discovery",
+			}
+			ds := &appsv1.DaemonSet{}
+			Eventually(func(g Gomega) {
+				g.Expect(k8sClient.Get(ctx, dsName, ds)).Should(Succeed())
+			}, timeout, interval).Should(Succeed())
+
+			// The discovery fails on node-a and hasn't completed on node-b
+			for _, node := range []string{"node-a", "node-b"} {
+				discoveryPod := &corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      dsName.Name + "-" + node,
+						Namespace: dsName.Namespace,
+						Labels:    ds.Spec.Template.Labels,
+					},
+					Spec: corev1.PodSpec{
+						NodeName:       node,
+						InitContainers: ds.Spec.Template.Spec.InitContainers,
+						Containers:     ds.Spec.Template.Spec.Containers,
+					},
+				}
+				Expect(k8sClient.Create(ctx, discoveryPod)).Should(Succeed())
+				DeferCleanup(th.DeleteInstance, discoveryPod)
+				if node == "node-a" {
+					discoveryPod.Status.InitContainerStatuses = []corev1.ContainerStatus{{
+						Name: ds.Spec.Template.Spec.InitContainers[0].Name,
+						LastTerminationState: corev1.ContainerState{
+							Terminated: &corev1.ContainerStateTerminated{
+								ExitCode: 1,
+								Message:  "cannot read /host/sys/class/fc_host",
+							},
+						},
+					}}
+					Expect(k8sClient.Status().Update(ctx, discoveryPod)).Should(Succeed())
+				}
+			}
+			Eventually(func(g Gomega) {
+				g.Expect(k8sClient.Get(ctx, dsName, ds)).Should(Succeed())
+				ds.Status.ObservedGeneration = ds.Generation
+				ds.Status.DesiredNumberScheduled = 2
+				ds.Status.UpdatedNumberScheduled = 2
+				g.Expect(k8sClient.Status().Update(ctx, ds)).Should(Succeed())
+			}, timeout, interval).Should(Succeed())
+
+			th.ExpectConditionWithDetails(
+				volume,
+				ConditionGetterFunc(CinderVolumeConditionGetter),
+				cinderv1.FibreChannelReadyCondition,
+				corev1.ConditionFalse,
+				condition.ErrorReason,
+				"Fibre Channel discovery failed on nodes: node-a (cannot read /host/sys/class/fc_host)",
+			)
+			Expect(GetCinderVolume(volume).Status.FibreChannel).To(ConsistOf(cinderv1.FibreChannelNodeStatus{
+				NodeName: "node-a",
+				Error:    "cannot read /host/sys/class/fc_host",
+			}))
+
+			// The service only runs on the nodes that have FC HBAs
+			ss := th.GetStatefulSet(volume)
+			Expect(ss.Spec.Template.Spec.NodeSelector).To(
+				HaveKeyWithValue(cinder.FibreChannelNodeLabel, "true"))
+		})
+	})
+	When("Cinder CR instance is built with availability zones", func() {
+		BeforeEach(func() {
+			for _, zone := range []string{"az1", "az2"} {
+				node := &corev1.Node{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:   "node-" + zone,
+						Labels: map[string]string{corev1.LabelTopologyZone: zone},
+					},
+				}
+				Expect(k8sClient.Create(ctx, node)).Should(Succeed())
+				DeferCleanup(k8sClient.Delete, ctx, node)
+			}
+
+			rawSpec := map[string]interface{}{
+				"secret":              SecretName,
+				"databaseInstance":    "openstack",
+				"rabbitMqClusterName": "rabbitmq",
+				"cinderAPI": map[string]interface{}{
+					"containerImage": cinderv1.CinderAPIContainerImage,
+				},
+				"cinderScheduler": map[string]interface{}{
+					"containerImage": cinderv1.CinderSchedulerContainerImage,
+					"availabilityZoneFilter": map[string]interface{}{
+						"defaultAvailabilityZone": "az1",
+					},
+				},
+				"cinderVolumes": map[string]interface{}{
+					"volume1": map[string]interface{}{
+						"containerImage": cinderv1.CinderVolumeContainerImage,
+						"nodeSelector": map[string]interface{}{
+							corev1.LabelTopologyZone: "az1",
+						},
+						"availabilityZone": map[string]interface{}{},
+					},
+					"volume2": map[string]interface{}{
+						"containerImage":   cinderv1.CinderVolumeContainerImage,
+						"availabilityZone": map[string]interface{}{},
+					},
+				},
+			}
+
+			setupCinderDeps(rawSpec)
+			keystone.SimulateKeystoneEndpointReady(cinderTest.CinderKeystoneEndpoint)
+		})
+
+		It("sets the zone of the back-end to the zone of its nodes", func() {
+			volume := cinderTest.CinderVolumes[0]
+			th.ExpectCondition(
+				volume,
+				ConditionGetterFunc(CinderVolumeConditionGetter),
+				cinderv1.AvailabilityZoneReadyCondition,
+				corev1.ConditionTrue,
+			)
+			Expect(GetCinderVolume(volume).Status.AvailabilityZone).To(Equal("az1"))
+			cf := th.GetSecret(types.NamespacedName{
+				Namespace: volume.Namespace,
+				Name:      volume.Name + "-config-data",
+			})
+			Expect(string(cf.Data[cinder.ServiceConfigFileName])).To(
+				ContainSubstring("backend_availability_zone = az1"))
+
+			conf := string(th.GetSecret(cinderTest.CinderConfigSecret).Data[cinder.DefaultsConfigFileName])
+			Expect(conf).To(ContainSubstring("default_availability_zone = az1"))
+			Expect(conf).To(ContainSubstring("allow_availability_zone_fallback = false"))
+		})
+
+		It("doesn't deploy a back-end whose nodes span several zones", func() {
+			volume := cinderTest.CinderVolumes[1]
+			th.ExpectConditionWithDetails(
+				volume,
+				ConditionGetterFunc(CinderVolumeConditionGetter),
+				cinderv1.AvailabilityZoneReadyCondition,
+				corev1.ConditionFalse,
+				condition.ErrorReason,
+				"Service can run on nodes of several availability zones: az1, az2",
+			)
+			th.AssertStatefulSetDoesNotExist(volume)
+		})
+
+		It("follows the zone of its nodes when they are relabeled", func() {
+			volume := cinderTest.CinderVolumes[1]
+			th.ExpectCondition(
+				volume,
+				ConditionGetterFunc(CinderVolumeConditionGetter),
+				cinderv1.AvailabilityZoneReadyCondition,
+				corev1.ConditionFalse,
+			)
+
+			Eventually(func(g Gomega) {
+				node := &corev1.Node{}
+				g.Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "node-az2"}, node)).Should(Succeed())
+				node.Labels[corev1.LabelTopologyZone] = "az1"
+				g.Expect(k8sClient.Update(ctx, node)).Should(Succeed())
+			}, timeout, interval).Should(Succeed())
+
+			th.ExpectCondition(
+				volume,
+				ConditionGetterFunc(CinderVolumeConditionGetter),
+				cinderv1.AvailabilityZoneReadyCondition,
+				corev1.ConditionTrue,
+			)
+			Expect(GetCinderVolume(volume).Status.AvailabilityZone).To(Equal("az1"))
+		})
+	})
+	When("Cinder CR instance is built with a scheduler policy", func() {
+		BeforeEach(func() {
+			rawSpec := map[string]interface{}{
+				"secret":              SecretName,
+				"databaseInstance":    "openstack",
+				"rabbitMqClusterName": "rabbitmq",
+				"cinderAPI": map[string]interface{}{
+					"containerImage": cinderv1.CinderAPIContainerImage,
+				},
+				"cinderScheduler": map[string]interface{}{
+					"containerImage": cinderv1.CinderSchedulerContainerImage,
+					"schedulerPolicy": map[string]interface{}{
+						"weighers":                 []interface{}{"GoodnessWeigher", "CapacityWeigher"},
+						"capacityWeightMultiplier": "0.5",
+						"stochastic":               true,
+						"driverInitWaitTime":       60,
+						"backends": map[string]interface{}{
+							"volume1": map[string]interface{}{
+								"goodnessFunction": "100 - capabilities.allocated_capacity_gb / 10",
+							},
+						},
+					},
+				},
+				"cinderVolumes": map[string]interface{}{
+					"volume1": map[string]interface{}{
+						"containerImage": cinderv1.CinderVolumeContainerImage,
+					},
+				},
+			}
+
+			setupCinderDeps(rawSpec)
+			keystone.SimulateKeystoneEndpointReady(cinderTest.CinderKeystoneEndpoint)
+		})
+
+		It("renders the policy in the scheduler and volume configuration", func() {
+			cf := th.GetSecret(types.NamespacedName{
+				Namespace: cinderTest.CinderScheduler.Namespace,
+				Name:      cinderTest.CinderScheduler.Name + "-config-data",
+			})
+			conf := string(cf.Data[cinder.ServiceConfigFileName])
+			Expect(conf).To(ContainSubstring("scheduler_default_weighers = GoodnessWeigher,CapacityWeigher"))
+			Expect(conf).To(ContainSubstring("capacity_weight_multiplier = 0.5"))
+			Expect(conf).To(ContainSubstring(
+				"scheduler_weight_handler = cinder.scheduler.weights.stochastic.stochasticHostWeightHandler"))
+			Expect(conf).To(ContainSubstring("scheduler_driver_init_wait_time = 60"))
+			Expect(conf).ToNot(ContainSubstring("scheduler_default_filters"))
+
+			volume := cinderTest.CinderVolumes[0]
+			cf = th.GetSecret(types.NamespacedName{
+				Namespace: volume.Namespace,
+				Name:      volume.Name + "-config-data",
+			})
+			Expect(string(cf.Data[cinder.ServiceConfigFileName])).To(ContainSubstring(
+				"goodness_function = \"100 - capabilities.allocated_capacity_gb / 10\""))
+		})
+	})
+	When("Cinder CR instance is built with notifications on a dedicated cluster", func() {
+		BeforeEach(func() {
+			spec := GetDefaultCinderSpec()
+			spec["notifications"] = map[string]interface{}{
+				"enabled":             true,
+				"topics":              []interface{}{"notifications", "audit"},
+				"rabbitMqClusterName": "rabbitmq-notifications",
+			}
+
+			DeferCleanup(th.DeleteInstance, CreateCinder(cinderTest.Instance, spec))
+			DeferCleanup(k8sClient.Delete, ctx, CreateCinderMessageBusSecret(cinderTest.Instance.Namespace, cinderTest.RabbitmqSecretName))
+			DeferCleanup(k8sClient.Delete, ctx, CreateCinderMessageBusSecret(cinderTest.Instance.Namespace, "rabbitmq-notifications-secret"))
+			DeferCleanup(
+				mariadb.DeleteDBService,
+				mariadb.CreateDBService(
+					cinderTest.Instance.Namespace,
+					GetCinder(cinderTest.Instance).Spec.DatabaseInstance,
+					corev1.ServiceSpec{
+						Ports: []corev1.ServicePort{{Port: 3306}},
+					},
+				),
+			)
+			infra.SimulateTransportURLReady(cinderTest.CinderTransportURL)
+			infra.SimulateTransportURLReady(cinderTest.CinderNotificationURL)
+			DeferCleanup(infra.DeleteMemcached, infra.CreateMemcached(namespace, cinderTest.MemcachedInstance, memcachedSpec))
+			infra.SimulateMemcachedReady(cinderTest.CinderMemcached)
+			keystoneAPIName := keystone.CreateKeystoneAPI(cinderTest.Instance.Namespace)
+			DeferCleanup(keystone.DeleteKeystoneAPI, keystoneAPIName)
+			mariadb.SimulateMariaDBAccountCompleted(cinderTest.Database)
+			mariadb.SimulateMariaDBDatabaseCompleted(cinderTest.Database)
+		})
+
+		It("sends the notifications to the dedicated cluster", func() {
+			th.ExpectCondition(
+				cinderTest.Instance,
+				ConditionGetterFunc(CinderConditionGetter),
+				cinderv1.NotificationTransportURLReadyCondition,
+				corev1.ConditionTrue,
+			)
+			Expect(GetCinder(cinderTest.Instance).Status.NotificationURLSecret).To(
+				Equal("rabbitmq-notifications-secret"))
+
+			conf := string(th.GetSecret(cinderTest.CinderConfigSecret).Data[cinder.DefaultsConfigFileName])
+			Expect(conf).To(ContainSubstring(
+				"[oslo_messaging_notifications]\n" +
+					"transport_url = rabbit://rabbitmq-notifications-secret/fake\n" +
+					"driver = messagingv2\n" +
+					"topics = notifications,audit"))
+		})
+
+		It("removes the dedicated TransportURL when notifications are disabled", func() {
+			infra.GetTransportURL(cinderTest.CinderNotificationURL)
+
+			Eventually(func(g Gomega) {
+				instance := GetCinder(cinderTest.Instance)
+				instance.Spec.Notifications.Enabled = false
+				g.Expect(k8sClient.Update(ctx, instance)).To(Succeed())
+			}, timeout, interval).Should(Succeed())
+
+			infra.AssertTransportURLDoesNotExist(cinderTest.CinderNotificationURL)
+			Eventually(func(g Gomega) {
+				conf := string(th.GetSecret(cinderTest.CinderConfigSecret).Data[cinder.DefaultsConfigFileName])
+				g.Expect(conf).To(ContainSubstring("driver = noop"))
+			}, timeout, interval).Should(Succeed())
+		})
+	})
+	When("Cinder CR instance is built with the API audit records written to a file", func() {
+		BeforeEach(func() {
+			spec := GetDefaultCinderSpec()
+			apiSpec := GetDefaultCinderAPISpec()
+			apiSpec["audit"] = map[string]interface{}{
+				"output":        "file",
+				"ignoreReqList": []interface{}{"GET", "HEAD"},
+			}
+			spec["cinderAPI"] = apiSpec
+
+			setupCinderDeps(spec)
+			keystone.SimulateKeystoneEndpointReady(cinderTest.CinderKeystoneEndpoint)
+		})
+
+		It("adds the audit filter and its sidecar to the API", func() {
+			cf := th.GetSecret(types.NamespacedName{
+				Namespace: cinderTest.CinderAPI.Namespace,
+				Name:      cinderTest.CinderAPI.Name + "-config-data",
+			})
+			conf := string(cf.Data[cinder.ServiceConfigFileName])
+			Expect(conf).To(ContainSubstring("api_paste_config = /etc/cinder/cinder.conf.d/api-paste.ini"))
+			Expect(conf).To(ContainSubstring("log_config_append = /etc/cinder/cinder.conf.d/api-logging.ini"))
+			Expect(conf).To(ContainSubstring("[audit_middleware_notifications]\ndriver = log"))
+
+			paste := string(cf.Data[cinderapi.PasteFileName])
+			Expect(paste).To(ContainSubstring("authtoken keystonecontext audit apiv3"))
+			Expect(paste).To(ContainSubstring("ignore_req_list = GET, HEAD"))
+			Expect(cf.Data).To(HaveKey(cinderapi.AuditMapFileName))
+
+			containers := th.GetStatefulSet(cinderTest.CinderAPI).Spec.Template.Spec.Containers
+			Expect(containers).To(HaveLen(3))
+			Expect(containers[2].Name).To(Equal(cinderTest.CinderAPI.Name + "-audit"))
+			Expect(containers[2].Args[4]).To(ContainSubstring(cinderapi.AuditLogFile))
+		})
+	})
+	When("Cinder CR instance is built with inline API policy rules", func() {
+		BeforeEach(func() {
+			spec := GetDefaultCinderSpec()
+			apiSpec := GetDefaultCinderAPISpec()
+			apiSpec["policy"] = map[string]interface{}{
+				"rules":      "\"volume:delete\": \"rule:admin_api\"\n",
+				"secureRBAC": false,
+			}
+			spec["cinderAPI"] = apiSpec
+
+			setupCinderDeps(spec)
+			keystone.SimulateKeystoneEndpointReady(cinderTest.CinderKeystoneEndpoint)
+		})
+
+		It("adds the policy file to the API configuration", func() {
+			cf := th.GetSecret(types.NamespacedName{
+				Namespace: cinderTest.CinderAPI.Namespace,
+				Name:      cinderTest.CinderAPI.Name + "-config-data",
+			})
+			conf := string(cf.Data[cinder.ServiceConfigFileName])
+			Expect(conf).To(ContainSubstring(
+				"[oslo_policy]\n" +
+					"enforce_scope = false\n" +
+					"enforce_new_defaults = false\n" +
+					"policy_file = " + cinderapi.PolicyFile))
+			Expect(string(cf.Data[cinderapi.PolicyFileName])).To(
+				Equal("\"volume:delete\": \"rule:admin_api\"\n"))
+		})
+	})
+	When("Cinder CR instance is built with mutual TLS on the internal network", func() {
+		BeforeEach(func() {
+			spec := GetTLSCinderSpec()
+			apiSpec := GetTLSCinderAPISpec()
+			apiSpec["internalClientAuth"] = map[string]interface{}{
+				"caBundleSecretName":   CABundleSecretName,
+				"clientCertSecretName": InternalCertSecretName,
+			}
+			spec["cinderAPI"] = apiSpec
+			spec["cinderVolumes"] = map[string]interface{}{
+				"volume1": GetDefaultCinderVolumeSpec(),
+			}
+
+			setupCinderDeps(spec)
+			DeferCleanup(k8sClient.Delete, ctx, th.CreateCABundleSecret(cinderTest.CABundleSecret))
+			DeferCleanup(k8sClient.Delete, ctx, th.CreateCertSecret(cinderTest.InternalCertSecret))
+			DeferCleanup(k8sClient.Delete, ctx, th.CreateCertSecret(cinderTest.PublicCertSecret))
+			keystone.SimulateKeystoneServiceReady(cinderTest.CinderKeystoneService)
+			keystone.SimulateKeystoneEndpointReady(cinderTest.CinderKeystoneEndpoint)
+		})
+
+		It("requires client certificates on the internal endpoint", func() {
+			cf := th.GetSecret(cinderTest.CinderConfigSecret)
+			wsgi := string(cf.Data["10-cinder_wsgi.conf"])
+			Expect(wsgi).To(ContainSubstring(
+				"SSLCACertificateFile    \"" + cinder.APICertsPath + "/client-ca/tls-ca-bundle.pem\"\n" +
+					"  SSLVerifyClient         optional"))
+			Expect(wsgi).To(ContainSubstring("Require expr %{SSL_CLIENT_VERIFY} == 'SUCCESS'"))
+			Expect(strings.Count(wsgi, "SSLVerifyClient")).To(Equal(1))
+
+			th.ExpectCondition(
+				cinderTest.CinderAPI,
+				ConditionGetterFunc(CinderAPIConditionGetter),
+				condition.TLSInputReadyCondition,
+				corev1.ConditionTrue,
+			)
+			ss := th.GetStatefulSet(cinderTest.CinderAPI)
+			th.AssertVolumeExists("client-ca-bundle", ss.Spec.Template.Spec.Volumes)
+			th.AssertVolumeExists("client-certs", ss.Spec.Template.Spec.Volumes)
+			th.AssertVolumeMountExists("client-ca-bundle", "", ss.Spec.Template.Spec.Containers[2].VolumeMounts)
+		})
+
+		It("presents the client certificate to Nova and Glance", func() {
+			conf := string(th.GetSecret(cinderTest.CinderConfigSecret).Data[cinder.DefaultsConfigFileName])
+			Expect(conf).To(ContainSubstring("glance_certfile = " + cinder.ClientCertPath + "/tls.crt"))
+			Expect(conf).To(ContainSubstring("glance_keyfile = " + cinder.ClientCertPath + "/tls.key"))
+			Expect(conf).To(ContainSubstring(
+				"project_domain_name = Default\n" +
+					"certfile = " + cinder.ClientCertPath + "/tls.crt\n" +
+					"keyfile = " + cinder.ClientCertPath + "/tls.key\n\n[service_user]"))
+
+			Eventually(func(g Gomega) {
+				volume := GetCinderVolume(cinderTest.CinderVolumes[0])
+				g.Expect(volume.Spec.ClientCertSecretName).To(Equal(InternalCertSecretName))
+			}, timeout, interval).Should(Succeed())
+			ss := th.GetStatefulSet(cinderTest.CinderVolumes[0])
+			th.AssertVolumeExists("client-certs", ss.Spec.Template.Spec.Volumes)
+			th.AssertVolumeMountExists("client-certs", "", ss.Spec.Template.Spec.Containers[0].VolumeMounts)
+		})
+	})
+	When("Cinder CR instance is built with API rate limits", func() {
+		BeforeEach(func() {
+			spec := GetDefaultCinderSpec()
+			apiSpec := GetDefaultCinderAPISpec()
+			apiSpec["rateLimit"] = map[string]interface{}{
+				"maxRequestBodySize": 1048576,
+				"limits": []interface{}{
+					map[string]interface{}{
+						"verb":  "POST",
+						"value": 10,
+					},
+					map[string]interface{}{
+						"verb":  "GET",
+						"regex": "^/volumes",
+						"value": 100,
+						"unit":  "SECOND",
+					},
+				},
+			}
+			spec["cinderAPI"] = apiSpec
+
+			setupCinderDeps(spec)
+			keystone.SimulateKeystoneEndpointReady(cinderTest.CinderKeystoneEndpoint)
+		})
+
+		It("adds the rate limiting filter and the request size limits", func() {
+			cf := th.GetSecret(types.NamespacedName{
+				Namespace: cinderTest.CinderAPI.Namespace,
+				Name:      cinderTest.CinderAPI.Name + "-config-data",
+			})
+			conf := string(cf.Data[cinder.ServiceConfigFileName])
+			Expect(conf).To(ContainSubstring("api_paste_config = /etc/cinder/cinder.conf.d/api-paste.ini"))
+			Expect(conf).To(ContainSubstring("[oslo_middleware]\nmax_request_body_size = 1048576"))
+
+			paste := string(cf.Data[cinderapi.PasteFileName])
+			Expect(paste).To(ContainSubstring("authtoken keystonecontext ratelimit apiv3"))
+			Expect(paste).To(ContainSubstring("osprofiler noauth ratelimit apiv3"))
+			Expect(paste).To(ContainSubstring("osprofiler noauth_include_project_id ratelimit apiv3"))
+			Expect(paste).To(ContainSubstring(
+				"limits = (POST, \"*\", .*, 10, MINUTE);(GET, \"*\", ^/volumes, 100, SECOND)"))
+
+			Eventually(func(g Gomega) {
+				wsgi := string(th.GetSecret(cinderTest.CinderConfigSecret).Data["10-cinder_wsgi.conf"])
+				g.Expect(wsgi).To(ContainSubstring("LimitRequestBody 1048576"))
+			}, timeout, interval).Should(Succeed())
+		})
+	})
+	When("Cinder CR instance is built for an IPv6-only deployment", func() {
+		BeforeEach(func() {
+			nad := th.CreateNetworkAttachmentDefinition(cinderTest.StorageNAD)
+			DeferCleanup(th.DeleteInstance, nad)
+			spec := GetDefaultCinderSpec()
+			apiSpec := GetDefaultCinderAPISpec()
+			apiSpec["override"] = map[string]interface{}{
+				"ipFamilies": map[string]interface{}{
+					"internal": []string{"IPv6"},
+					"public":   []string{"IPv6"},
+				},
+			}
+			spec["cinderAPI"] = apiSpec
+			volumeSpec := GetDefaultCinderVolumeSpec()
+			volumeSpec["networkAttachments"] = []string{cinderTest.StorageNAD.Name}
+			volumeSpec["storageNetwork"] = cinderTest.StorageNAD.Name
+			spec["cinderVolumes"] = map[string]interface{}{
+				"volume1": volumeSpec,
+			}
+
+			setupCinderDeps(spec)
+			keystone.SimulateKeystoneServiceReady(cinderTest.CinderKeystoneService)
+			keystone.SimulateKeystoneEndpointReady(cinderTest.CinderKeystoneEndpoint)
+		})
+
+		It("creates IPv6 Services and listens on IPv6", func() {
+			for _, svcName := range []types.NamespacedName{
+				cinderTest.CinderServiceInternal, cinderTest.CinderServicePublic,
+			} {
+				svc := th.GetService(svcName)
+				Expect(svc.Spec.IPFamilies).To(Equal([]corev1.IPFamily{corev1.IPv6Protocol}))
+				Expect(svc.Spec.ClusterIP).To(ContainSubstring(":"))
+			}
+
+			cf := th.GetSecret(cinderTest.CinderConfigSecret)
+			Expect(string(cf.Data[cinder.DefaultsConfigFileName])).To(ContainSubstring("osapi_volume_listen = ::\n"))
+			Expect(string(cf.Data["cinder-api-uwsgi.ini"])).To(ContainSubstring("http-socket = [::]:8776"))
+		})
+
+		It("starts the volume service with its address on the storage network", func() {
+			ss := th.GetStatefulSet(cinderTest.CinderVolumes[0])
+			container := ss.Spec.Template.Spec.Containers[0]
+			Expect(container.Args).To(Equal([]string{"-c", cinder.StorageIPScript + " /usr/local/bin/kolla_start"}))
+			Expect(GetEnvVarValue(container.Env, "STORAGE_NETWORK", "")).To(
+				Equal(cinderTest.StorageNAD.Namespace + "/" + cinderTest.StorageNAD.Name))
+			th.AssertVolumeExists("pod-info", ss.Spec.Template.Spec.Volumes)
+			th.AssertVolumeMountExists("pod-info", "", container.VolumeMounts)
+			// The probe doesn't need the address
+			Expect(ss.Spec.Template.Spec.Containers[1].VolumeMounts).NotTo(
+				ContainElement(HaveField("Name", "pod-info")))
+		})
+	})
+	When("Cinder CR instance is built with a storage network", func() {
+		var storageDataNAD types.NamespacedName
+
+		BeforeEach(func() {
+			storageDataNAD = types.NamespacedName{
+				Namespace: cinderTest.Instance.Namespace,
+				Name:      "storage-data",
+			}
+			DeferCleanup(th.DeleteInstance, th.CreateNetworkAttachmentDefinition(cinderTest.StorageNAD))
+			DeferCleanup(th.DeleteInstance, th.CreateNetworkAttachmentDefinition(storageDataNAD))
+			spec := GetDefaultCinderSpec()
+			volumeSpec := GetDefaultCinderVolumeSpec()
+			volumeSpec["networkAttachments"] = []string{cinderTest.StorageNAD.Name, storageDataNAD.Name}
+			volumeSpec["storageNetwork"] = storageDataNAD.Name
+			spec["cinderVolumes"] = map[string]interface{}{
+				"volume1": volumeSpec,
+			}
+
+			setupCinderDeps(spec)
+			keystone.SimulateKeystoneServiceReady(cinderTest.CinderKeystoneService)
+			keystone.SimulateKeystoneEndpointReady(cinderTest.CinderKeystoneEndpoint)
+		})
+
+		It("starts the volume service with its address on the selected network", func() {
+			Eventually(func(g Gomega) {
+				volume := GetCinderVolume(cinderTest.CinderVolumes[0])
+				g.Expect(volume.Spec.StorageNetwork).To(Equal(storageDataNAD.Name))
+			}, timeout, interval).Should(Succeed())
+			ss := th.GetStatefulSet(cinderTest.CinderVolumes[0])
+			container := ss.Spec.Template.Spec.Containers[0]
+			Expect(container.Args).To(Equal([]string{"-c", cinder.StorageIPScript + " /usr/local/bin/kolla_start"}))
+			Expect(GetEnvVarValue(container.Env, "STORAGE_NETWORK", "")).To(
+				Equal(storageDataNAD.Namespace + "/" + storageDataNAD.Name))
+			th.AssertVolumeMountExists("pod-info", "", container.VolumeMounts)
+		})
+	})
+	When("Cinder CR instance is attached to the storage network without selecting it", func() {
+		BeforeEach(func() {
+			DeferCleanup(th.DeleteInstance, th.CreateNetworkAttachmentDefinition(cinderTest.StorageNAD))
+			spec := GetDefaultCinderSpec()
+			volumeSpec := GetDefaultCinderVolumeSpec()
+			volumeSpec["networkAttachments"] = []string{cinderTest.StorageNAD.Name}
+			spec["cinderVolumes"] = map[string]interface{}{
+				"volume1": volumeSpec,
+			}
+
+			setupCinderDeps(spec)
+			keystone.SimulateKeystoneServiceReady(cinderTest.CinderKeystoneService)
+			keystone.SimulateKeystoneEndpointReady(cinderTest.CinderKeystoneEndpoint)
+		})
+
+		It("starts the volume service with the my_ip of its config", func() {
+			ss := th.GetStatefulSet(cinderTest.CinderVolumes[0])
+			container := ss.Spec.Template.Spec.Containers[0]
+			Expect(container.Args).ToNot(ContainElement(ContainSubstring(cinder.StorageIPScript)))
+			Expect(GetEnvVarValue(container.Env, "STORAGE_NETWORK", "")).To(BeEmpty())
+			Expect(container.VolumeMounts).NotTo(ContainElement(HaveField("Name", "pod-info")))
+		})
+	})
+	When("Cinder CR instance is built with the internal endpoint on its own network", func() {
+		BeforeEach(func() {
+			DeferCleanup(th.DeleteInstance, th.CreateNetworkAttachmentDefinition(cinderTest.InternalAPINAD))
+			spec := GetDefaultCinderSpec()
+			apiSpec := GetDefaultCinderAPISpec()
+			apiSpec["networkAttachments"] = []string{cinderTest.InternalAPINAD.Name}
+			apiSpec["endpointNetworks"] = map[string]interface{}{
+				"internal": cinderTest.InternalAPINAD.Name,
+			}
+			spec["cinderAPI"] = apiSpec
+
+			setupCinderDeps(spec)
+			keystone.SimulateKeystoneServiceReady(cinderTest.CinderKeystoneService)
+			keystone.SimulateKeystoneEndpointReady(cinderTest.CinderKeystoneEndpoint)
+		})
+
+		It("serves the internal endpoint on its own port and on the internalapi network", func() {
+			wsgi := string(th.GetSecret(cinderTest.CinderConfigSecret).Data["10-cinder_wsgi.conf"])
+			Expect(wsgi).To(ContainSubstring(
+				"Listen 8777\n" +
+					"Listen 8776\n" +
+					"<VirtualHost ${POD_ADDRESSES_8777} ${INTERNAL_ADDRESS}:8776>\n" +
+					"  ServerName cinder-internal."))
+			Expect(wsgi).To(ContainSubstring(
+				"<VirtualHost ${POD_ADDRESSES_8776}>\n" +
+					"  ServerName cinder-public."))
+			Expect(strings.Count(wsgi, "Listen ")).To(Equal(2))
+
+			// The public Service doesn't reach the internal vhost
+			internalSvc := th.GetService(cinderTest.CinderServiceInternal)
+			Expect(internalSvc.Spec.Ports[0].Port).To(Equal(cinder.CinderInternalPort))
+			Expect(internalSvc.Spec.Ports[0].TargetPort.IntVal).To(Equal(cinderapi.InternalTargetPort))
+			publicSvc := th.GetService(cinderTest.CinderServicePublic)
+			Expect(publicSvc.Spec.Ports[0].TargetPort.IntVal).To(Equal(cinder.CinderPublicPort))
+
+			ss := th.GetStatefulSet(cinderTest.CinderAPI)
+			container := ss.Spec.Template.Spec.Containers[1]
+			Expect(container.Args).To(Equal([]string{"-c", cinderapi.EndpointAddressesScript + " /usr/local/bin/kolla_start"}))
+			Expect(GetEnvVarValue(container.Env, "INTERNAL_NETWORK", "")).To(
+				Equal(cinderTest.InternalAPINAD.Namespace + "/" + cinderTest.InternalAPINAD.Name))
+			Expect(container.Env).To(ContainElement(And(
+				HaveField("Name", "POD_IPS"),
+				HaveField("ValueFrom.FieldRef.FieldPath", "status.podIPs"))))
+			Expect(GetEnvVarValue(container.Env, "POD_PORTS", "")).To(Equal("8777 8776"))
+			Expect(GetEnvVarValue(container.Env, "PUBLIC_NETWORK", "")).To(BeEmpty())
+			th.AssertVolumeMountExists("pod-info", "", container.VolumeMounts)
+		})
+	})
+	When("Cinder CR instance is built with keystone registration options", func() {
+		BeforeEach(func() {
+			spec := GetDefaultCinderSpec()
+			apiSpec := GetDefaultCinderAPISpec()
+			apiSpec["keystone"] = map[string]interface{}{
+				"description":  "Block Storage",
+				"enabled":      false,
+				"path":         "/v3/%(project_id)s",
+				"blockStorage": true,
+			}
+			spec["cinderAPI"] = apiSpec
+
+			setupCinderDeps(spec)
+		})
+
+		It("registers the volumev3 service and its block-storage alias", func() {
+			keystoneService := keystone.GetKeystoneService(cinderTest.CinderKeystoneService)
+			Expect(keystoneService.Spec.ServiceType).To(Equal(cinder.ServiceTypeV3))
+			Expect(keystoneService.Spec.ServiceDescription).To(Equal("Block Storage"))
+			Expect(keystoneService.Spec.Enabled).To(BeFalse())
+			keystone.SimulateKeystoneServiceReady(cinderTest.CinderKeystoneService)
+
+			keystoneEndpoint := keystone.GetKeystoneEndpoint(cinderTest.CinderKeystoneEndpoint)
+			Expect(keystoneEndpoint.Spec.Endpoints).To(HaveKeyWithValue(
+				"internal", "http://cinder-internal."+cinderTest.Instance.Namespace+".svc:8776/v3/%(project_id)s"))
+			keystone.SimulateKeystoneEndpointReady(cinderTest.CinderKeystoneEndpoint)
+
+			aliasService := keystone.GetKeystoneService(cinderTest.CinderKeystoneAlias)
+			Expect(aliasService.Spec.ServiceType).To(Equal(cinder.ServiceTypeBlockStorage))
+			Expect(aliasService.Spec.ServiceDescription).To(Equal("Block Storage"))
+			keystone.SimulateKeystoneServiceReady(cinderTest.CinderKeystoneAlias)
+
+			aliasEndpoint := keystone.GetKeystoneEndpoint(cinderTest.CinderKeystoneAlias)
+			Expect(aliasEndpoint.Spec.Endpoints).To(HaveKeyWithValue(
+				"internal", "http://cinder-internal."+cinderTest.Instance.Namespace+".svc:8776/v3"))
+		})
+	})
+	When("Cinder CR instance is built without keystone registration", func() {
+		BeforeEach(func() {
+			spec := GetDefaultCinderSpec()
+			apiSpec := GetDefaultCinderAPISpec()
+			apiSpec["keystone"] = map[string]interface{}{
+				"register": false,
+			}
+			spec["cinderAPI"] = apiSpec
+
+			setupCinderDeps(spec)
+		})
+
+		It("deploys the API without catalog entries", func() {
+			Eventually(func(g Gomega) {
+				api := GetCinderAPI(cinderTest.CinderAPI)
+				g.Expect(api.Status.APIEndpoints).To(HaveKey(cinder.ServiceNameV3))
+				g.Expect(api.Status.Conditions.Has(condition.KeystoneServiceReadyCondition)).To(BeFalse())
+				g.Expect(api.Status.Conditions.Has(condition.KeystoneEndpointReadyCondition)).To(BeFalse())
+			}, timeout, interval).Should(Succeed())
+			th.GetStatefulSet(cinderTest.CinderAPI)
+			keystone.AssertKeystoneServiceDoesNotExist(cinderTest.CinderKeystoneService)
+			keystone.AssertKeystoneEndpointDoesNotExist(cinderTest.CinderKeystoneEndpoint)
+		})
+
+		It("still validates the tokens with Keystone", func() {
+			conf := string(th.GetSecret(cinderTest.CinderConfigSecret).Data["00-global-defaults.conf"])
+			Expect(conf).To(ContainSubstring("auth_strategy = keystone\n"))
+			Expect(conf).To(ContainSubstring("[keystone_authtoken]"))
+		})
+	})
+	When("Cinder CR instance is built with the noauth strategy", func() {
+		BeforeEach(func() {
+			spec := GetDefaultCinderSpec()
+			apiSpec := GetDefaultCinderAPISpec()
+			apiSpec["authStrategy"] = "noauth"
+			apiSpec["keystone"] = map[string]interface{}{
+				"register": false,
+			}
+			spec["cinderAPI"] = apiSpec
+
+			setupCinderDepsWithoutKeystone(spec)
+		})
+
+		It("deploys the API without a KeystoneAPI", func() {
+			Eventually(func(g Gomega) {
+				api := GetCinderAPI(cinderTest.CinderAPI)
+				g.Expect(api.Status.APIEndpoints).To(HaveKey(cinder.ServiceNameV3))
+			}, timeout, interval).Should(Succeed())
+			th.GetStatefulSet(cinderTest.CinderAPI)
+		})
+
+		It("doesn't validate the tokens with Keystone", func() {
+			conf := string(th.GetSecret(cinderTest.CinderConfigSecret).Data["00-global-defaults.conf"])
+			Expect(conf).To(ContainSubstring("auth_strategy = noauth\n"))
+			Expect(conf).NotTo(ContainSubstring("[keystone_authtoken]"))
+		})
+	})
+	// Run MariaDBAccount suite tests.  these are pre-packaged ginkgo tests
+	// that exercise standard account create / update patterns that should be
+	// common to all controllers that ensure MariaDBAccount CRs.
+	mariadbSuite := &mariadb_test.MariaDBTestHarness{
+		PopulateHarness: func(harness *mariadb_test.MariaDBTestHarness) {
+			harness.Setup(
+				"Cinder",
+				cinderTest.Instance.Namespace,
+				cinder.DatabaseName,
+				"openstack.org/cinder",
+				mariadb,
+				timeout,
+				interval,
+			)
+		},
+		// Generate a fully running Cinder service given an accountName
+		// needs to make it all the way to the end where the mariadb finalizers
+		// are removed from unused accounts since that's part of what we are testing
+		SetupCR: func(accountName types.NamespacedName) {
+			spec := GetTLSCinderSpec()
+			spec["databaseAccount"] = accountName.Name
+
+			DeferCleanup(th.DeleteInstance, CreateCinder(cinderTest.Instance, spec))
+			DeferCleanup(k8sClient.Delete, ctx, CreateCinderMessageBusSecret(cinderTest.Instance.Namespace, cinderTest.RabbitmqSecretName))
+			DeferCleanup(th.DeleteInstance, CreateCinderAPI(cinderTest.Instance, GetDefaultCinderAPISpec()))
+			DeferCleanup(th.DeleteInstance, CreateCinderScheduler(cinderTest.Instance, GetDefaultCinderSchedulerSpec()))
+			DeferCleanup(th.DeleteInstance, CreateCinderVolume(cinderTest.Instance, GetDefaultCinderVolumeSpec()))
+			DeferCleanup(
+				mariadb.DeleteDBService,
+				mariadb.CreateDBService(
+					cinderTest.Instance.Namespace,
+					GetCinder(cinderName).Spec.DatabaseInstance,
+					corev1.ServiceSpec{
+						Ports: []corev1.ServicePort{{Port: 3306}},
+					},
+				),
+			)
+			infra.SimulateTransportURLReady(cinderTest.CinderTransportURL)
+			DeferCleanup(infra.DeleteMemcached, infra.CreateMemcached(namespace, cinderTest.MemcachedInstance, memcachedSpec))
+			infra.SimulateMemcachedReady(cinderTest.CinderMemcached)
+			DeferCleanup(keystone.DeleteKeystoneAPI, keystone.CreateKeystoneAPI(cinderTest.Instance.Namespace))
+			mariadb.SimulateMariaDBAccountCompleted(accountName)
+			mariadb.SimulateMariaDBDatabaseCompleted(cinderTest.Database)
+			th.SimulateJobSuccess(cinderTest.CinderDBSync)
+
+			DeferCleanup(k8sClient.Delete, ctx, th.CreateCABundleSecret(cinderTest.CABundleSecret))
+			DeferCleanup(k8sClient.Delete, ctx, th.CreateCertSecret(cinderTest.InternalCertSecret))
+			DeferCleanup(k8sClient.Delete, ctx, th.CreateCertSecret(cinderTest.PublicCertSecret))
+			keystone.SimulateKeystoneServiceReady(cinderTest.CinderKeystoneService)
+			keystone.SimulateKeystoneEndpointReady(cinderTest.CinderKeystoneEndpoint)
+
+			th.AssertServiceExists(cinderTest.CinderServicePublic)
+			th.AssertServiceExists(cinderTest.CinderServiceInternal)
+
+			// check keystone endpoints
+			keystoneEndpoint := keystone.GetKeystoneEndpoint(cinderTest.CinderKeystoneEndpoint)
+			endpoints := keystoneEndpoint.Spec.Endpoints
+			Expect(endpoints).To(HaveKeyWithValue("public", "https://cinder-public."+namespace+".svc:8776/v3"))
+			Expect(endpoints).To(HaveKeyWithValue("internal", "https://cinder-internal."+namespace+".svc:8776/v3"))
+
+		},
+		// Change the account name in the service to a new name
+		UpdateAccount: func(newAccountName types.NamespacedName) {
+
+			Eventually(func(g Gomega) {
+				cinder := GetCinder(cinderName)
+				cinder.Spec.DatabaseAccount = newAccountName.Name
+				g.Expect(th.K8sClient.Update(ctx, cinder)).Should(Succeed())
+			}, timeout, interval).Should(Succeed())
+
+		},
+		// delete the instance to exercise finalizer removal
+		DeleteCR: func() {
+			th.DeleteInstance(GetCinder(cinderName))
+		},
+	}
+
+	mariadbSuite.RunBasicSuite()
+
+})
+
+var _ = Describe("Cinder Webhook", func() {
+
+	BeforeEach(func() {
+		err := os.Setenv("OPERATOR_TEMPLATES", "../../templates")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("rejects with wrong CinderAPI service override endpoint type", func() {
+		spec := GetDefaultCinderSpec()
+		apiSpec := GetDefaultCinderAPISpec()
+		apiSpec["override"] = map[string]interface{}{
+			"service": map[string]interface{}{
+				"internal": map[string]interface{}{},
+				"wrooong":  map[string]interface{}{},
+			},
+		}
+		spec["cinderAPI"] = apiSpec
+
+		raw := map[string]interface{}{
+			"apiVersion": "cinder.openstack.org/v1beta1",
+			"kind":       "Cinder",
+			"metadata": map[string]interface{}{
+				"name":      cinderTest.Instance.Name,
+				"namespace": cinderTest.Instance.Namespace,
+			},
+			"spec": spec,
+		}
+
+		unstructuredObj := &unstructured.Unstructured{Object: raw}
+		_, err := controllerutil.CreateOrPatch(
+			th.Ctx, th.K8sClient, unstructuredObj, func() error { return nil })
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(
+			ContainSubstring(
+				"invalid: spec.cinderAPI.override.service[wrooong]: " +
+					"Invalid value: \"wrooong\": invalid endpoint type: wrooong"),
+		)
+	})
+
+	It("rejects CinderAPI audit records on the notification bus without notifications", func() {
+		spec := GetDefaultCinderSpec()
+		apiSpec := GetDefaultCinderAPISpec()
+		apiSpec["audit"] = map[string]interface{}{}
+		spec["cinderAPI"] = apiSpec
+
+		raw := map[string]interface{}{
+			"apiVersion": "cinder.openstack.org/v1beta1",
+			"kind":       "Cinder",
+			"metadata": map[string]interface{}{
+				"name":      cinderTest.Instance.Name,
+				"namespace": cinderTest.Instance.Namespace,
+			},
+			"spec": spec,
+		}
+
+		unstructuredObj := &unstructured.Unstructured{Object: raw}
+		_, err := controllerutil.CreateOrPatch(
+			th.Ctx, th.K8sClient, unstructuredObj, func() error { return nil })
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(
+			ContainSubstring(
+				"invalid: spec.cinderAPI.audit.output: Invalid value: \"notifications\": " +
+					"audit records can't be sent to the notification bus when notifications are disabled"),
+		)
+	})
+
+	It("rejects CinderAPI policy rules that aren't Cinder policies", func() {
+		spec := GetDefaultCinderSpec()
+		apiSpec := GetDefaultCinderAPISpec()
+		apiSpec["policy"] = map[string]interface{}{
+			"rules": "\"volume:delete\": \"rule:my_admins\"\n" +
+				"\"my_admins\": \"role:admin\"\n" +
+				"\"volume:destroy\": \"rule:admin_api\"\n",
+		}
+		spec["cinderAPI"] = apiSpec
+
+		raw := map[string]interface{}{
+			"apiVersion": "cinder.openstack.org/v1beta1",
+			"kind":       "Cinder",
+			"metadata": map[string]interface{}{
+				"name":      cinderTest.Instance.Name,
+				"namespace": cinderTest.Instance.Namespace,
+			},
+			"spec": spec,
+		}
+
+		unstructuredObj := &unstructured.Unstructured{Object: raw}
+		_, err := controllerutil.CreateOrPatch(
+			th.Ctx, th.K8sClient, unstructuredObj, func() error { return nil })
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(
+			ContainSubstring(
+				"invalid: spec.cinderAPI.policy.rules: Invalid value: \"\": " +
+					"unknown Cinder policies: [volume:destroy]"),
+		)
+	})
+
+	It("rejects uwsgi CinderAPI with TLS only on the public endpoint", func() {
+		spec := GetDefaultCinderSpec()
+		apiSpec := GetDefaultCinderAPISpec()
+		apiSpec["apiServer"] = "uwsgi"
+		apiSpec["tls"] = map[string]interface{}{
+			"api": map[string]interface{}{
+				"public": map[string]interface{}{
+					"secretName": PublicCertSecretName,
+				},
+			},
+		}
+		spec["cinderAPI"] = apiSpec
+
+		raw := map[string]interface{}{
+			"apiVersion": "cinder.openstack.org/v1beta1",
+			"kind":       "Cinder",
+			"metadata": map[string]interface{}{
+				"name":      cinderTest.Instance.Name,
+				"namespace": cinderTest.Instance.Namespace,
+			},
+			"spec": spec,
+		}
+
+		unstructuredObj := &unstructured.Unstructured{Object: raw}
+		_, err := controllerutil.CreateOrPatch(
+			th.Ctx, th.K8sClient, unstructuredObj, func() error { return nil })
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(
+			ContainSubstring(
+				"invalid: spec.cinderAPI.apiServer: Invalid value: \"uwsgi\": " +
+					"uwsgi requires TLS to be enabled on both the internal and public endpoints or on none of them"),
+		)
+	})
+
+	It("rejects a startup probe override on the CinderAPI", func() {
+		spec := GetDefaultCinderSpec()
+		apiSpec := GetDefaultCinderAPISpec()
+		apiSpec["probes"] = map[string]interface{}{
+			"startup": map[string]interface{}{
+				"failureThreshold": 20,
+			},
+		}
+		spec["cinderAPI"] = apiSpec
+
+		raw := map[string]interface{}{
+			"apiVersion": "cinder.openstack.org/v1beta1",
+			"kind":       "Cinder",
+			"metadata": map[string]interface{}{
+				"name":      cinderTest.Instance.Name,
+				"namespace": cinderTest.Instance.Namespace,
+			},
+			"spec": spec,
+		}
+
+		unstructuredObj := &unstructured.Unstructured{Object: raw}
+		_, err := controllerutil.CreateOrPatch(
+			th.Ctx, th.K8sClient, unstructuredObj, func() error { return nil })
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(
+			ContainSubstring(
+				"invalid: spec.cinderAPI.probes.startup: Forbidden: the API has no startup probe"),
+		)
+	})
+
+	It("rejects two IP families with a single-stack policy", func() {
+		spec := GetDefaultCinderSpec()
+		apiSpec := GetDefaultCinderAPISpec()
+		apiSpec["override"] = map[string]interface{}{
+			"service": map[string]interface{}{
+				"internal": map[string]interface{}{
+					"spec": map[string]interface{}{
+						"ipFamilyPolicy": "SingleStack",
+					},
+				},
+			},
+			"ipFamilies": map[string]interface{}{
+				"internal": []string{"IPv6", "IPv4"},
+			},
+		}
+		spec["cinderAPI"] = apiSpec
+
+		raw := map[string]interface{}{
+			"apiVersion": "cinder.openstack.org/v1beta1",
+			"kind":       "Cinder",
+			"metadata": map[string]interface{}{
+				"name":      cinderTest.Instance.Name,
+				"namespace": cinderTest.Instance.Namespace,
+			},
+			"spec": spec,
+		}
+
+		unstructuredObj := &unstructured.Unstructured{Object: raw}
+		_, err := controllerutil.CreateOrPatch(
+			th.Ctx, th.K8sClient, unstructuredObj, func() error { return nil })
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(
+			ContainSubstring(
+				"invalid: spec.cinderAPI.override.service[internal].spec.ipFamilyPolicy: Invalid value: \"SingleStack\": " +
+					"two IP families require a dual-stack ipFamilyPolicy"))
+	})
+
+	It("rejects a storage network that is not attached to the service", func() {
+		spec := GetDefaultCinderSpec()
+		spec["cinderBackup"] = map[string]interface{}{
+			"containerImage":     cinderv1.CinderBackupContainerImage,
+			"networkAttachments": []string{"internalapi"},
+			"storageNetwork":     cinderTest.StorageNAD.Name,
+		}
+
+		raw := map[string]interface{}{
+			"apiVersion": "cinder.openstack.org/v1beta1",
+			"kind":       "Cinder",
+			"metadata": map[string]interface{}{
+				"name":      cinderTest.Instance.Name,
+				"namespace": cinderTest.Instance.Namespace,
+			},
+			"spec": spec,
+		}
+
+		unstructuredObj := &unstructured.Unstructured{Object: raw}
+		_, err := controllerutil.CreateOrPatch(
+			th.Ctx, th.K8sClient, unstructuredObj, func() error { return nil })
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(
+			ContainSubstring(
+				"invalid: spec.cinderBackup.storageNetwork: Invalid value: \"storage\": " +
+					"must be one of the networkAttachments of the service"))
+	})
+
+	It("rejects an endpoint network that is not attached to the API", func() {
+		spec := GetDefaultCinderSpec()
+		apiSpec := GetDefaultCinderAPISpec()
+		apiSpec["networkAttachments"] = []string{"internalapi"}
+		apiSpec["endpointNetworks"] = map[string]interface{}{
+			"public": "external",
+		}
+		spec["cinderAPI"] = apiSpec
+
+		raw := map[string]interface{}{
+			"apiVersion": "cinder.openstack.org/v1beta1",
+			"kind":       "Cinder",
+			"metadata": map[string]interface{}{
+				"name":      cinderTest.Instance.Name,
+				"namespace": cinderTest.Instance.Namespace,
+			},
+			"spec": spec,
+		}
+
+		unstructuredObj := &unstructured.Unstructured{Object: raw}
+		_, err := controllerutil.CreateOrPatch(
+			th.Ctx, th.K8sClient, unstructuredObj, func() error { return nil })
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(
+			ContainSubstring(
+				"invalid: spec.cinderAPI.endpointNetworks[public]: Invalid value: \"external\": " +
+					"must be one of the networkAttachments of the service"))
+	})
+
+	It("rejects the endpoints on the same network", func() {
+		spec := GetDefaultCinderSpec()
+		apiSpec := GetDefaultCinderAPISpec()
+		apiSpec["networkAttachments"] = []string{"internalapi"}
+		apiSpec["endpointNetworks"] = map[string]interface{}{
+			"internal": "internalapi",
+			"public":   "internalapi",
+		}
+		spec["cinderAPI"] = apiSpec
+
+		raw := map[string]interface{}{
+			"apiVersion": "cinder.openstack.org/v1beta1",
+			"kind":       "Cinder",
+			"metadata": map[string]interface{}{
+				"name":      cinderTest.Instance.Name,
+				"namespace": cinderTest.Instance.Namespace,
+			},
+			"spec": spec,
+		}
+
+		unstructuredObj := &unstructured.Unstructured{Object: raw}
+		_, err := controllerutil.CreateOrPatch(
+			th.Ctx, th.K8sClient, unstructuredObj, func() error { return nil })
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(
+			ContainSubstring(
+				"invalid: spec.cinderAPI.endpointNetworks[public]: Invalid value: \"internalapi\": " +
+					"the public and internal endpoints can't be served on the same network"))
+	})
+
+	It("rejects the noauth strategy on an API registered in Keystone", func() {
+		spec := GetDefaultCinderSpec()
+		apiSpec := GetDefaultCinderAPISpec()
+		apiSpec["authStrategy"] = "noauth"
+		spec["cinderAPI"] = apiSpec
+
+		raw := map[string]interface{}{
+			"apiVersion": "cinder.openstack.org/v1beta1",
+			"kind":       "Cinder",
+			"metadata": map[string]interface{}{
+				"name":      cinderTest.Instance.Name,
+				"namespace": cinderTest.Instance.Namespace,
+			},
+			"spec": spec,
+		}
+
+		unstructuredObj := &unstructured.Unstructured{Object: raw}
+		_, err := controllerutil.CreateOrPatch(
+			th.Ctx, th.K8sClient, unstructuredObj, func() error { return nil })
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(
+			ContainSubstring(
+				"invalid: spec.cinderAPI.authStrategy: Invalid value: \"noauth\": " +
+					"noauth requires keystone.register to be false"))
+	})
+
+	It("rejects an address pool that is not on the network of the endpoint", func() {
+		spec := GetDefaultCinderSpec()
+		apiSpec := GetDefaultCinderAPISpec()
+		apiSpec["networkAttachments"] = []string{"internalapi"}
+		apiSpec["endpointNetworks"] = map[string]interface{}{
+			"internal": "internalapi",
+		}
+		apiSpec["override"] = map[string]interface{}{
+			"service": map[string]interface{}{
+				"internal": map[string]interface{}{
+					"metadata": map[string]interface{}{
+						"annotations": map[string]interface{}{
+							"metallb.universe.tf/address-pool": "ctlplane",
+						},
+					},
+				},
+			},
+		}
+		spec["cinderAPI"] = apiSpec
+
+		raw := map[string]interface{}{
+			"apiVersion": "cinder.openstack.org/v1beta1",
+			"kind":       "Cinder",
+			"metadata": map[string]interface{}{
+				"name":      cinderTest.Instance.Name,
+				"namespace": cinderTest.Instance.Namespace,
+			},
+			"spec": spec,
+		}
+
+		unstructuredObj := &unstructured.Unstructured{Object: raw}
+		_, err := controllerutil.CreateOrPatch(
+			th.Ctx, th.K8sClient, unstructuredObj, func() error { return nil })
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(
+			ContainSubstring(
+				"invalid: spec.cinderAPI.override.service[internal].metadata.annotations[metallb.universe.tf/address-pool]: " +
+					"Invalid value: \"ctlplane\": must be the network the endpoint is served on: internalapi"))
+	})
+
+	It("rejects client certificates on an internal endpoint without TLS", func() {
+		spec := GetDefaultCinderSpec()
+		apiSpec := GetDefaultCinderAPISpec()
+		apiSpec["internalClientAuth"] = map[string]interface{}{
+			"caBundleSecretName": CABundleSecretName,
+		}
+		spec["cinderAPI"] = apiSpec
+
+		raw := map[string]interface{}{
+			"apiVersion": "cinder.openstack.org/v1beta1",
+			"kind":       "Cinder",
+			"metadata": map[string]interface{}{
+				"name":      cinderTest.Instance.Name,
+				"namespace": cinderTest.Instance.Namespace,
+			},
+			"spec": spec,
+		}
+
+		unstructuredObj := &unstructured.Unstructured{Object: raw}
+		_, err := controllerutil.CreateOrPatch(
+			th.Ctx, th.K8sClient, unstructuredObj, func() error { return nil })
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(
+			ContainSubstring(
+				"invalid: spec.cinderAPI.internalClientAuth: Invalid value: \"" + CABundleSecretName + "\": " +
+					"client certificates require TLS on the internal endpoint"),
+		)
+	})
+
+	It("rejects a failover to an unknown replication target", func() {
+		spec := GetDefaultCinderSpec()
+		volumeSpec := GetDefaultCinderVolumeSpec()
+		volumeSpec["replication"] = map[string]interface{}{
+			"devices": []interface{}{
+				map[string]interface{}{
+					"backendID": "secondary",
+					"options": map[string]interface{}{
+						"san_ip": "192.168.1.2",
+					},
+				},
+			},
+		}
+		volumeSpec["failover"] = "tertiary"
+		spec["cinderVolumes"] = map[string]interface{}{
+			"volume1": volumeSpec,
+		}
+
+		raw := map[string]interface{}{
+			"apiVersion": "cinder.openstack.org/v1beta1",
+			"kind":       "Cinder",
+			"metadata": map[string]interface{}{
+				"name":      cinderTest.Instance.Name,
+				"namespace": cinderTest.Instance.Namespace,
+			},
+			"spec": spec,
+		}
+
+		unstructuredObj := &unstructured.Unstructured{Object: raw}
+		_, err := controllerutil.CreateOrPatch(
+			th.Ctx, th.K8sClient, unstructuredObj, func() error { return nil })
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(
+			ContainSubstring(
+				"spec.cinderVolumes[volume1].failover: Invalid value: \"tertiary\": " +
+					"must be \"primary\" or the backendID of a replication device"),
+		)
+	})
+
+	It("rejects replication options that would break the replication device", func() {
+		spec := GetDefaultCinderSpec()
+		volumeSpec := GetDefaultCinderVolumeSpec()
+		volumeSpec["replication"] = map[string]interface{}{
+			"devices": []interface{}{
+				map[string]interface{}{
+					"backendID": "secondary",
+					"options": map[string]interface{}{
+						"san_ip":   "fd00::2",
+						"srp":      "SRP_1,SRP_2",
+						"pool:id":  "1",
+						"san_user": "admin\n[DEFAULT]",
+					},
+				},
+			},
+		}
+		spec["cinderVolumes"] = map[string]interface{}{
+			"volume1": volumeSpec,
+		}
+
+		raw := map[string]interface{}{
+			"apiVersion": "cinder.openstack.org/v1beta1",
+			"kind":       "Cinder",
+			"metadata": map[string]interface{}{
+				"name":      cinderTest.Instance.Name,
+				"namespace": cinderTest.Instance.Namespace,
+			},
+			"spec": spec,
+		}
+
+		unstructuredObj := &unstructured.Unstructured{Object: raw}
+		_, err := controllerutil.CreateOrPatch(
+			th.Ctx, th.K8sClient, unstructuredObj, func() error { return nil })
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(
+			ContainSubstring(
+				"spec.cinderVolumes[volume1].replication.devices[0].options: Invalid value: \"pool:id\": " +
+					"keys must not contain commas, colons or line breaks"))
+		Expect(err.Error()).To(
+			ContainSubstring(
+				"spec.cinderVolumes[volume1].replication.devices[0].options[srp]: Invalid value: \"SRP_1,SRP_2\": " +
+					"must not contain commas or line breaks"))
+		Expect(err.Error()).To(
+			ContainSubstring("spec.cinderVolumes[volume1].replication.devices[0].options[san_user]"))
+		Expect(err.Error()).NotTo(ContainSubstring("options[san_ip]"))
+	})
+
+	It("rejects an LVM backend with both devices and a loop file", func() {
+		spec := GetDefaultCinderSpec()
+		volumeSpec := GetDefaultCinderVolumeSpec()
+		volumeSpec["lvm"] = map[string]interface{}{
+			"nodeName":     "worker-0",
+			"devices":      []interface{}{"/dev/vdb"},
+			"loopFileSize": "10Gi",
+		}
+		spec["cinderVolumes"] = map[string]interface{}{
+			"volume1": volumeSpec,
+		}
+
+		raw := map[string]interface{}{
+			"apiVersion": "cinder.openstack.org/v1beta1",
+			"kind":       "Cinder",
+			"metadata": map[string]interface{}{
+				"name":      cinderTest.Instance.Name,
+				"namespace": cinderTest.Instance.Namespace,
+			},
+			"spec": spec,
+		}
+
+		unstructuredObj := &unstructured.Unstructured{Object: raw}
+		_, err := controllerutil.CreateOrPatch(
+			th.Ctx, th.K8sClient, unstructuredObj, func() error { return nil })
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(
+			ContainSubstring(
+				"spec.cinderVolumes[volume1].lvm.loopFileSize: Forbidden: " +
+					"devices and loopFileSize are mutually exclusive"),
+		)
+	})
+
+	It("rejects an LVM volume group name that is a path", func() {
+		spec := GetDefaultCinderSpec()
+		volumeSpec := GetDefaultCinderVolumeSpec()
+		volumeSpec["lvm"] = map[string]interface{}{
+			"nodeName":     "worker-0",
+			"volumeGroup":  "../../etc/x",
+			"loopFileSize": "10Gi",
+		}
+		spec["cinderVolumes"] = map[string]interface{}{
+			"volume1": volumeSpec,
+		}
+
+		raw := map[string]interface{}{
+			"apiVersion": "cinder.openstack.org/v1beta1",
+			"kind":       "Cinder",
+			"metadata": map[string]interface{}{
+				"name":      cinderTest.Instance.Name,
+				"namespace": cinderTest.Instance.Namespace,
+			},
+			"spec": spec,
+		}
+
+		unstructuredObj := &unstructured.Unstructured{Object: raw}
+		_, err := controllerutil.CreateOrPatch(
+			th.Ctx, th.K8sClient, unstructuredObj, func() error { return nil })
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(
+			ContainSubstring("lvm.volumeGroup: Invalid value: \"../../etc/x\""))
+	})
+
+	It("rejects the LVM volume group named ..", func() {
+		spec := GetDefaultCinderSpec()
+		volumeSpec := GetDefaultCinderVolumeSpec()
+		volumeSpec["lvm"] = map[string]interface{}{
+			"nodeName":     "worker-0",
+			"volumeGroup":  "..",
+			"loopFileSize": "10Gi",
+		}
+		spec["cinderVolumes"] = map[string]interface{}{
+			"volume1": volumeSpec,
+		}
+
+		raw := map[string]interface{}{
+			"apiVersion": "cinder.openstack.org/v1beta1",
+			"kind":       "Cinder",
+			"metadata": map[string]interface{}{
+				"name":      cinderTest.Instance.Name,
+				"namespace": cinderTest.Instance.Namespace,
+			},
+			"spec": spec,
+		}
+
+		unstructuredObj := &unstructured.Unstructured{Object: raw}
+		_, err := controllerutil.CreateOrPatch(
+			th.Ctx, th.K8sClient, unstructuredObj, func() error { return nil })
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(
+			ContainSubstring(
+				"spec.cinderVolumes[volume1].lvm.volumeGroup: Invalid value: \"..\": " +
+					"is not a valid volume group name"))
+	})
+
+	It("rejects an availability zone different from the zone of the nodeSelector", func() {
+		spec := GetDefaultCinderSpec()
+		volumeSpec := GetDefaultCinderVolumeSpec()
+		volumeSpec["nodeSelector"] = map[string]interface{}{
+			corev1.LabelTopologyZone: "az1",
+		}
+		volumeSpec["availabilityZone"] = map[string]interface{}{
+			"name": "az2",
+		}
+		spec["cinderVolumes"] = map[string]interface{}{
+			"volume1": volumeSpec,
+		}
+
+		raw := map[string]interface{}{
+			"apiVersion": "cinder.openstack.org/v1beta1",
+			"kind":       "Cinder",
+			"metadata": map[string]interface{}{
+				"name":      cinderTest.Instance.Name,
+				"namespace": cinderTest.Instance.Namespace,
+			},
+			"spec": spec,
+		}
+
+		unstructuredObj := &unstructured.Unstructured{Object: raw}
+		_, err := controllerutil.CreateOrPatch(
+			th.Ctx, th.K8sClient, unstructuredObj, func() error { return nil })
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(
+			ContainSubstring(
+				"spec.cinderVolumes[volume1].availabilityZone.name: Invalid value: \"az2\": " +
+					"nodeSelector places the service in zone az1"),
+		)
+	})
+
+	It("rejects a scheduler policy with an unknown filter", func() {
+		spec := GetDefaultCinderSpec()
+		spec["cinderScheduler"] = map[string]interface{}{
+			"containerImage": cinderv1.CinderSchedulerContainerImage,
+			"schedulerPolicy": map[string]interface{}{
+				"filters": []interface{}{"CapacityFilter", "RamFilter"},
+			},
+		}
+
+		raw := map[string]interface{}{
+			"apiVersion": "cinder.openstack.org/v1beta1",
+			"kind":       "Cinder",
+			"metadata": map[string]interface{}{
+				"name":      cinderTest.Instance.Name,
+				"namespace": cinderTest.Instance.Namespace,
+			},
+			"spec": spec,
+		}
+
+		unstructuredObj := &unstructured.Unstructured{Object: raw}
+		_, err := controllerutil.CreateOrPatch(
+			th.Ctx, th.K8sClient, unstructuredObj, func() error { return nil })
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(
+			ContainSubstring(
+				"spec.cinderScheduler.schedulerPolicy.filters[1]: Unsupported value: \"RamFilter\""),
+		)
+	})
+
+	It("rejects a goodness function that would break the config", func() {
+		spec := GetDefaultCinderSpec()
+		spec["cinderVolumes"] = map[string]interface{}{
+			"volume1": GetDefaultCinderVolumeSpec(),
+		}
+		spec["cinderScheduler"] = map[string]interface{}{
+			"containerImage": cinderv1.CinderSchedulerContainerImage,
+			"schedulerPolicy": map[string]interface{}{
+				"weighers": []interface{}{"GoodnessWeigher"},
+				"backends": map[string]interface{}{
+					"volume1": map[string]interface{}{
+						"goodnessFunction": "100\"\n[DEFAULT]\ndebug = true",
+					},
+				},
+			},
+		}
+
+		raw := map[string]interface{}{
+			"apiVersion": "cinder.openstack.org/v1beta1",
+			"kind":       "Cinder",
+			"metadata": map[string]interface{}{
+				"name":      cinderTest.Instance.Name,
+				"namespace": cinderTest.Instance.Namespace,
+			},
+			"spec": spec,
+		}
+
+		unstructuredObj := &unstructured.Unstructured{Object: raw}
+		_, err := controllerutil.CreateOrPatch(
+			th.Ctx, th.K8sClient, unstructuredObj, func() error { return nil })
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(
+			ContainSubstring(
+				"spec.cinderScheduler.schedulerPolicy.backends[volume1].goodnessFunction"))
+		Expect(err.Error()).To(
+			ContainSubstring("must not contain double quotes or line breaks"))
 	})
 
 	It("webhooks reject the request - cinderVolume key too long", func() {
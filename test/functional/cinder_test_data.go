@@ -52,12 +52,14 @@ type CinderTestData struct {
 	CinderRole             types.NamespacedName
 	CinderRoleBinding      types.NamespacedName
 	CinderTransportURL     types.NamespacedName
+	CinderNotificationURL  types.NamespacedName
 	CinderMemcached        types.NamespacedName
 	CinderSA               types.NamespacedName
 	CinderDBSync           types.NamespacedName
 	CinderDBPurge          types.NamespacedName
 	CinderKeystoneService  types.NamespacedName
 	CinderKeystoneEndpoint types.NamespacedName
+	CinderKeystoneAlias    types.NamespacedName
 	CinderServicePublic    types.NamespacedName
 	CinderServiceInternal  types.NamespacedName
 	CinderConfigSecret     types.NamespacedName
@@ -67,6 +69,7 @@ type CinderTestData struct {
 	CinderScheduler        types.NamespacedName
 	CinderVolumes          []types.NamespacedName
 	InternalAPINAD         types.NamespacedName
+	StorageNAD             types.NamespacedName
 	ContainerImage         string
 	CABundleSecret         types.NamespacedName
 	InternalCertSecret     types.NamespacedName
@@ -129,6 +132,10 @@ func GetCinderTestData(cinderName types.NamespacedName) CinderTestData {
 			Namespace: cinderName.Namespace,
 			Name:      fmt.Sprintf("%s-cinder-transport", cinderName.Name),
 		},
+		CinderNotificationURL: types.NamespacedName{
+			Namespace: cinderName.Namespace,
+			Name:      fmt.Sprintf("%s-cinder-notification-transport", cinderName.Name),
+		},
 		CinderMemcached: types.NamespacedName{
 			Namespace: cinderName.Namespace,
 			Name:      MemcachedInstance,
@@ -158,10 +165,19 @@ func GetCinderTestData(cinderName types.NamespacedName) CinderTestData {
 			Namespace: cinderName.Namespace,
 			Name:      cinder.ServiceNameV3,
 		},
+		// KeystoneService and KeystoneEndpoint of the block-storage alias
+		CinderKeystoneAlias: types.NamespacedName{
+			Namespace: cinderName.Namespace,
+			Name:      cinder.ServiceNameBlockStorage,
+		},
 		InternalAPINAD: types.NamespacedName{
 			Namespace: cinderName.Namespace,
 			Name:      "internalapi",
 		},
+		StorageNAD: types.NamespacedName{
+			Namespace: cinderName.Namespace,
+			Name:      "storage",
+		},
 		RabbitmqClusterName:   "rabbitmq",
 		RabbitmqSecretName:    "rabbitmq-secret",
 		MemcachedInstance:     MemcachedInstance,
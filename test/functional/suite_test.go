@@ -134,6 +134,10 @@ var _ = BeforeSuite(func() {
 		},
 	}
 
+	// Allow creating IPv6 Services
+	testEnv.ControlPlane.GetAPIServer().Configure().Set(
+		"service-cluster-ip-range", "10.0.0.0/24,fd00:10:96::/112")
+
 	// cfg is defined in this file globally.
 	cfg, err = testEnv.Start()
 	Expect(err).NotTo(HaveOccurred())